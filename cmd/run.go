@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"syscall"
 	"time"
@@ -16,17 +17,24 @@ import (
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/health"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/listener"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/sweeper"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	mine_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/mine"
-	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	// Подключение плагинов мерчантов/автовыплат
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/mine"
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
 )
 
 func runCmd() *cobra.Command {
@@ -96,27 +104,73 @@ func runner(cfg *config.Config) (err error) {
 
 	sqlStore := sqlstore.Init(postgres)
 
+	// Точность сумм задается каталогом валют
+	currencies, err := sqlStore.AdminPanel().Currency().GetAll()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, c := range currencies {
+		c.RegisterPrecision()
+	}
+
 	nsqStore := nsqstore.Init(nsq)
 
-	plugins := plugins.InitAppPlugins(
-		mine_plugin.InitMinePlugin(),
-		whitebit_plugin.InitWhitebitPlugin(&cfg.Plugins),
-	)
+	plugins := plugins.InitAppPlugins(&cfg.Plugins)
 
 	logger := utils.InitLogger(sqlStore.AdminPanel().Logs())
 
-	// lsnr := listener.InitListener(
-	// 	sqlStore,
-	// 	nsqStore,
-	// 	plugins,
-	// 	logger,
-	// )
+	sel := accounts.InitSelector(
+		sqlStore,
+		plugins,
+		&cfg.Accounts,
+		logger,
+	)
+
+	lsnr := listener.InitListener(
+		sqlStore,
+		nsqStore,
+		plugins,
+		sel,
+		logger,
+	)
+
+	hc := health.InitChecker(
+		sqlStore,
+		nsqStore,
+		plugins,
+		logger,
+	)
+
+	swpr := sweeper.InitSweeper(
+		sqlStore,
+		nsqStore,
+		logger,
+	)
+
+	rt := rates.InitEngine(
+		sqlStore,
+		redisStore.Rates,
+		nsqStore,
+		logger,
+	)
+
+	rp := reports.InitReports(
+		sqlStore,
+		filepath.Join(cfg.Services.Server.Tmp, "reports"),
+		logger,
+	)
 
 	srv := server.Init(
 		sqlStore,
 		nsqStore,
 		redisStore,
 		plugins,
+		sel,
+		lsnr,
+		hc,
+		rt,
+		rp,
 		logger,
 		cfg,
 	).Create()
@@ -132,16 +186,40 @@ func runner(cfg *config.Config) (err error) {
 		}
 	}()
 
-	// Запуск слушателя транзакций
-	// go func() {
-	// 	if err := lsnr.Listen(ctx, &cfg.Listener); err != nil {
-	// 		logger.NewRecord(&models.LogRecord{
-	// 			Service: AppType.LogTypeServer,
-	// 			Module:  AppType.LogModuleListener,
-	// 			Info:    err.Error(),
-	// 		})
-	// 	}
-	// }()
+	// Запуск слушателя транзакций под наблюдением супервизора
+	lsnrDone := make(chan struct{})
+	go func() {
+		defer close(lsnrDone)
+		lsnr.Supervise(ctx, &cfg.Listener)
+	}()
+
+	// Запуск плановой проверки аккаунтов
+	hcDone := make(chan struct{})
+	go func() {
+		defer close(hcDone)
+		hc.Supervise(ctx, &cfg.Health)
+	}()
+
+	// Запуск очистки просроченных заявок
+	swprDone := make(chan struct{})
+	go func() {
+		defer close(swprDone)
+		swpr.Supervise(ctx, &cfg.Sweeper)
+	}()
+
+	// Запуск расчета курсов направлений
+	rtDone := make(chan struct{})
+	go func() {
+		defer close(rtDone)
+		rt.Supervise(ctx, &cfg.Rates)
+	}()
+
+	// Запуск формирования отчетов
+	rpDone := make(chan struct{})
+	go func() {
+		defer close(rpDone)
+		rp.Supervise(ctx, &cfg.Reports)
+	}()
 
 	<-ctx.Done()
 	stop()
@@ -156,5 +234,12 @@ func runner(cfg *config.Config) (err error) {
 		})
 	}
 
+	// Ожидание завершения текущих итераций фоновых процессов
+	<-lsnrDone
+	<-hcDone
+	<-swprDone
+	<-rtDone
+	<-rpDone
+
 	return nil
 }
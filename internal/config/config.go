@@ -6,6 +6,11 @@ type Config struct {
 	Users    UsersConfig     `toml:"users"`
 	Plugins  PluginsConfig   `toml:"plugins"`
 	Listener ListenerConfig  `toml:"listener"`
+	Sweeper  SweeperConfig   `toml:"sweeper"`
+	Rates    RatesConfig     `toml:"rates"`
+	Reports  ReportsConfig   `toml:"reports"`
+	Accounts AccountsConfig  `toml:"accounts"`
+	Health   HealthConfig    `toml:"health"`
 }
 
 type ServicesConfigs struct {
@@ -54,7 +59,66 @@ type PluginsConfig struct {
 }
 
 type ListenerConfig struct {
+	Interval        int `toml:"INTERVAL"`
+	RestartDelay    int `toml:"RESTART_DELAY"`
+	MaxRestartDelay int `toml:"MAX_RESTART_DELAY"`
+	// Кол-во операций в одном запросе истории аккаунта
+	HistoryPageLimit int `toml:"HISTORY_PAGE_LIMIT"`
+	// Максимальное кол-во запросов истории аккаунта за одну итерацию
+	HistoryMaxPages int `toml:"HISTORY_MAX_PAGES"`
+}
+
+type SweeperConfig struct {
+	// Интервал между проверками в секундах
+	Interval int `toml:"INTERVAL"`
+	// Срок жизни новой заявки в минутах, если
+	// для направления обмена он не задан
+	RequestTTL int `toml:"REQUEST_TTL"`
+	// Время в минутах после освобождения адреса просроченной
+	// заявки, в течение которого адрес не выдается повторно
+	AddressCooldown int `toml:"ADDRESS_COOLDOWN"`
+}
+
+type RatesConfig struct {
+	// Интервал между обновлениями курсов в секундах
+	Interval int `toml:"INTERVAL"`
+	// Время ожидания ответа одного источника в секундах
+	Timeout int `toml:"TIMEOUT"`
+	// Время в секундах, в течение которого курс считается
+	// актуальным, если источники перестали его отдавать
+	TTL int `toml:"TTL"`
+	// Время в секундах, в течение которого публичная
+	// выгрузка курсов отдается без повторного расчета
+	ExportCacheTTL int `toml:"EXPORT_CACHE_TTL"`
+	// Срок хранения истории курсов в днях, 0 - без ограничения
+	HistoryRetention int `toml:"HISTORY_RETENTION"`
+}
+
+type ReportsConfig struct {
+	// Кол-во одновременно формируемых отчетов
+	Workers int `toml:"WORKERS"`
+	// Максимальное время формирования одного отчета в секундах
+	Timeout int `toml:"TIMEOUT"`
+	// Срок хранения готовых отчетов в часах
+	Retention int `toml:"RETENTION"`
+	// Интервал между очистками устаревших отчетов в секундах
+	CleanupInterval int `toml:"CLEANUP_INTERVAL"`
+}
+
+type AccountsConfig struct {
+	// Время в секундах, в течение которого баланс аккаунта
+	// автовыплат используется без повторного запроса
+	BalanceCacheTTL int `toml:"BALANCE_CACHE_TTL"`
+}
+
+type HealthConfig struct {
+	// Интервал между проверками аккаунтов в секундах
 	Interval int `toml:"INTERVAL"`
+	// Максимальное время ответа аккаунта в секундах
+	Timeout int `toml:"TIMEOUT"`
+	// Кол-во неудачных проверок подряд, после которого аккаунт
+	// отключается. 0 - аккаунты не отключаются автоматически
+	MaxFailures int `toml:"MAX_FAILURES"`
 }
 
 func Init() *Config {
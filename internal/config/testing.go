@@ -47,5 +47,41 @@ func InitTestConfig(t *testing.T) *Config {
 		Plugins: PluginsConfig{
 			AesKey: "fn5LyPGTnB18gl24nieHavsmKfKRmvLR",
 		},
+
+		Listener: ListenerConfig{
+			HistoryPageLimit: 100,
+			HistoryMaxPages:  10,
+		},
+
+		Sweeper: SweeperConfig{
+			Interval:        60,
+			RequestTTL:      60,
+			AddressCooldown: 1440,
+		},
+
+		Rates: RatesConfig{
+			Interval:         60,
+			Timeout:          5,
+			TTL:              600,
+			ExportCacheTTL:   30,
+			HistoryRetention: 90,
+		},
+
+		Reports: ReportsConfig{
+			Workers:         2,
+			Timeout:         30,
+			Retention:       24,
+			CleanupInterval: 600,
+		},
+
+		Accounts: AccountsConfig{
+			BalanceCacheTTL: 30,
+		},
+
+		Health: HealthConfig{
+			Interval:    300,
+			Timeout:     5,
+			MaxFailures: 3,
+		},
 	}
 }
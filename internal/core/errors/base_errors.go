@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrFailedToInitializeStruct = errors.New("failed to initialize structure")
+	ErrWorkerStopped            = errors.New("worker stopped unexpectedly")
 )
@@ -6,4 +6,5 @@ var (
 	ErrAlreadyExists    = errors.New("record with the passed parameters already exists")
 	ErrRecordNotFound   = errors.New("record with the passed parameters is not found")
 	ErrInvalidCondition = errors.New("invalid select condition")
+	ErrRecordInUse      = errors.New("record is referenced by other records and cannot be deleted")
 )
@@ -0,0 +1,7 @@
+package cerrors
+
+import "errors"
+
+var (
+	ErrInvalidStatusTransition = errors.New("invalid exchange request status transition")
+)
@@ -6,3 +6,10 @@ var (
 	ErrFailedToGetAllMerchants = errors.New("failed to get list of all merchants")
 	ErrFailedToDecodeParams    = errors.New("failed to decode merchant optional params")
 )
+
+var (
+	ErrNoAutopayoutAccount = errors.New("no active autopayout account available")
+	ErrAutopayoutRejected  = errors.New("autopayout rejected by provider")
+	ErrInvalidPayoutAmount = errors.New("invalid payout amount")
+	ErrNotEnoughBalance    = errors.New("no autopayout account has enough balance for this payout")
+)
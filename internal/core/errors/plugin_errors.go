@@ -0,0 +1,9 @@
+package cerrors
+
+import "errors"
+
+var (
+	ErrPluginNotFound     = errors.New("plugin for this service is not registered")
+	ErrPluginNotSupported = errors.New("operation is not supported by plugin")
+	ErrPayoutDuplicate    = errors.New("payout with this unique id was already accepted by provider")
+)
@@ -12,6 +12,23 @@ var (
 	ErrUnauthorized      = errors.New("unauthorized")
 
 	ErrNoMerchantAutopatout             = errors.New("at the moment there are no connected merchant&autopayout in this direction")
+	ErrDirectionDisabled                = errors.New("at the moment this direction is disabled")
 	ErrMerchantAutopatoutOptionalParams = errors.New("failed to decode optional parameters for merchant&autopayout account")
 	ErrConnectionFailed                 = errors.New("failed connect to merchant&autopayout account")
+
+	ErrRateNotAvailable = errors.New("at the moment there is no actual rate in this direction")
+	ErrFeedTooLarge     = errors.New("rates feed exceeds the maximum allowed size")
+
+	ErrAmountBelowMinimum = errors.New("amount is less than the minimum amount for this direction")
+	ErrAmountAboveMaximum = errors.New("amount is greater than the maximum amount for this direction")
+	ErrNotEnoughReserve   = errors.New("not enough reserve in this direction to pay out this amount")
+
+	ErrCourseCorrectionChanged = errors.New("course correction of this direction has been changed concurrently")
+
+	ErrReportNotReady  = errors.New("report is not ready yet")
+	ErrReportExpired   = errors.New("report file has been removed after the retention period")
+	ErrReportQueueFull = errors.New("report queue is full, try again later")
+
+	ErrCurrencyNotFound   = errors.New("currency is not found in the catalog")
+	ErrClientMemoRequired = errors.New("memo is required to pay out in this currency")
 )
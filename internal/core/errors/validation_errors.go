@@ -3,5 +3,16 @@ package cerrors
 import "errors"
 
 var (
-	ErrValidationIndalidDateFormat = errors.New("invalid date format")
+	ErrValidationIndalidDateFormat   = errors.New("invalid date format")
+	ErrValidationInvalidAmount       = errors.New("amount must be positive and fit the currency precision")
+	ErrValidationInvalidTolerance    = errors.New("tolerance must be between 0 and 100 percent or a non-negative amount")
+	ErrValidationInvalidPeriod       = errors.New("period start must be before its end")
+	ErrValidationTooManyPoints       = errors.New("too many points in period, use a larger interval")
+	ErrValidationInvalidBounds       = errors.New("correction_min must not be greater than correction_max")
+	ErrValidationInvalidReportParams = errors.New("report params must be a json object")
+	ErrValidationInvalidFeedMapping  = errors.New("feed mapping must be a json object with string fields and a one character delimiter")
+	ErrValidationInvalidLimits       = errors.New("amount limits must be non-negative and min_amount must not be greater than max_amount")
+	ErrValidationInvalidReserve      = errors.New("reserve must be a non-negative amount")
+	ErrValidationInvalidMinimums     = errors.New("min_deposit and min_withdraw must be non-negative and fit the currency decimals")
+	ErrValidationInvalidProviders    = errors.New("provider_codes must be keyed by a registered service and contain a ticker or a network")
 )
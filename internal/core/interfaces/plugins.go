@@ -1,19 +1,29 @@
 package interfaces
 
+import "context"
+
+// Опциональные параметры аккаунта мерчанта/автовыплаты.
+// Конкретный тип известен только плагину, который их декодировал.
+type OptionParams interface{}
+
 type PluginI interface {
 	Merchant() MerchantI
 	AutoPayout() AutoPayoutI
 
-	Ping(params interface{}) (interface{}, error)
-	Balance(params, body interface{}) (interface{}, error)
-	History(params, body interface{}) (interface{}, error)
-	GetOptionParams(options string) (interface{}, error)
+	// Проверка соединения с аккаунтом
+	Ping(ctx context.Context, params OptionParams) error
+	// Баланс аккаунта, если ticker пустой — по всем валютам
+	Balance(ctx context.Context, params OptionParams, ticker string) ([]*Balance, error)
+	// История пополнений и выводов аккаунта
+	History(ctx context.Context, params OptionParams, q *HistoryQuery) (*History, error)
+	// Расшифровка опциональных параметров аккаунта
+	GetOptionParams(options string) (OptionParams, error)
 }
 
 type MerchantI interface {
-	CreateAdress(d, params interface{}) (interface{}, error)
+	CreateAddress(ctx context.Context, params OptionParams, r *AddressRequest) (*Address, error)
 }
 
 type AutoPayoutI interface {
-	Payout(params, body interface{}) (interface{}, error)
+	Payout(ctx context.Context, params OptionParams, r *PayoutRequest) (*PayoutResult, error)
 }
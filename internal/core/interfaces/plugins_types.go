@@ -0,0 +1,173 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+)
+
+/*
+	Типы результатов работы плагинов мерчантов/автовыплат.
+
+	Ошибки делятся на два вида:
+	- *ProviderError — платежная система получила запрос и отклонила его.
+	  Временные ошибки (ошибка сервера, превышение частоты запросов) не
+	  означают отказа, результат операции неизвестен;
+	- любая другая ошибка — запрос не дошел до платежной системы
+	  или ответ не удалось прочитать, результат операции неизвестен.
+*/
+
+type Balance struct {
+	Ticker    string         `json:"ticker"`
+	Available AppMoney.Money `json:"available"`
+}
+
+// Тип операции в истории аккаунта
+type HistoryMethod int
+
+const (
+	HistoryDeposit  HistoryMethod = 1
+	HistoryWithdraw HistoryMethod = 2
+)
+
+// Обобщенный статус операции в истории аккаунта
+type HistoryStatus int
+
+const (
+	HistoryStatusPending HistoryStatus = iota
+	HistoryStatusSuccess
+	HistoryStatusFailed
+)
+
+type HistoryQuery struct {
+	Method   HistoryMethod
+	Ticker   string
+	Address  string
+	UniqueID string
+	Limit    int
+	Offset   int
+}
+
+type History struct {
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	Total   int              `json:"total"`
+	Records []*HistoryRecord `json:"records"`
+}
+
+type HistoryRecord struct {
+	Method          HistoryMethod  `json:"method"`
+	Status          HistoryStatus  `json:"status"`
+	ProviderStatus  int            `json:"provider_status"`
+	Ticker          string         `json:"ticker"`
+	Network         string         `json:"network"`
+	Address         string         `json:"address"`
+	Memo            string         `json:"memo"`
+	Amount          AppMoney.Money `json:"amount"`
+	Fee             AppMoney.Money `json:"fee"`
+	TransactionHash string         `json:"transaction_hash"`
+	UniqueID        string         `json:"unique_id"`
+	CreatedAt       int64          `json:"created_at"`
+}
+
+// Валюта из каталога приложения. Плагин сам определяет
+// тикер и сеть валюты в своей платежной системе
+type Currency struct {
+	// Код валюты в приложении, например USDTTRC20
+	Code         string
+	Ticker       string
+	Network      string
+	MemoRequired bool
+
+	// Тикеры и сети валюты в платежных системах по названию
+	// сервиса, если они отличаются от Ticker и Network
+	Providers map[string]ProviderCurrency
+}
+
+type ProviderCurrency struct {
+	Ticker  string `json:"ticker"`
+	Network string `json:"network"`
+}
+
+// Метод возвращает тикер и сеть валюты в платежной системе service
+func (c *Currency) Provider(service string) ProviderCurrency {
+	p := ProviderCurrency{Ticker: c.Ticker, Network: c.Network}
+	if v, ok := c.Providers[service]; ok {
+		if v.Ticker != "" {
+			p.Ticker = v.Ticker
+		}
+
+		if v.Network != "" {
+			p.Network = v.Network
+		}
+	}
+
+	return p
+}
+
+type AddressRequest struct {
+	Currency *Currency
+}
+
+type Address struct {
+	Address string `json:"address"`
+	Memo    string `json:"memo,omitempty"`
+}
+
+type PayoutRequest struct {
+	Currency *Currency
+	Address  string
+	Memo     string
+	Amount   AppMoney.Money
+
+	// Ключ идемпотентности, повторный запрос с тем же
+	// ключом не приводит к повторной отправке средств
+	UniqueID string
+}
+
+type PayoutResult struct {
+	Raw []byte `json:"-"`
+}
+
+// Ошибка которую вернула платежная система
+type ProviderError struct {
+	StatusCode int                 `json:"status_code"`
+	Code       int                 `json:"code"`
+	Message    string              `json:"message"`
+	Errors     map[string][]string `json:"errors"`
+	Raw        []byte              `json:"-"`
+}
+
+func (e *ProviderError) Error() string {
+	arr := []string{}
+	for field, msgs := range e.Errors {
+		arr = append(arr, fmt.Sprintf("%s: %s", field, strings.Join(msgs, ", ")))
+	}
+
+	if len(arr) > 0 {
+		return fmt.Sprintf("provider error %d: %s (%s)", e.Code, e.Message, strings.Join(arr, "; "))
+	}
+
+	return fmt.Sprintf("provider error %d: %s", e.Code, e.Message)
+}
+
+// Метод проверяет, является ли ошибка временной: платежная система
+// не смогла обработать запрос и результат операции неизвестен
+func (e *ProviderError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError ||
+		e.Message == "Too many requests"
+}
+
+// Функция проверяет, является ли err ошибкой платежной системы
+func AsProviderError(err error) (*ProviderError, bool) {
+	var pErr *ProviderError
+	if errors.As(err, &pErr) {
+		return pErr, true
+	}
+
+	return nil, false
+}
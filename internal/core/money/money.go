@@ -0,0 +1,147 @@
+package cmoney
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+/*
+	Денежные суммы приложения.
+
+	Все суммы хранятся и сравниваются как точные десятичные числа,
+	без промежуточного перевода во float64. Количество знаков после
+	запятой зависит от валюты, при выплате сумма округляется вниз
+	до точности валюты, чтобы никогда не отправить больше положенного.
+*/
+
+// Точность валюты, для которой она не задана явно
+const (
+	DefaultPrecision int32 = 8
+	FiatPrecision    int32 = 2
+)
+
+var (
+	mu sync.RWMutex
+
+	// Точность валют по коду в приложении
+	precisions = map[string]int32{
+		"BTC":       8,
+		"ETH":       8,
+		"TRX":       6,
+		"USDT":      6,
+		"USDTTRC20": 6,
+		"USDTERC20": 6,
+		"USDTOMNI":  8,
+	}
+
+	// Фиатные валюты, коды платежных систем в приложении
+	// заканчиваются на код валюты, например SBERRUB
+	fiat = []string{"RUB", "USD", "EUR", "UAH", "KZT"}
+)
+
+type Money struct {
+	decimal.Decimal
+}
+
+var Zero = Money{decimal.Zero}
+
+func New(d decimal.Decimal) Money {
+	return Money{d}
+}
+
+func NewFromInt(i int64) Money {
+	return Money{decimal.NewFromInt(i)}
+}
+
+func NewFromString(s string) (Money, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Zero, err
+	}
+
+	return Money{d}, nil
+}
+
+func RequireFromString(s string) Money {
+	return Money{decimal.RequireFromString(s)}
+}
+
+// Функция задает точность валюты currency
+func SetPrecision(currency string, decimals int32) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	precisions[strings.ToUpper(currency)] = decimals
+}
+
+// Функция возвращает точность валюты currency
+func Precision(currency string) int32 {
+	currency = strings.ToUpper(currency)
+
+	mu.RLock()
+	p, ok := precisions[currency]
+	mu.RUnlock()
+
+	if ok {
+		return p
+	}
+
+	for _, f := range fiat {
+		if strings.HasSuffix(currency, f) {
+			return FiatPrecision
+		}
+	}
+
+	return DefaultPrecision
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{m.Decimal.Add(o.Decimal)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{m.Decimal.Sub(o.Decimal)}
+}
+
+// Умножение суммы на коэффициент, например на курс обмена
+func (m Money) Mul(d decimal.Decimal) Money {
+	return Money{m.Decimal.Mul(d)}
+}
+
+func (m Money) Abs() Money {
+	return Money{m.Decimal.Abs()}
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.Decimal.Equal(o.Decimal)
+}
+
+func (m Money) LessThanOrEqual(o Money) bool {
+	return m.Decimal.LessThanOrEqual(o.Decimal)
+}
+
+// Метод округляет сумму вниз до точности валюты currency
+func (m Money) Round(currency string) Money {
+	return Money{m.Decimal.RoundFloor(Precision(currency))}
+}
+
+// Метод проверяет, что в сумме не больше знаков
+// после запятой, чем допускает валюта currency
+func (m Money) Fits(currency string) bool {
+	return m.Decimal.Equal(m.Decimal.Truncate(Precision(currency)))
+}
+
+// Суммы передаются в JSON числом, как и до
+// перехода на точную десятичную арифметику
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal.String()), nil
+}
+
+// Суммы из внешних XML выгрузок курсов часто
+// содержат пробелы и переводы строк вокруг числа
+func (m *Money) UnmarshalText(text []byte) error {
+	return m.Decimal.UnmarshalText(bytes.TrimSpace(text))
+}
@@ -0,0 +1,62 @@
+package cmoney_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Money_Arithmetic(t *testing.T) {
+	sum := AppMoney.RequireFromString("0.1").Add(AppMoney.RequireFromString("0.2"))
+	assert.True(t, sum.Equal(AppMoney.RequireFromString("0.3")))
+	assert.Equal(t, "0.3", sum.String())
+}
+
+func Test_Money_Precision(t *testing.T) {
+	testCases := []struct {
+		currency string
+		expected int32
+	}{
+		{currency: "BTC", expected: 8},
+		{currency: "USDTTRC20", expected: 6},
+		{currency: "SBERRUB", expected: 2},
+		{currency: "tcsbusd", expected: 2},
+		{currency: "UNKNOWN", expected: AppMoney.DefaultPrecision},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.currency, func(t *testing.T) {
+			assert.Equal(t, tc.expected, AppMoney.Precision(tc.currency))
+		})
+	}
+
+	AppMoney.SetPrecision("XMR", 12)
+	assert.Equal(t, int32(12), AppMoney.Precision("XMR"))
+}
+
+func Test_Money_Round(t *testing.T) {
+	// Выплата никогда не округляется в большую сторону
+	assert.Equal(t, "10.12", AppMoney.RequireFromString("10.129").Round("SBERRUB").String())
+	assert.Equal(t, "0.123456", AppMoney.RequireFromString("0.1234569").Round("USDTTRC20").String())
+
+	assert.True(t, AppMoney.RequireFromString("10.12").Fits("SBERRUB"))
+	assert.False(t, AppMoney.RequireFromString("10.123").Fits("SBERRUB"))
+}
+
+func Test_Money_JSON(t *testing.T) {
+	type payload struct {
+		Amount AppMoney.Money `json:"amount"`
+	}
+
+	b, err := json.Marshal(payload{Amount: AppMoney.RequireFromString("100.5")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":100.5}`, string(b))
+
+	for _, raw := range []string{`{"amount":0.30000000000000004}`, `{"amount":"0.30000000000000004"}`} {
+		var p payload
+		assert.NoError(t, json.Unmarshal([]byte(raw), &p))
+		assert.Equal(t, "0.30000000000000004", p.Amount.String())
+	}
+}
@@ -0,0 +1,33 @@
+package ctypes
+
+// Способы задания допустимого отклонения
+// полученной суммы от ожидаемой
+var (
+	// Отклонение в единицах валюты
+	ToleranceAbsolute = "absolute"
+
+	// Отклонение в процентах от ожидаемой суммы
+	TolerancePercent = "percent"
+)
+
+// Способы задания резерва направления
+var (
+	// Резерв задается менеджером вручную
+	ReserveManual = "manual"
+
+	// Резерв - доступный баланс привязанных аккаунтов автовыплат
+	ReserveAuto = "auto"
+)
+
+// Способы выбора аккаунта среди привязанных к направлению
+var (
+	// Первый аккаунт с наименьшим приоритетом привязки
+	SelectionPriority = "priority"
+
+	// Взвешенный round-robin по весам привязок
+	SelectionWeighted = "weighted"
+
+	// Первый по приоритету аккаунт автовыплат,
+	// баланса которого хватает на выплату
+	SelectionBalance = "balance"
+)
@@ -44,3 +44,65 @@ var (
 	// Выполненная заявка
 	ExchangeRequestDone ExchangeRequestStatus = 700
 )
+
+// Допустимые переходы между статусами заявки.
+// Canceled, Deleted и Done — конечные статусы.
+var exchangeRequestTransitions = map[ExchangeRequestStatus][]ExchangeRequestStatus{
+	ExchangeRequestNew: {
+		ExchangeRequestCanceled,
+		ExchangeRequestDeleted,
+		ExchangeRequestInvalidAmount,
+		ExchangeRequestPaid,
+		ExchangeRequestChecked,
+	},
+	ExchangeRequestInvalidAmount: {
+		ExchangeRequestCanceled,
+		ExchangeRequestPaid,
+		ExchangeRequestChecked,
+	},
+	ExchangeRequestPaid: {
+		ExchangeRequestChecked,
+		ExchangeRequestAutopayoutError,
+		ExchangeRequestAwaitingConfirmation,
+	},
+	ExchangeRequestChecked: {
+		ExchangeRequestCanceled,
+		ExchangeRequestPaid,
+		ExchangeRequestDone,
+	},
+	ExchangeRequestAutopayoutError: {
+		ExchangeRequestCanceled,
+		ExchangeRequestPaid,
+		ExchangeRequestChecked,
+		ExchangeRequestDone,
+	},
+	ExchangeRequestAwaitingConfirmation: {
+		ExchangeRequestAutopayoutError,
+		ExchangeRequestChecked,
+		ExchangeRequestDone,
+	},
+}
+
+// Метод проверяет, может ли заявка перейти из статуса s в статус to
+func (s ExchangeRequestStatus) CanTransitionTo(to ExchangeRequestStatus) bool {
+	for _, allowed := range exchangeRequestTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Метод возвращает список статусов в которые заявка может перейти из статуса s
+func (s ExchangeRequestStatus) Transitions() []ExchangeRequestStatus {
+	return append([]ExchangeRequestStatus{}, exchangeRequestTransitions[s]...)
+}
+
+// Инициаторы смены статуса заявки. Если статус
+// меняет менеджер, записывается его имя пользователя.
+var (
+	ExchangeRequestActorListener = "listener"
+	ExchangeRequestActorBot      = "bot"
+	ExchangeRequestActorSweeper  = "sweeper"
+)
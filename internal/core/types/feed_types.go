@@ -0,0 +1,8 @@
+package ctypes
+
+// Форматы выгрузки курсов источников
+const (
+	FeedFormatXML  = "xml"
+	FeedFormatJSON = "json"
+	FeedFormatCSV  = "csv"
+)
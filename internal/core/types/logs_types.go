@@ -12,4 +12,8 @@ const (
 	LogModuleServer      = "server"
 	LogModuleListener    = "listener"
 	LogModuleDatabase    = "database"
+	LogModuleSweeper     = "sweeper"
+	LogModuleRates       = "rates"
+	LogModuleReports     = "reports"
+	LogModuleHealth      = "health"
 )
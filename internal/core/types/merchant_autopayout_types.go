@@ -18,8 +18,3 @@ var (
 	UseAsMerchant   = 1
 	UseAsAutoPayout = 2
 )
-
-var BaseWhitebitGetHistoryBody = map[string]interface{}{
-	"limit":  100,
-	"offset": 0,
-}
@@ -0,0 +1,21 @@
+package ctypes
+
+type PayoutStatus int
+
+// Возможные статусы попытки автовыплаты
+var (
+	// Попытка зафиксирована в БД, запрос в платежную
+	// систему еще не отправлен или его результат неизвестен
+	PayoutPending PayoutStatus = 100
+
+	// Платежная система приняла запрос на вывод средств
+	PayoutSent PayoutStatus = 200
+
+	// Платежная система отклонила запрос на вывод средств
+	PayoutRejected PayoutStatus = 300
+
+	// Не удалось связаться с платежной системой.
+	// Результат неизвестен, повтор возможен только
+	// через тот же аккаунт и с тем же ключом идемпотентности
+	PayoutFailed PayoutStatus = 400
+)
@@ -0,0 +1,8 @@
+package ctypes
+
+// Шаг прореживания истории курсов
+var (
+	RateHistoryMinute = "minute"
+	RateHistoryHour   = "hour"
+	RateHistoryDay    = "day"
+)
@@ -0,0 +1,15 @@
+package ctypes
+
+// Доступные типы отчетов
+const (
+	ReportTypeExchangerRates = "exchanger_rates"
+)
+
+// Статусы задачи формирования отчета
+const (
+	ReportStatusPending = "pending"
+	ReportStatusRunning = "running"
+	ReportStatusDone    = "done"
+	ReportStatusFailed  = "failed"
+	ReportStatusExpired = "expired"
+)
@@ -17,4 +17,7 @@ var (
 	ResourceNotify             = "Notifications"
 	ResourceUser               = "User"
 	ResourceMerchantAutopayout = "Merchant/Autopayout"
+	ResourceExchangeRequest    = "Exchange request"
+	ResourceReport             = "Report"
+	ResourceCurrency           = "Currency"
 )
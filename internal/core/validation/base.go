@@ -4,6 +4,7 @@ import (
 	"regexp"
 
 	CoreErrors "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -23,3 +24,27 @@ func DateValidation(d string) validation.RuleFunc {
 		return nil
 	}
 }
+
+// Функция для валидации денежной суммы m в валюте currency.
+// Сумма должна быть положительной и не иметь больше знаков
+// после запятой, чем допускает валюта.
+func MoneyValidation(m AppMoney.Money, currency string) validation.RuleFunc {
+	return func(value interface{}) error {
+		if !m.IsPositive() || !m.Fits(currency) {
+			return CoreErrors.ErrValidationInvalidAmount
+		}
+
+		return nil
+	}
+}
+
+// Функция приводит список строк к виду пригодному
+// для передачи в правило validation.In
+func StringsToInterfaces(arr []string) []interface{} {
+	res := make([]interface{}, len(arr))
+	for i, s := range arr {
+		res[i] = s
+	}
+
+	return res
+}
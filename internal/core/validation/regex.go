@@ -5,4 +5,7 @@ const (
 	RegexName = `^[^._ ](?:[\w-]|\.[\w-])+[^._ ]$`
 	RegexCard = `^(?:4[0-9]{12}(?:[0-9]{3})?|5[1-5][0-9]{14}|6(?:011|5[0-9][0-9])[0-9]{12}|3[47][0-9]{13}|3(?:0[0-5]|[68][0-9])[0-9]{11}|(?:2131|1800|35\d{3})\d{11})$`
 	RegexDate = `^[0-9]{4}-(0[1-9]|1[0-2])-(0[1-9]|[1-2][0-9]|3[0-1])T(2[0-3]|[01][0-9]):([0-5][0-9]):([0-5][0-9]).(\d\d\d\d\d\d\d\d)$`
+
+	// Коды, тикеры и сети валют
+	RegexCurrency = `^[A-Z0-9]{2,20}$`
 )
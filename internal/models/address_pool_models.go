@@ -0,0 +1,12 @@
+package models
+
+// Адрес для приема средств, освободившийся после
+// истечения срока жизни заявки и доступный для повторной выдачи
+type PoolAddress struct {
+	ID         int    `json:"id"`
+	MaID       int    `json:"ma_id"`
+	Currency   string `json:"currency"`
+	Address    string `json:"address"`
+	ReleasedAt string `json:"released_at"`
+	CreatedAt  string `json:"created_at"`
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"regexp"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ AppInterfaces.ResourceI = (*Currency)(nil)
+var _ AppInterfaces.ResourceI = (*CurrencySelection)(nil)
+
+// Валюта из каталога. Code - код валюты в приложении, на него
+// ссылаются направления обмена и заявки, после создания он не
+// меняется. Ticker и Network - тикер и сеть валюты, по умолчанию
+// они же используются в платежных системах, ProviderCodes задает
+// отличающиеся тикер и сеть для отдельных сервисов.
+type Currency struct {
+	ID            int                                       `json:"id"`
+	Code          string                                    `json:"code"`
+	Ticker        string                                    `json:"ticker"`
+	Network       string                                    `json:"network"`
+	Decimals      int                                       `json:"decimals"`
+	MemoRequired  bool                                      `json:"memo_required"`
+	MinDeposit    AppMoney.Money                            `json:"min_deposit"`
+	MinWithdraw   AppMoney.Money                            `json:"min_withdraw"`
+	ProviderCodes map[string]AppInterfaces.ProviderCurrency `json:"provider_codes"`
+	CreatedBy     string                                    `json:"created_by"`
+	CreatedAt     string                                    `json:"created_at"`
+	UpdatedAt     string                                    `json:"updated_at"`
+}
+
+type CurrencySelection struct {
+	Page  int
+	Limit int
+}
+
+// Метод возвращает валюту в виде, в котором она
+// передается плагинам мерчантов/автовыплат
+func (c *Currency) Plugin() *AppInterfaces.Currency {
+	providers := make(map[string]AppInterfaces.ProviderCurrency, len(c.ProviderCodes))
+	for service, p := range c.ProviderCodes {
+		providers[service] = p
+	}
+
+	return &AppInterfaces.Currency{
+		Code:         c.Code,
+		Ticker:       c.Ticker,
+		Network:      c.Network,
+		MemoRequired: c.MemoRequired,
+		Providers:    providers,
+	}
+}
+
+// Метод задает точность сумм в валюте равной Decimals
+func (c *Currency) RegisterPrecision() {
+	AppMoney.SetPrecision(c.Code, int32(c.Decimals))
+}
+
+func (cs *CurrencySelection) Validation() error {
+	return validation.ValidateStruct(
+		cs,
+		validation.Field(&cs.Page,
+			validation.Required,
+			validation.Min(1),
+		),
+
+		validation.Field(&cs.Limit,
+			validation.Required,
+			validation.Min(1),
+			validation.Max(30),
+		),
+	)
+}
+
+func (c *Currency) Validation() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(
+			&c.Code,
+			validation.Required,
+			validation.Match(regexp.MustCompile(AppValidation.RegexCurrency)),
+		),
+
+		validation.Field(
+			&c.Ticker,
+			validation.Required,
+			validation.Match(regexp.MustCompile(AppValidation.RegexCurrency)),
+		),
+
+		validation.Field(
+			&c.Network,
+			validation.When(c.Network != "",
+				validation.Match(regexp.MustCompile(AppValidation.RegexCurrency)),
+			),
+		),
+
+		validation.Field(
+			&c.Decimals,
+			validation.Min(0),
+			validation.Max(18),
+		),
+
+		validation.Field(
+			&c.MinDeposit,
+			validation.By(c.minimumValidation),
+		),
+
+		validation.Field(
+			&c.MinWithdraw,
+			validation.By(c.minimumValidation),
+		),
+
+		validation.Field(
+			&c.ProviderCodes,
+			validation.By(c.providersValidation),
+		),
+
+		validation.Field(
+			&c.CreatedBy,
+			validation.When(c.CreatedBy != "",
+				validation.Match(regexp.MustCompile(AppValidation.RegexName)),
+			),
+		),
+	)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Минимальная сумма не может быть отрицательной
+// и должна укладываться в точность валюты
+func (c *Currency) minimumValidation(value interface{}) error {
+	m := value.(AppMoney.Money)
+	if m.IsNegative() || !m.Equal(AppMoney.New(m.Truncate(int32(c.Decimals)))) {
+		return AppError.ErrValidationInvalidMinimums
+	}
+
+	return nil
+}
+
+// Коды задаются только для подключенных сервисов
+func (c *Currency) providersValidation(value interface{}) error {
+	services := map[string]bool{}
+	for _, s := range plugins.Services() {
+		services[s] = true
+	}
+
+	re := regexp.MustCompile(AppValidation.RegexCurrency)
+	for service, p := range c.ProviderCodes {
+		if !services[service] || (p.Ticker == "" && p.Network == "") {
+			return AppError.ErrValidationInvalidProviders
+		}
+
+		if (p.Ticker != "" && !re.MatchString(p.Ticker)) ||
+			(p.Network != "" && !re.MatchString(p.Network)) {
+			return AppError.ErrValidationInvalidProviders
+		}
+	}
+
+	return nil
+}
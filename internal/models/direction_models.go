@@ -3,10 +3,14 @@ package models
 import (
 	"regexp"
 
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/shopspring/decimal"
 )
 
 var _ AppInterfaces.ResourceI = (*Direction)(nil)
@@ -14,15 +18,28 @@ var _ AppInterfaces.ResourceI = (*DirectionMA)(nil)
 var _ AppInterfaces.ResourceI = (*DirectionSelection)(nil)
 
 type Direction struct {
-	ID                  int    `json:"id"`
-	ExchangeFrom        string `json:"exchange_from"`
-	ExchangeTo          string `json:"exchange_to"`
-	CourseCorrection    int    `json:"course_correction"`
-	AddressVerification bool   `json:"address_verification"`
-	Status              bool   `json:"status"`
-	CreatedBy           string `json:"created_by"`
-	CreatedAt           string `json:"created_at"`
-	UpdatedAt           string `json:"updated_at"`
+	ID                  int            `json:"id"`
+	ExchangeFrom        string         `json:"exchange_from"`
+	ExchangeTo          string         `json:"exchange_to"`
+	CourseCorrection    int            `json:"course_correction"`
+	AddressVerification bool           `json:"address_verification"`
+	Status              bool           `json:"status"`
+	RequestTTL          int            `json:"request_ttl"`
+	ToleranceType       string         `json:"tolerance_type"`
+	Tolerance           AppMoney.Money `json:"tolerance"`
+	AutoPricing         bool           `json:"auto_pricing"`
+	TargetPosition      int            `json:"target_position"`
+	CorrectionMin       int            `json:"correction_min"`
+	CorrectionMax       int            `json:"correction_max"`
+	MinAmount           AppMoney.Money `json:"min_amount"`
+	MaxAmount           AppMoney.Money `json:"max_amount"`
+	ReserveType         string         `json:"reserve_type"`
+	Reserve             AppMoney.Money `json:"reserve"`
+	MerchantPolicy      string         `json:"merchant_policy"`
+	AutopayoutPolicy    string         `json:"autopayout_policy"`
+	CreatedBy           string         `json:"created_by"`
+	CreatedAt           string         `json:"created_at"`
+	UpdatedAt           string         `json:"updated_at"`
 }
 
 type DirectionMA struct {
@@ -31,10 +48,18 @@ type DirectionMA struct {
 	MaID        int    `json:"ma_id"`
 	ServiceType int    `json:"service_type"`
 	Status      bool   `json:"status"`
+	Priority    int    `json:"priority"`
+	Weight      int    `json:"weight"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
 
+// Активная привязка к направлению вместе с привязанным аккаунтом
+type DirectionAccount struct {
+	Binding *DirectionMA
+	Account *MerchantAutopayout
+}
+
 type DirectionSelection struct {
 	Page   *int
 	Limit  *int
@@ -107,10 +132,76 @@ func (ds *DirectionSelection) Validation() error {
 	)
 }
 
-func (dma *DirectionMA) Validation() error {
+// Метод возвращает допустимое отклонение полученной
+// суммы от ожидаемой суммы expected в единицах валюты
+func (d *Direction) AllowedDeviation(expected AppMoney.Money) AppMoney.Money {
+	if d.ToleranceType == AppType.TolerancePercent {
+		return expected.Mul(d.Tolerance.Decimal).Mul(decimal.New(1, -2))
+	}
+
+	return d.Tolerance
+}
+
+// Метод ограничивает корректировку cc границами,
+// установленными менеджером для автоматической корректировки
+func (d *Direction) ClampCorrection(cc int) int {
+	if cc < d.CorrectionMin {
+		return d.CorrectionMin
+	}
+
+	if cc > d.CorrectionMax {
+		return d.CorrectionMax
+	}
+
+	return cc
+}
+
+// Метод проверяет, что сумма заявки amount в валюте ExchangeFrom
+// укладывается в границы направления. Нулевая граница не ограничивает.
+func (d *Direction) CheckAmount(amount AppMoney.Money) error {
+	if d.MinAmount.IsPositive() && amount.LessThan(d.MinAmount.Decimal) {
+		return AppError.ErrAmountBelowMinimum
+	}
+
+	if d.MaxAmount.IsPositive() && amount.GreaterThan(d.MaxAmount.Decimal) {
+		return AppError.ErrAmountAboveMaximum
+	}
+
 	return nil
 }
 
+// Метод проверяет, что сумма выдачи payout в валюте ExchangeTo
+// не превышает резерв направления. Если способ задания резерва
+// не выбран, резерв не ограничивает заявки.
+func (d *Direction) CheckReserve(payout AppMoney.Money) error {
+	if d.ReserveType != "" && payout.GreaterThan(d.Reserve.Decimal) {
+		return AppError.ErrNotEnoughReserve
+	}
+
+	return nil
+}
+
+// Метод проверяет, что полученная сумма transferred отличается
+// от ожидаемой expected не больше чем допускает направление
+func (d *Direction) WithinTolerance(expected, transferred AppMoney.Money) bool {
+	return transferred.Sub(expected).Abs().LessThanOrEqual(d.AllowedDeviation(expected))
+}
+
+func (dma *DirectionMA) Validation() error {
+	return validation.ValidateStruct(
+		dma,
+		validation.Field(
+			&dma.Priority,
+			validation.Min(0),
+		),
+
+		validation.Field(
+			&dma.Weight,
+			validation.Min(0),
+		),
+	)
+}
+
 func (d *Direction) Validation() error {
 	return validation.ValidateStruct(
 		d,
@@ -133,9 +224,11 @@ func (d *Direction) Validation() error {
 			validation.Required,
 		),
 
+		// Нулевая корректировка допустима, курс не может стать отрицательным
 		validation.Field(
 			&d.CourseCorrection,
-			validation.Required,
+			validation.Min(-9999),
+			validation.Max(10000),
 		),
 
 		validation.Field(
@@ -148,6 +241,98 @@ func (d *Direction) Validation() error {
 			validation.In(true, false),
 		),
 
+		validation.Field(
+			&d.RequestTTL,
+			validation.Min(0),
+		),
+
+		validation.Field(
+			&d.ToleranceType,
+			validation.When(d.ToleranceType != "",
+				validation.In(AppType.ToleranceAbsolute, AppType.TolerancePercent),
+			),
+		),
+
+		validation.Field(
+			&d.Tolerance,
+			validation.By(func(value interface{}) error {
+				if d.Tolerance.IsNegative() ||
+					(d.ToleranceType == AppType.TolerancePercent && d.Tolerance.GreaterThan(decimal.NewFromInt(100))) {
+					return AppError.ErrValidationInvalidTolerance
+				}
+
+				return nil
+			}),
+		),
+
+		validation.Field(
+			&d.AutoPricing,
+			validation.In(true, false),
+		),
+
+		validation.Field(
+			&d.TargetPosition,
+			validation.When(d.AutoPricing,
+				validation.Required,
+				validation.Min(1),
+			).Else(validation.Min(0)),
+		),
+
+		validation.Field(
+			&d.CorrectionMax,
+			validation.By(func(value interface{}) error {
+				if d.CorrectionMin > d.CorrectionMax {
+					return AppError.ErrValidationInvalidBounds
+				}
+
+				return nil
+			}),
+		),
+
+		validation.Field(
+			&d.MaxAmount,
+			validation.By(func(value interface{}) error {
+				if d.MinAmount.IsNegative() || d.MaxAmount.IsNegative() ||
+					(d.MaxAmount.IsPositive() && d.MinAmount.GreaterThan(d.MaxAmount.Decimal)) {
+					return AppError.ErrValidationInvalidLimits
+				}
+
+				return nil
+			}),
+		),
+
+		validation.Field(
+			&d.ReserveType,
+			validation.When(d.ReserveType != "",
+				validation.In(AppType.ReserveManual, AppType.ReserveAuto),
+			),
+		),
+
+		validation.Field(
+			&d.Reserve,
+			validation.By(func(value interface{}) error {
+				if d.Reserve.IsNegative() {
+					return AppError.ErrValidationInvalidReserve
+				}
+
+				return nil
+			}),
+		),
+
+		validation.Field(
+			&d.MerchantPolicy,
+			validation.When(d.MerchantPolicy != "",
+				validation.In(AppType.SelectionPriority, AppType.SelectionWeighted),
+			),
+		),
+
+		validation.Field(
+			&d.AutopayoutPolicy,
+			validation.When(d.AutopayoutPolicy != "",
+				validation.In(AppType.SelectionPriority, AppType.SelectionWeighted, AppType.SelectionBalance),
+			),
+		),
+
 		validation.Field(
 			&d.CreatedBy,
 			validation.When(d.CreatedBy != "",
@@ -1,7 +1,14 @@
 package models
 
 import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -17,19 +24,76 @@ type ExchangeRequest struct {
 	ExchangeTo        string                        `json:"exchange_to"`
 	Course            string                        `json:"course"`
 	Address           string                        `json:"address"`
+	Memo              string                        `json:"memo"`
 	ClientAddress     string                        `json:"client_address"`
-	ExpectedAmount    float64                       `json:"expected_amount"`
-	TransferredAmount float64                       `json:"transferred_amount"`
+	ClientMemo        string                        `json:"client_memo"`
+	ExpectedAmount    AppMoney.Money                `json:"expected_amount"`
+	TransferredAmount AppMoney.Money                `json:"transferred_amount"`
 	TransactionHash   *string                       `json:"transaction_hash"`
+	MaID              *int                          `json:"ma_id"`
 	CreatedBy         UserFromBotRequest            `json:"created_by"`
 	CreatedAt         string                        `json:"created_at"`
 	UpdatedAt         string                        `json:"updated_at"`
 }
 
 type ExchangeRequestSelection struct {
-	Status AppType.ExchangeRequestStatus
-	Page   *int
-	Limit  *int
+	Status       AppType.ExchangeRequestStatus
+	ExchangeFrom string
+	ExchangeTo   string
+	Username     string
+	ChatID       int64
+	DateFrom     string
+	DateTo       string
+	Page         *int
+	Limit        *int
+}
+
+// Ручная смена статуса заявки менеджером
+type ExchangeRequestTransition struct {
+	Status AppType.ExchangeRequestStatus `json:"request_status"`
+	Reason string                        `json:"reason"`
+}
+
+// Список всех статусов заявки
+var exchangeRequestStatuses = []interface{}{
+	AppType.ExchangeRequestNew,
+	AppType.ExchangeRequestCanceled,
+	AppType.ExchangeRequestDeleted,
+	AppType.ExchangeRequestInvalidAmount,
+	AppType.ExchangeRequestPaid,
+	AppType.ExchangeRequestChecked,
+	AppType.ExchangeRequestAutopayoutError,
+	AppType.ExchangeRequestAwaitingConfirmation,
+	AppType.ExchangeRequestDone,
+}
+
+// Метод подготавливает переход заявки в статус to и возвращает
+// запись для истории статусов. Сама заявка не изменяется, переход
+// сохраняется методом Transition репозитория заявок.
+func (er *ExchangeRequest) Transition(to AppType.ExchangeRequestStatus, actor, reason string) (*RequestStatusHistory, error) {
+	if !er.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %d -> %d", AppError.ErrInvalidStatusTransition, er.Status, to)
+	}
+
+	return &RequestStatusHistory{
+		RequestID:  er.ID,
+		StatusFrom: er.Status,
+		StatusTo:   to,
+		Actor:      actor,
+		Reason:     reason,
+	}, nil
+}
+
+// Метод возвращает время создания заявки. Время приходит из БД
+// в формате RFC3339, из хранилища для тестов - в формате DateStandart.
+func (er *ExchangeRequest) CreatedTime() (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, core.DateStandart} {
+		if t, err := time.Parse(layout, er.CreatedAt); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("request %d: invalid created_at %q", er.ID, er.CreatedAt)
 }
 
 func (ers *ExchangeRequestSelection) Validation() error {
@@ -54,11 +118,42 @@ func (ers *ExchangeRequestSelection) Validation() error {
 
 		validation.Field(
 			&ers.Status,
-			validation.Required,
-			validation.In(
-				AppType.ExchangeRequestNew,
+			validation.When(ers.Status != 0,
+				validation.In(exchangeRequestStatuses...),
 			),
 		),
+
+		validation.Field(&ers.Username,
+			validation.When(ers.Username != "",
+				validation.Match(regexp.MustCompile(AppValidation.RegexName)),
+			).Else(validation.Empty),
+		),
+
+		validation.Field(&ers.DateFrom, validation.When(ers.DateFrom != "",
+			validation.By(AppValidation.DateValidation(ers.DateFrom)),
+		).Else(validation.Empty)),
+
+		validation.Field(&ers.DateTo, validation.When(ers.DateTo != "",
+			validation.By(AppValidation.DateValidation(ers.DateTo)),
+		).Else(validation.Empty)),
+	)
+}
+
+func (ert *ExchangeRequestTransition) Validation() error {
+	return validation.ValidateStruct(
+		ert,
+
+		validation.Field(
+			&ert.Status,
+			validation.Required,
+			validation.In(exchangeRequestStatuses...),
+		),
+
+		validation.Field(
+			&ert.Reason,
+			validation.Required,
+			validation.Length(1, 1000),
+		),
 	)
 }
 
@@ -97,6 +192,16 @@ func (er *ExchangeRequest) Validation() error {
 			validation.Required,
 		),
 
+		validation.Field(
+			&er.ExpectedAmount,
+			validation.By(AppValidation.MoneyValidation(er.ExpectedAmount, er.ExchangeFrom)),
+		),
+
+		validation.Field(
+			&er.ClientMemo,
+			validation.Length(0, 255),
+		),
+
 		// validation.Field(
 		// 	&er.CreatedBy,
 		// 	validation.Required,
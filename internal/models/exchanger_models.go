@@ -1,23 +1,55 @@
 package models
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"regexp"
+	"unicode/utf8"
 
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
 
 var _ AppInterfaces.ResourceI = (*ExchangerSelection)(nil)
 
+// Источник курсов. Format - формат выгрузки по адресу UrlToParse,
+// Mapping - расположение полей для форматов json и csv.
 type Exchanger struct {
-	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	UrlToParse string `json:"url"`
-	CreatedBy  string `json:"created_by"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	UrlToParse string          `json:"url"`
+	Format     string          `json:"format"`
+	Mapping    json.RawMessage `json:"mapping"`
+	CreatedBy  string          `json:"created_by"`
+	CreatedAt  string          `json:"created_at"`
+	UpdatedAt  string          `json:"updated_at"`
+}
+
+// Расположение полей котировки в выгрузке источника.
+//
+// Для json поля задаются путями через точку относительно
+// элемента массива Items, для csv - названиями колонок
+// из первой строки файла. Незаданные поля совпадают
+// с названиями полей формата 1obmen.
+type FeedMapping struct {
+	Items     string `json:"items"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	In        string `json:"in"`
+	Out       string `json:"out"`
+	Amount    string `json:"amount"`
+	Delimiter string `json:"delimiter"`
+}
+
+// Запрос пробного разбора выгрузки источника
+type FeedTestParse struct {
+	UrlToParse string          `json:"url"`
+	Format     string          `json:"format"`
+	Mapping    json.RawMessage `json:"mapping"`
 }
 
 type ExchangerSelection struct {
@@ -25,20 +57,21 @@ type ExchangerSelection struct {
 	Limit int
 }
 
+// Выгрузка курсов в формате мониторингов обменников
 type OneObmen struct {
-	XMLName xml.Name       `xml:"rates"`
-	Rates   []OneObmenItem `xml:"item"`
+	XMLName xml.Name       `xml:"rates" json:"-"`
+	Rates   []OneObmenItem `xml:"item" json:"rates"`
 }
 
 type OneObmenItem struct {
-	XMLName   xml.Name `xml:"item"`
-	From      string   `xml:"from"`
-	To        string   `xml:"to"`
-	In        float64  `xml:"in"`
-	Out       float64  `xml:"out"`
-	Amount    float64  `xml:"amount"`
-	MinAmount string   `xml:"minamount"`
-	MaxAmount string   `xml:"maxamount"`
+	XMLName   xml.Name       `xml:"item" json:"-"`
+	From      string         `xml:"from" json:"from"`
+	To        string         `xml:"to" json:"to"`
+	In        AppMoney.Money `xml:"in" json:"in"`
+	Out       AppMoney.Money `xml:"out" json:"out"`
+	Amount    AppMoney.Money `xml:"amount" json:"amount"`
+	MinAmount string         `xml:"minamount,omitempty" json:"minamount,omitempty"`
+	MaxAmount string         `xml:"maxamount,omitempty" json:"maxamount,omitempty"`
 }
 
 /*
@@ -84,6 +117,14 @@ func (e *Exchanger) ExchangerCreateValidation() error {
 			validation.Length(3, 255),
 			validation.Match(regexp.MustCompile(AppValidation.RegexUrl)),
 		),
+		validation.Field(
+			&e.Format,
+			validation.In(AppType.FeedFormatXML, AppType.FeedFormatJSON, AppType.FeedFormatCSV),
+		),
+		validation.Field(
+			&e.Mapping,
+			validation.By(mappingValidation),
+		),
 	)
 }
 
@@ -102,5 +143,75 @@ func (e *Exchanger) ExchangerUpdateValidation() error {
 			validation.Length(3, 255),
 			validation.Match(regexp.MustCompile(AppValidation.RegexUrl)),
 		),
+		validation.Field(
+			&e.Format,
+			validation.In(AppType.FeedFormatXML, AppType.FeedFormatJSON, AppType.FeedFormatCSV),
+		),
+		validation.Field(
+			&e.Mapping,
+			validation.By(mappingValidation),
+		),
 	)
 }
+
+func (ftp *FeedTestParse) Validation() error {
+	return validation.ValidateStruct(
+		ftp,
+		validation.Field(
+			&ftp.UrlToParse,
+			validation.Required,
+			validation.Length(3, 255),
+			validation.Match(regexp.MustCompile(AppValidation.RegexUrl)),
+		),
+		validation.Field(
+			&ftp.Format,
+			validation.In(AppType.FeedFormatXML, AppType.FeedFormatJSON, AppType.FeedFormatCSV),
+		),
+		validation.Field(
+			&ftp.Mapping,
+			validation.By(mappingValidation),
+		),
+	)
+}
+
+// Метод возвращает расположение полей с учетом значений по умолчанию
+func (e *Exchanger) FeedMapping() (*FeedMapping, error) {
+	m := &FeedMapping{}
+	if len(e.Mapping) > 0 {
+		if err := json.Unmarshal(e.Mapping, m); err != nil {
+			return nil, AppError.ErrValidationInvalidFeedMapping
+		}
+	}
+
+	for _, f := range []struct {
+		field *string
+		value string
+	}{
+		{&m.From, "from"},
+		{&m.To, "to"},
+		{&m.In, "in"},
+		{&m.Out, "out"},
+		{&m.Amount, "amount"},
+		{&m.Delimiter, ","},
+	} {
+		if *f.field == "" {
+			*f.field = f.value
+		}
+	}
+
+	return m, nil
+}
+
+func mappingValidation(value interface{}) error {
+	raw, _ := value.(json.RawMessage)
+	m, err := (&Exchanger{Mapping: raw}).FeedMapping()
+	if err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(m.Delimiter) != 1 {
+		return AppError.ErrValidationInvalidFeedMapping
+	}
+
+	return nil
+}
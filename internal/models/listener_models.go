@@ -1,11 +1,106 @@
 package models
 
+import (
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+)
+
+// Набор аккаунтов одной итерации слушателя.
+//
+// Набор собирается целиком до начала опроса и после этого не
+// меняется: поля закрыты, а методы возвращают копии. Поэтому его
+// можно читать из нескольких горутин без блокировок.
 type ListenerState struct {
-	Merchants   *ListeningAccounts
-	Autopayouts *ListeningAccounts
+	merchants   []*ListeningAccount
+	autopayouts []*ListeningAccount
+	failed      []*FailedAccount
+}
+
+// Аккаунт мерчанта/автовыплат с расшифрованными параметрами
+type ListeningAccount struct {
+	ID      int
+	Name    string
+	Service string
+	Params  AppInterfaces.OptionParams
+}
+
+// Аккаунт, который не удалось подготовить к опросу
+type FailedAccount struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Service     string `json:"service"`
+	ServiceType int    `json:"service_type"`
+	Error       string `json:"error"`
+}
+
+func NewListenerState(merchants, autopayouts []*ListeningAccount, failed []*FailedAccount) *ListenerState {
+	s := &ListenerState{
+		merchants:   copyAccounts(merchants, ""),
+		autopayouts: copyAccounts(autopayouts, ""),
+		failed:      make([]*FailedAccount, 0, len(failed)),
+	}
+
+	for _, f := range failed {
+		v := *f
+		s.failed = append(s.failed, &v)
+	}
+
+	return s
+}
+
+// Метод возвращает аккаунты мерчантов сервиса service
+func (s *ListenerState) Merchants(service string) []*ListeningAccount {
+	return copyAccounts(s.merchants, service)
+}
+
+// Метод возвращает аккаунты автовыплат сервиса service
+func (s *ListenerState) Autopayouts(service string) []*ListeningAccount {
+	return copyAccounts(s.autopayouts, service)
+}
+
+// Метод возвращает аккаунты, которые не ответили на пинг
+// или параметры которых не удалось расшифровать
+func (s *ListenerState) Failed() []*FailedAccount {
+	arr := make([]*FailedAccount, 0, len(s.failed))
+	for _, f := range s.failed {
+		v := *f
+		arr = append(arr, &v)
+	}
+
+	return arr
+}
+
+// Функция копирует аккаунты сервиса service из arr,
+// для пустого service копируются все аккаунты
+func copyAccounts(arr []*ListeningAccount, service string) []*ListeningAccount {
+	res := make([]*ListeningAccount, 0, len(arr))
+	for _, a := range arr {
+		if service == "" || a.Service == service {
+			v := *a
+			res = append(res, &v)
+		}
+	}
+
+	return res
 }
 
-type ListeningAccounts struct {
-	Whitebit []*WhitebitOptionParams
-	Mine     []*MineOptionParams
+// Позиция слушателя в истории операций аккаунта.
+//
+// CreatedAt - время самой новой обработанной операции, Seen - ключи
+// обработанных операций с этим же временем. Pending - ключи операций,
+// которые при последнем опросе еще не получили окончательный статус,
+// и время их создания. История читается до самой старой из них.
+//
+// Если за один опрос история не дочитана до позиции, между CreatedAt
+// и Ceiling остается непрочитанный участок. Операции новее Ceiling уже
+// обработаны, самая новая из них - Head. Следующие опросы дочитывают
+// участок начиная с операции Offset, после чего позиция переходит на Head.
+type HistoryCursor struct {
+	MaID      int              `json:"ma_id"`
+	CreatedAt int64            `json:"created_at"`
+	Seen      []string         `json:"seen"`
+	Pending   map[string]int64 `json:"pending"`
+	Offset    int              `json:"offset"`
+	Ceiling   int64            `json:"ceiling"`
+	Head      int64            `json:"head"`
+	UpdatedAt string           `json:"updated_at"`
 }
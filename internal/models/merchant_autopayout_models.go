@@ -6,6 +6,7 @@ import (
 	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -25,6 +26,22 @@ type MerchantAutopayout struct {
 	UpdatedAt   string `json:"updated_at"`
 }
 
+// Состояние аккаунта по результатам плановых проверок.
+// Latency - время ответа последней проверки в миллисекундах,
+// Checks и Failures - всего проверок и неудачных проверок.
+type AccountHealth struct {
+	MaID                int     `json:"ma_id"`
+	Alive               bool    `json:"alive"`
+	Latency             int64   `json:"latency"`
+	LastError           string  `json:"last_error"`
+	LastErrorAt         *string `json:"last_error_at"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	Checks              int     `json:"checks"`
+	Failures            int     `json:"failures"`
+	LastSuccessAt       *string `json:"last_success_at"`
+	CheckedAt           string  `json:"checked_at"`
+}
+
 type MerchantAutopayoutSelection struct {
 	Page    *int
 	Limit   *int
@@ -80,10 +97,7 @@ func (ma *MerchantAutopayoutSelection) Validation() error {
 			&ma.Service,
 			validation.When(len(ma.Service) > 0,
 				validation.Each(
-					validation.In(
-						AppType.MerchantAutoPayoutMine,
-						AppType.MerchantAutoPayoutWhitebit,
-					),
+					validation.In(AppValidation.StringsToInterfaces(plugins.Services())...),
 				),
 			),
 		),
@@ -108,10 +122,7 @@ func (ma *MerchantAutopayout) Validation() error {
 			validation.When(
 				ma.CreatedBy != "",
 				validation.Required,
-				validation.In(
-					AppType.MerchantAutoPayoutMine,
-					AppType.MerchantAutoPayoutWhitebit,
-				),
+				validation.In(AppValidation.StringsToInterfaces(plugins.Services())...),
 				validation.Match(regexp.MustCompile(AppValidation.RegexName)),
 			),
 		),
@@ -0,0 +1,74 @@
+package models
+
+import (
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ AppInterfaces.ResourceI = (*PayoutSelection)(nil)
+
+// Попытка автовыплаты по заявке
+type Payout struct {
+	ID          int                  `json:"id"`
+	RequestID   int                  `json:"request_id"`
+	MaID        int                  `json:"ma_id"`
+	Ticker      string               `json:"ticker"`
+	Network     string               `json:"network"`
+	Amount      AppMoney.Money       `json:"amount"`
+	Status      AppType.PayoutStatus `json:"status"`
+	RawResponse *string              `json:"raw_response"`
+	Error       *string              `json:"error"`
+	CreatedAt   string               `json:"created_at"`
+	UpdatedAt   string               `json:"updated_at"`
+}
+
+// Выборка попыток автовыплат по заявке или по аккаунту
+type PayoutSelection struct {
+	Page      *int
+	Limit     *int
+	RequestID int
+	MaID      int
+}
+
+func (ps *PayoutSelection) Validation() error {
+	return validation.ValidateStruct(
+		ps,
+		validation.Field(
+			&ps.Page,
+			validation.When(
+				ps.Page != nil,
+				validation.Required,
+				validation.Min(1)),
+		),
+
+		validation.Field(
+			&ps.Limit,
+			validation.When(
+				ps.Limit != nil,
+				validation.Required,
+				validation.Min(1),
+				validation.Max(30),
+			),
+		),
+
+		validation.Field(
+			&ps.RequestID,
+			validation.When(
+				ps.MaID == 0,
+				validation.Required,
+				validation.Min(1),
+			).Else(validation.Empty),
+		),
+
+		validation.Field(
+			&ps.MaID,
+			validation.When(
+				ps.RequestID == 0,
+				validation.Required,
+				validation.Min(1),
+			),
+		),
+	)
+}
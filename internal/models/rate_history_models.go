@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ AppInterfaces.ResourceI = (*RateHistorySelection)(nil)
+
+// Максимальное кол-во точек в одном ответе истории курсов
+const RateHistoryMaxPoints = 2000
+
+// Курс направления в одном источнике на момент обновления.
+// Reference отмечает котировку, выбранную опорной.
+type RateHistory struct {
+	ID               int            `json:"id"`
+	DirectionID      int            `json:"direction_id"`
+	ExchangerID      int            `json:"exchanger_id"`
+	SourceIn         AppMoney.Money `json:"source_in"`
+	SourceOut        AppMoney.Money `json:"source_out"`
+	CourseCorrection int            `json:"course_correction"`
+	In               AppMoney.Money `json:"in"`
+	Out              AppMoney.Money `json:"out"`
+	Reference        bool           `json:"reference"`
+	CreatedAt        string         `json:"created_at"`
+}
+
+// Запрос истории курсов направления. Без ExchangerID
+// возвращается история опорного курса направления.
+type RateHistorySelection struct {
+	DirectionID int
+	ExchangerID int
+	Interval    string
+	DateFrom    string
+	DateTo      string
+}
+
+type RateStats struct {
+	Min AppMoney.Money `json:"min"`
+	Max AppMoney.Money `json:"max"`
+	Avg AppMoney.Money `json:"avg"`
+}
+
+// Сводка по курсу источника и скорректированному курсу
+type RateHistoryStats struct {
+	Count  int       `json:"count"`
+	Source RateStats `json:"source"`
+	Course RateStats `json:"course"`
+}
+
+// Точка временного ряда, Time - начало интервала
+type RateHistoryPoint struct {
+	Time string `json:"time"`
+	RateHistoryStats
+}
+
+var rateHistoryIntervals = map[string]time.Duration{
+	AppType.RateHistoryMinute: time.Minute,
+	AppType.RateHistoryHour:   time.Hour,
+	AppType.RateHistoryDay:    24 * time.Hour,
+}
+
+func (rhs *RateHistorySelection) Validation() error {
+	return validation.ValidateStruct(
+		rhs,
+		validation.Field(
+			&rhs.DirectionID,
+			validation.Required,
+			validation.Min(1),
+		),
+
+		validation.Field(
+			&rhs.ExchangerID,
+			validation.Min(0),
+		),
+
+		validation.Field(
+			&rhs.Interval,
+			validation.Required,
+			validation.In(AppType.RateHistoryMinute, AppType.RateHistoryHour, AppType.RateHistoryDay),
+		),
+
+		validation.Field(
+			&rhs.DateFrom,
+			validation.Required,
+			validation.By(AppValidation.DateValidation(rhs.DateFrom)),
+		),
+
+		validation.Field(
+			&rhs.DateTo,
+			validation.Required,
+			validation.By(AppValidation.DateValidation(rhs.DateTo)),
+			validation.By(rhs.periodValidation),
+		),
+	)
+}
+
+// Метод проверяет, что период не пустой и при выбранном
+// шаге прореживания не дает больше RateHistoryMaxPoints точек
+func (rhs *RateHistorySelection) periodValidation(value interface{}) error {
+	from, err := time.Parse(core.DateStandart, rhs.DateFrom)
+	if err != nil {
+		return nil
+	}
+
+	to, err := time.Parse(core.DateStandart, rhs.DateTo)
+	if err != nil {
+		return nil
+	}
+
+	if !from.Before(to) {
+		return AppError.ErrValidationInvalidPeriod
+	}
+
+	if step, ok := rateHistoryIntervals[rhs.Interval]; ok && to.Sub(from)/step > RateHistoryMaxPoints {
+		return AppError.ErrValidationTooManyPoints
+	}
+
+	return nil
+}
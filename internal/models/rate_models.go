@@ -0,0 +1,44 @@
+package models
+
+import (
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	"github.com/shopspring/decimal"
+)
+
+// Текущий курс направления обмена.
+//
+// SourceIn/SourceOut - опорный курс, выбранный среди источников
+// из таблицы `exchangers`, In/Out - курс после применения
+// корректировки направления CourseCorrection, Course - кол-во
+// единиц ExchangeTo выдаваемое за одну единицу ExchangeFrom.
+// Position - место курса среди Competitors курсов источников.
+type Rate struct {
+	ExchangeFrom     string         `json:"exchange_from"`
+	ExchangeTo       string         `json:"exchange_to"`
+	Source           string         `json:"source"`
+	SourceIn         AppMoney.Money `json:"source_in"`
+	SourceOut        AppMoney.Money `json:"source_out"`
+	CourseCorrection int            `json:"course_correction"`
+	In               AppMoney.Money `json:"in"`
+	Out              AppMoney.Money `json:"out"`
+	Course           AppMoney.Money `json:"course"`
+	Position         int            `json:"position"`
+	Competitors      int            `json:"competitors"`
+	UpdatedAt        string         `json:"updated_at"`
+}
+
+// Метод применяет корректировку направления к опорному курсу.
+// Корректировка задается в сотых долях процента, положительная
+// увеличивает сумму выдачи, отрицательная уменьшает.
+func (r *Rate) Correct(courseCorrection int) {
+	r.CourseCorrection = courseCorrection
+	r.In = r.SourceIn
+	r.Out = r.SourceOut.Mul(
+		decimal.NewFromInt(10000 + int64(courseCorrection)).Shift(-4),
+	)
+
+	r.Course = AppMoney.Zero
+	if r.In.IsPositive() {
+		r.Course = AppMoney.New(r.Out.Div(r.In.Decimal).Truncate(AppMoney.DefaultPrecision))
+	}
+}
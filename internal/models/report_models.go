@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ AppInterfaces.ResourceI = (*ReportJob)(nil)
+
+// Задача формирования отчета. File - путь к готовому файлу
+// на сервере, наружу он не отдается, файл скачивается через
+// отдельный метод API. По истечении срока хранения файл
+// удаляется, а задача переходит в статус ReportStatusExpired.
+type ReportJob struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	Status    string          `json:"status"`
+	Params    json.RawMessage `json:"params"`
+	File      string          `json:"-"`
+	Error     string          `json:"error"`
+	CreatedBy string          `json:"created_by"`
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+}
+
+// Параметры отчета AppType.ReportTypeExchangerRates
+type ExchangerRatesReportParams struct {
+	Exchanger string `json:"exchanger"`
+}
+
+func (rj *ReportJob) Validation() error {
+	return validation.ValidateStruct(
+		rj,
+		validation.Field(
+			&rj.Type,
+			validation.Required,
+			validation.In(AppType.ReportTypeExchangerRates),
+		),
+
+		validation.Field(
+			&rj.Params,
+			validation.By(rj.paramsValidation),
+		),
+
+		validation.Field(
+			&rj.CreatedBy,
+			validation.When(rj.CreatedBy != "",
+				validation.Match(
+					regexp.MustCompile(AppValidation.RegexName),
+				),
+			),
+		),
+	)
+}
+
+// Метод проверяет параметры отчета выбранного типа
+func (rj *ReportJob) paramsValidation(value interface{}) error {
+	switch rj.Type {
+	case AppType.ReportTypeExchangerRates:
+		p := &ExchangerRatesReportParams{}
+		if err := json.Unmarshal(rj.Params, p); err != nil {
+			return AppError.ErrValidationInvalidReportParams
+		}
+
+		return validation.ValidateStruct(
+			p,
+			validation.Field(&p.Exchanger, validation.Required),
+		)
+	}
+
+	return nil
+}
+
+// Отчет можно скачать
+func (rj *ReportJob) Ready() bool {
+	return rj.Status == AppType.ReportStatusDone
+}
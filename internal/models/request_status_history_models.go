@@ -0,0 +1,16 @@
+package models
+
+import (
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+)
+
+// Запись о смене статуса заявки
+type RequestStatusHistory struct {
+	ID         int                           `json:"id"`
+	RequestID  int                           `json:"request_id"`
+	StatusFrom AppType.ExchangeRequestStatus `json:"status_from"`
+	StatusTo   AppType.ExchangeRequestStatus `json:"status_to"`
+	Actor      string                        `json:"actor"`
+	Reason     string                        `json:"reason"`
+	CreatedAt  string                        `json:"created_at"`
+}
@@ -3,6 +3,7 @@ package models
 type WhitebitApiHistory struct {
 	Account struct {
 		Address string `json:"address"`
+		Memo    string `json:"memo"`
 	} `json:"account"`
 
 	Required struct {
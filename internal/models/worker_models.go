@@ -0,0 +1,24 @@
+package models
+
+// Состояние фонового процесса работающего под наблюдением
+type WorkerState struct {
+	Name        string  `json:"name"`
+	Running     bool    `json:"running"`
+	Restarts    int     `json:"restarts"`
+	LastTick    *string `json:"last_tick"`
+	LastError   *string `json:"last_error"`
+	LastErrorAt *string `json:"last_error_at"`
+}
+
+// Состояние слушателя транзакций
+type ListenerStatus struct {
+	WorkerState
+	AccountsPolled int              `json:"accounts_polled"`
+	FailedAccounts []*FailedAccount `json:"failed_accounts"`
+}
+
+// Состояние проверки аккаунтов мерчантов/автовыплат
+type AccountsHealthStatus struct {
+	WorkerState
+	Accounts []*AccountHealth `json:"accounts"`
+}
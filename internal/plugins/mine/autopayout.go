@@ -1,6 +1,11 @@
 package mine_plugin
 
-import "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+import (
+	"context"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+)
 
 type MinePluginAutoPayout struct{}
 
@@ -8,6 +13,6 @@ func IniAutoPayout() interfaces.AutoPayoutI {
 	return &MinePluginAutoPayout{}
 }
 
-func (p *MinePluginAutoPayout) Payout(params, body interface{}) (interface{}, error) {
-	return nil, nil
+func (p *MinePluginAutoPayout) Payout(ctx context.Context, params interfaces.OptionParams, r *interfaces.PayoutRequest) (*interfaces.PayoutResult, error) {
+	return nil, AppError.ErrPluginNotSupported
 }
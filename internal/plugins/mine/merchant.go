@@ -1,6 +1,9 @@
 package mine_plugin
 
 import (
+	"context"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 )
 
@@ -10,6 +13,6 @@ func InitMerchant() interfaces.MerchantI {
 	return &MinePluginMerchant{}
 }
 
-func (p *MinePluginMerchant) CreateAdress(d, apiHandler interface{}) (interface{}, error) {
-	return nil, nil
+func (p *MinePluginMerchant) CreateAddress(ctx context.Context, params interfaces.OptionParams, r *interfaces.AddressRequest) (*interfaces.Address, error) {
+	return nil, AppError.ErrPluginNotSupported
 }
@@ -1,9 +1,22 @@
 package mine_plugin
 
 import (
+	"context"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
 )
 
+func init() {
+	plugins.Register(AppType.MerchantAutoPayoutMine, func(cfg *config.PluginsConfig) interfaces.PluginI {
+		return InitMinePlugin()
+	})
+}
+
 type MinePlugin struct {
 	merchant   interfaces.MerchantI
 	autopayout interfaces.AutoPayoutI
@@ -34,18 +47,18 @@ func (plugin *MinePlugin) AutoPayout() interfaces.AutoPayoutI {
 	return plugin.autopayout
 }
 
-func (plugin *MinePlugin) Ping(params interface{}) (interface{}, error) {
-	return nil, nil
+func (plugin *MinePlugin) Ping(ctx context.Context, params interfaces.OptionParams) error {
+	return AppError.ErrPluginNotSupported
 }
 
-func (plugin *MinePlugin) History(params, body interface{}) (interface{}, error) {
-	return nil, nil
+func (plugin *MinePlugin) History(ctx context.Context, params interfaces.OptionParams, q *interfaces.HistoryQuery) (*interfaces.History, error) {
+	return nil, AppError.ErrPluginNotSupported
 }
 
-func (plugin *MinePlugin) Balance(params, body interface{}) (interface{}, error) {
-	return nil, nil
+func (plugin *MinePlugin) Balance(ctx context.Context, params interfaces.OptionParams, ticker string) ([]*interfaces.Balance, error) {
+	return nil, AppError.ErrPluginNotSupported
 }
 
-func (plugin *MinePlugin) GetOptionParams(options string) (interface{}, error) {
-	return nil, nil
+func (plugin *MinePlugin) GetOptionParams(options string) (interfaces.OptionParams, error) {
+	return &models.MineOptionParams{}, nil
 }
@@ -1,17 +1,80 @@
 package plugins
 
 import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 )
 
+// Функция создания экземпляра плагина
+type Factory func(cfg *config.PluginsConfig) interfaces.PluginI
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Регистрация плагина под названием сервиса.
+// Каждый плагин вызывает ее из функции init своего пакета,
+// поэтому для подключения плагина достаточно его импорта.
+func Register(service string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if f == nil {
+		panic(fmt.Sprintf("plugins: factory for %s is nil", service))
+	}
+
+	if _, ok := factories[service]; ok {
+		panic(fmt.Sprintf("plugins: %s already registered", service))
+	}
+
+	factories[service] = f
+}
+
+// Список названий всех зарегистрированных сервисов
+func Services() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	arr := make([]string, 0, len(factories))
+	for service := range factories {
+		arr = append(arr, service)
+	}
+	sort.Strings(arr)
+
+	return arr
+}
+
 type AppPlugins struct {
-	Mine     interfaces.PluginI
-	Whitebit interfaces.PluginI
+	plugins map[string]interfaces.PluginI
+}
+
+// Создание экземпляров всех зарегистрированных плагинов
+func InitAppPlugins(cfg *config.PluginsConfig) *AppPlugins {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	p := &AppPlugins{
+		plugins: make(map[string]interfaces.PluginI, len(factories)),
+	}
+
+	for service, f := range factories {
+		p.plugins[service] = f(cfg)
+	}
+
+	return p
 }
 
-func InitAppPlugins(m, wb interfaces.PluginI) *AppPlugins {
-	return &AppPlugins{
-		Mine:     m,
-		Whitebit: wb,
+// Получить плагин по названию сервиса (поле Service в MerchantAutopayout)
+func (p *AppPlugins) Get(service string) (interfaces.PluginI, error) {
+	if plugin, ok := p.plugins[service]; ok {
+		return plugin, nil
 	}
+
+	return nil, fmt.Errorf("%w: %s", AppError.ErrPluginNotFound, service)
 }
@@ -0,0 +1,42 @@
+package plugins_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/stretchr/testify/assert"
+
+	mine_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/mine"
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+)
+
+func Test_Plugins_Registry(t *testing.T) {
+	plugins.Register("test", func(cfg *config.PluginsConfig) interfaces.PluginI {
+		return mine_plugin.InitMinePlugin()
+	})
+
+	assert.Equal(t, []string{"mine", "test", "whitebit"}, plugins.Services())
+
+	// Повторная регистрация сервиса недопустима
+	assert.Panics(t, func() {
+		plugins.Register("test", func(cfg *config.PluginsConfig) interfaces.PluginI {
+			return nil
+		})
+	})
+
+	p := plugins.InitAppPlugins(&config.PluginsConfig{})
+
+	for _, service := range plugins.Services() {
+		plugin, err := p.Get(service)
+		assert.NoError(t, err)
+		assert.NotNil(t, plugin)
+	}
+
+	plugin, err := p.Get("unknown")
+	assert.Nil(t, plugin)
+	assert.True(t, errors.Is(err, AppError.ErrPluginNotFound))
+}
@@ -1,8 +1,13 @@
 package whitebit_plugin
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
-	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 )
 
 type WhitebitPluginAutoPayout struct{}
@@ -11,14 +16,58 @@ func IniAutoPayout() interfaces.AutoPayoutI {
 	return &WhitebitPluginAutoPayout{}
 }
 
-func (p *WhitebitPluginAutoPayout) Payout(params, body interface{}) (interface{}, error) {
-	b, err := SendRequest(
-		params.(*models.WhitebitOptionParams),
-		WhitebitbWithdrawPay,
-		body.(map[string]interface{}),
-	)
+// Создать запрос на вывод средств. Биржа не принимает повторный
+// запрос с тем же uniqueId, поэтому повтор безопасен. Если вывод с
+// этим uniqueId уже был принят, возвращается AppError.ErrPayoutDuplicate.
+func (p *WhitebitPluginAutoPayout) Payout(ctx context.Context, params interfaces.OptionParams, r *interfaces.PayoutRequest) (*interfaces.PayoutResult, error) {
+	op, err := optionParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := SendRequest(ctx, op, WhitebitbWithdrawPay, PrepareBodyForPayout(r))
 	if err != nil {
+		if pErr, ok := interfaces.AsProviderError(err); ok && duplicate(pErr) {
+			return nil, fmt.Errorf("%w | %s", AppError.ErrPayoutDuplicate, pErr.Error())
+		}
+
 		return nil, err
 	}
-	return b, nil
+
+	return &interfaces.PayoutResult{Raw: b}, nil
+}
+
+// Тело запроса на вывод. Тикер и сеть берутся из каталога
+// валют с учетом кодов, заданных для Whitebit.
+func PrepareBodyForPayout(r *interfaces.PayoutRequest) map[string]interface{} {
+	c := r.Currency.Provider(AppType.MerchantAutoPayoutWhitebit)
+
+	body := map[string]interface{}{
+		"ticker":   c.Ticker,
+		"amount":   r.Amount.String(),
+		"address":  r.Address,
+		"uniqueId": r.UniqueID,
+	}
+
+	if c.Network != "" {
+		body["network"] = c.Network
+	}
+
+	if r.Memo != "" {
+		body["memo"] = r.Memo
+	}
+
+	return body
+}
+
+// Функция проверяет, отклонила ли биржа вывод из-за того,
+// что вывод с тем же uniqueId уже был принят
+func duplicate(pErr *interfaces.ProviderError) bool {
+	for _, msg := range pErr.Errors["uniqueId"] {
+		if strings.Contains(msg, "already been taken") {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,254 @@
+package whitebit_plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+const (
+	// Максимальное время одного запроса, если контекст
+	// вызывающей стороны не ограничен по времени
+	RequestTimeout = 15 * time.Second
+
+	// Кол-во повторов запроса к идемпотентной конечной точке
+	// и задержка перед первым повтором, далее она удваивается
+	MaxRetries   = 3
+	RetryBackoff = 200 * time.Millisecond
+)
+
+// Лимиты частоты запросов Whitebit на один API ключ:
+// кол-во запросов за 10 секунд для каждой конечной точки
+var rateLimits = map[string]int{
+	WhitebitMainAccountAddress: 1000,
+	WhitebitCreateNewAddress:   1000,
+	WhitebitHistory:            200,
+	WhitebitbBalance:           1000,
+	WhitebitbWithdraw:          1000,
+	WhitebitbWithdrawPay:       1000,
+}
+
+// Конечные точки, запросы к которым ничего не меняют
+// на бирже и могут быть безопасно повторены
+var idempotent = map[string]bool{
+	WhitebitMainAccountAddress: true,
+	WhitebitHistory:            true,
+	WhitebitbBalance:           true,
+}
+
+// Клиент API Whitebit одного аккаунта.
+//
+// Клиенты создаются один раз на API ключ и переиспользуются всеми
+// плагинами. Запросы одного ключа отправляются по очереди, потому что
+// биржа отклоняет запрос, nonce которого не больше nonce предыдущего.
+// Запросы разных ключей выполняются параллельно.
+type Client struct {
+	http *http.Client
+
+	// Блокировка на время отправки запроса
+	send sync.Mutex
+
+	mu        sync.Mutex
+	params    models.WhitebitOptionParams
+	lastNonce int64
+	next      map[string]time.Time
+}
+
+var clients = struct {
+	sync.Mutex
+	m map[string]*Client
+}{m: map[string]*Client{}}
+
+// Функция возвращает клиент аккаунта с параметрами params
+func ClientFor(params *models.WhitebitOptionParams) *Client {
+	clients.Lock()
+	defer clients.Unlock()
+
+	k := params.BaseURL + "|" + params.PublicKey
+	c, ok := clients.m[k]
+	if !ok {
+		c = &Client{
+			http: &http.Client{},
+			next: map[string]time.Time{},
+		}
+		clients.m[k] = c
+	}
+
+	// Секретный ключ аккаунта мог измениться
+	c.mu.Lock()
+	c.params = *params
+	c.mu.Unlock()
+
+	return c
+}
+
+// Метод отправляет запрос на конечную точку requestURL. Запросы
+// к идемпотентным конечным точкам повторяются с экспоненциальной
+// задержкой, если запрос не дошел до биржи, биржа ответила ошибкой
+// сервера или превышением частоты запросов.
+// Если биржа отклонила запрос, возвращается *interfaces.ProviderError
+func (c *Client) Send(ctx context.Context, requestURL string, data map[string]interface{}) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, RequestTimeout)
+		defer cancel()
+	}
+
+	delay := RetryBackoff
+	for attempt := 0; ; attempt++ {
+		b, err := c.do(ctx, requestURL, data)
+		if err == nil || !idempotent[requestURL] || attempt >= MaxRetries || !retryable(err) {
+			return b, err
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return b, err
+		case <-t.C:
+		}
+
+		delay *= 2
+	}
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (c *Client) do(ctx context.Context, requestURL string, data map[string]interface{}) ([]byte, error) {
+	if err := c.wait(ctx, requestURL); err != nil {
+		return nil, err
+	}
+
+	c.send.Lock()
+	defer c.send.Unlock()
+
+	c.mu.Lock()
+	params := c.params
+	c.mu.Unlock()
+
+	body := make(map[string]interface{}, len(data)+2)
+	for k, v := range data {
+		body[k] = v
+	}
+	body["request"] = requestURL
+	body["nonce"] = strconv.FormatInt(c.nonce(), 10)
+
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Расчет полезной нагрузки
+	payload := base64.StdEncoding.EncodeToString(requestBody)
+
+	// Вычисление подписи с использованием sha512
+	h := hmac.New(sha512.New, []byte(params.SecretKey))
+	h.Write([]byte(payload))
+	signature := fmt.Sprintf("%x", h.Sum(nil))
+
+	request, err := http.NewRequestWithContext(ctx, "POST", params.BaseURL+requestURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-type", "application/json")
+	request.Header.Set("X-TXC-APIKEY", params.PublicKey)
+	request.Header.Set("X-TXC-PAYLOAD", payload)
+	request.Header.Set("X-TXC-SIGNATURE", signature)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if pErr := ParseError(response.StatusCode, b); pErr != nil {
+		return b, pErr
+	}
+
+	return b, nil
+}
+
+// Метод ожидает, пока запрос к конечной точке requestURL
+// уложится в лимит частоты запросов биржи
+func (c *Client) wait(ctx context.Context, requestURL string) error {
+	limit, ok := rateLimits[requestURL]
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	at := c.next[requestURL]
+	if at.Before(now) {
+		at = now
+	}
+	c.next[requestURL] = at.Add(10 * time.Second / time.Duration(limit))
+	c.mu.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Если одноразовый номер похож на номер предыдущего запроса или меньше
+// его, будет получено сообщение об ошибке «слишком много запросов».
+// Поэтому nonce — это время в миллисекундах, но всегда больше, чем
+// номер предыдущего запроса этого ключа, даже если запросы отправлены
+// в одну и ту же миллисекунду.
+func (c *Client) nonce() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nonce := time.Now().UnixNano() / int64(time.Millisecond)
+	if nonce <= c.lastNonce {
+		nonce = c.lastNonce + 1
+	}
+
+	c.lastNonce = nonce
+	return nonce
+}
+
+// Функция проверяет, имеет ли смысл повторить запрос
+// завершившийся ошибкой err
+func retryable(err error) bool {
+	pErr, ok := interfaces.AsProviderError(err)
+	if !ok {
+		return true
+	}
+
+	return pErr.Temporary()
+}
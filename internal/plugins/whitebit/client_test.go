@@ -0,0 +1,85 @@
+package whitebit_plugin_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Запрос баланса повторяется после ошибки сервера биржи,
+	запрос на вывод средств не повторяется
+*/
+func Test_Plugin_Whitebit_Client_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	params := wb.Account("retry-public", "retry-secret")
+	wb.SetBalance("USDT", "100")
+
+	wb.FailNext(whitebittest.PathBalance, http.StatusServiceUnavailable, `{"message":"Service unavailable"}`)
+	wb.FailNext(whitebittest.PathBalance, http.StatusTooManyRequests, `{"message":"Too many requests"}`)
+
+	_, err := whitebit_plugin.SendRequest(ctx, params, whitebit_plugin.WhitebitbBalance, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, wb.Requests(whitebittest.PathBalance))
+
+	// Ошибки валидации не повторяются
+	_, err = whitebit_plugin.SendRequest(ctx, params, whitebit_plugin.WhitebitHistory, map[string]interface{}{"limit": 0})
+	pErr, ok := interfaces.AsProviderError(err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, pErr.StatusCode)
+
+	wb.FailNext(whitebittest.PathWithdrawPay, http.StatusServiceUnavailable, `{"message":"Service unavailable"}`)
+
+	_, err = whitebit_plugin.SendRequest(ctx, params, whitebit_plugin.WhitebitbWithdrawPay, map[string]interface{}{
+		"ticker":   "USDT",
+		"amount":   "10",
+		"address":  "address",
+		"uniqueId": "1",
+	})
+	pErr, ok = interfaces.AsProviderError(err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, pErr.StatusCode)
+	assert.Zero(t, wb.Requests(whitebittest.PathWithdrawPay))
+	assert.Empty(t, wb.Withdrawals())
+	assert.Equal(t, "100", wb.Balance("USDT"))
+}
+
+/*
+	Параллельные запросы одного ключа получают
+	строго возрастающие nonce и не отклоняются биржей
+*/
+func Test_Plugin_Whitebit_Client_Nonce(t *testing.T) {
+	ctx := context.Background()
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	params := wb.Account("nonce-public", "nonce-secret")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, errs[i] = whitebit_plugin.SendRequest(ctx, params, whitebit_plugin.WhitebitbBalance, map[string]interface{}{})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, len(errs), wb.Requests(whitebittest.PathBalance))
+}
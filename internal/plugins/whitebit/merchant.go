@@ -1,7 +1,8 @@
 package whitebit_plugin
 
 import (
-	"strings"
+	"context"
+	"encoding/json"
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
@@ -15,44 +16,46 @@ func InitMerchant() interfaces.MerchantI {
 }
 
 // Создать адрес для принятия денег
-func (p *WhitebitPluginMerchant) CreateAdress(d, params interface{}) (interface{}, error) {
-	b, err := SendRequest(
-		params.(*models.WhitebitOptionParams),
-		WhitebitCreateNewAddress,
-		PrepareBodyForCreateAdress(d.(*models.ExchangeRequest)),
-	)
+func (p *WhitebitPluginMerchant) CreateAddress(ctx context.Context, params interfaces.OptionParams, r *interfaces.AddressRequest) (*interfaces.Address, error) {
+	op, err := optionParams(params)
 	if err != nil {
 		return nil, err
 	}
 
-	return b, nil
-}
+	b, err := SendRequest(ctx, op, WhitebitCreateNewAddress, PrepareBodyForCreateAdress(r))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp models.WhitebitApiHistory
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
 
-func PrepareBodyForCreateAdress(data *models.ExchangeRequest) map[string]interface{} {
-	var network string
-	networks := []string{AppType.CurrencyNetworkTRC20, AppType.CurrencyNetworkOMNI, AppType.CurrencyNetworkERC20}
+	if resp.Account.Address == "" {
+		return nil, &interfaces.ProviderError{Message: "empty address in response", Raw: b}
+	}
 
-	for i := 0; i < len(networks); i++ {
-		if strings.Contains(data.ExchangeFrom, networks[i]) {
-			network = networks[i]
-		}
+	if r.Currency.MemoRequired && resp.Account.Memo == "" {
+		return nil, &interfaces.ProviderError{Message: "empty memo in response", Raw: b}
 	}
 
-	if network != "" {
-		var t string
-		if network == AppType.CurrencyNetworkOMNI {
-			t = data.ExchangeFrom[:len(network)]
-		} else {
-			t = data.ExchangeFrom[:len(network)-1]
-		}
-
-		return map[string]interface{}{
-			"ticker":  t,
-			"network": network,
-		}
+	return &interfaces.Address{Address: resp.Account.Address, Memo: resp.Account.Memo}, nil
+}
+
+// Тело запроса на создание адреса. Тикер и сеть берутся из каталога
+// валют с учетом кодов, заданных для Whitebit. Сеть передается только
+// для валют, которые существуют в нескольких сетях.
+func PrepareBodyForCreateAdress(r *interfaces.AddressRequest) map[string]interface{} {
+	c := r.Currency.Provider(AppType.MerchantAutoPayoutWhitebit)
+
+	body := map[string]interface{}{
+		"ticker": c.Ticker,
 	}
 
-	return map[string]interface{}{
-		"ticker": data.ExchangeFrom,
+	if c.Network != "" {
+		body["network"] = c.Network
 	}
+
+	return body
 }
@@ -4,46 +4,73 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
-	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_Plugin_Whitebit_Merchant_PrepareBodyForCreateAdress(t *testing.T) {
 	testCases := []struct {
-		data            *models.ExchangeRequest
+		data            *interfaces.AddressRequest
 		expectedNetwork string
 		expectedTicker  string
 	}{
 		{
-			data: &models.ExchangeRequest{
-				ExchangeFrom: "USDTTRC20",
+			data: &interfaces.AddressRequest{
+				Currency: &interfaces.Currency{Code: "USDTTRC20", Ticker: "USDT", Network: AppType.CurrencyNetworkTRC20},
 			},
 			expectedNetwork: AppType.CurrencyNetworkTRC20,
 			expectedTicker:  "USDT",
 		},
 		{
-			data: &models.ExchangeRequest{
-				ExchangeFrom: "USDTOMNI",
+			data: &interfaces.AddressRequest{
+				Currency: &interfaces.Currency{Code: "USDTOMNI", Ticker: "USDT", Network: AppType.CurrencyNetworkOMNI},
 			},
 			expectedNetwork: AppType.CurrencyNetworkOMNI,
 			expectedTicker:  "USDT",
 		},
 		{
-			data: &models.ExchangeRequest{
-				ExchangeFrom: "USDTERC20",
+			data: &interfaces.AddressRequest{
+				Currency: &interfaces.Currency{Code: "USDTERC20", Ticker: "USDT", Network: AppType.CurrencyNetworkERC20},
 			},
 			expectedNetwork: AppType.CurrencyNetworkERC20,
 			expectedTicker:  "USDT",
 		},
+		// Сеть, которую Whitebit называет по-своему
+		{
+			data: &interfaces.AddressRequest{
+				Currency: &interfaces.Currency{
+					Code:    "USDTBEP20",
+					Ticker:  "USDT",
+					Network: "BEP20",
+					Providers: map[string]interfaces.ProviderCurrency{
+						AppType.MerchantAutoPayoutWhitebit: {Network: "BSC"},
+					},
+				},
+			},
+			expectedNetwork: "BSC",
+			expectedTicker:  "USDT",
+		},
+		// Валюта без сети
+		{
+			data: &interfaces.AddressRequest{
+				Currency: &interfaces.Currency{Code: "BTC", Ticker: "BTC"},
+			},
+			expectedNetwork: "",
+			expectedTicker:  "BTC",
+		},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("%d\n", i), func(t *testing.T) {
 			body := whitebit_plugin.PrepareBodyForCreateAdress(tc.data)
 
-			assert.Equal(t, tc.expectedNetwork, body["network"])
+			network, ok := body["network"]
+			assert.Equal(t, tc.expectedNetwork != "", ok)
+			if ok {
+				assert.Equal(t, tc.expectedNetwork, network)
+			}
 			assert.Equal(t, tc.expectedTicker, body["ticker"])
 
 		})
@@ -2,60 +2,65 @@ package whitebit_plugin
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha512"
-	"encoding/base64"
+	"context"
 	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strconv"
-	"time"
 
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 )
 
-// Функция для отправки запроса на конечные точки
-func SendRequest(params *models.WhitebitOptionParams, requestURL string, data map[string]interface{}) ([]byte, error) {
-	// Если одноразовый номер похож на номер предыдущего запроса или меньше
-	// его, будет получено сообщение об ошибке «слишком много запросов»
-
-	nonce := int(time.Now().Unix()) // nonce — это число, которое всегда больше, чем номер предыдущего запроса
-	data["request"] = requestURL
-	data["nonce"] = strconv.Itoa(nonce)
+// Функция для отправки запроса на конечные точки от имени аккаунта params.
+// Если биржа отклонила запрос, возвращается *interfaces.ProviderError
+func SendRequest(ctx context.Context, params *models.WhitebitOptionParams, requestURL string, data map[string]interface{}) ([]byte, error) {
+	return ClientFor(params).Send(ctx, requestURL, data)
+}
 
-	requestBody, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
+// Функция разбирает ответ биржи и возвращает ошибку, если запрос был
+// отклонен. Биржа сообщает об ошибке HTTP статусом и/или объектом
+// с полями code, message и errors.
+func ParseError(statusCode int, b []byte) *interfaces.ProviderError {
+	var body struct {
+		Code    *int            `json:"code"`
+		Message json.RawMessage `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
 	}
 
-	completeURL := params.BaseURL + requestURL
-
-	// Расчет полезной нагрузки
-	payload := base64.StdEncoding.EncodeToString(requestBody)
+	isObject := len(bytes.TrimSpace(b)) > 0 && bytes.TrimSpace(b)[0] == '{'
+	if isObject {
+		if err := json.Unmarshal(b, &body); err != nil {
+			isObject = false
+		}
+	}
 
-	// Вычисление подписи с использованием sha512
-	h := hmac.New(sha512.New, []byte(params.SecretKey))
-	h.Write([]byte(payload))
-	signature := fmt.Sprintf("%x", h.Sum(nil))
+	failed := statusCode >= http.StatusBadRequest ||
+		(isObject && (body.Code != nil || len(body.Message) > 0))
+	if !failed {
+		return nil
+	}
 
-	client := http.Client{}
+	pErr := &interfaces.ProviderError{
+		StatusCode: statusCode,
+		Raw:        b,
+	}
 
-	request, err := http.NewRequest("POST", completeURL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, err
+	if body.Code != nil {
+		pErr.Code = *body.Code
 	}
 
-	request.Header.Set("Content-type", "application/json")
-	request.Header.Set("X-TXC-APIKEY", params.PublicKey)
-	request.Header.Set("X-TXC-PAYLOAD", payload)
-	request.Header.Set("X-TXC-SIGNATURE", signature)
+	if len(body.Message) > 0 {
+		if err := json.Unmarshal(body.Message, &pErr.Message); err != nil {
+			pErr.Message = string(body.Message)
+		}
+	} else {
+		pErr.Message = http.StatusText(statusCode)
+	}
 
-	response, err := client.Do(request)
-	if err != nil {
-		return nil, err
+	if len(body.Errors) > 0 {
+		if err := json.Unmarshal(body.Errors, &pErr.Errors); err != nil {
+			pErr.Errors = map[string][]string{"errors": {string(body.Errors)}}
+		}
 	}
-	defer response.Body.Close()
 
-	return ioutil.ReadAll(response.Body)
+	return pErr
 }
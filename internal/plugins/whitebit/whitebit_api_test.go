@@ -0,0 +1,92 @@
+package whitebit_plugin_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Plugin_Whitebit_ParseError(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		body            string
+		expectedError   bool
+		expectedCode    int
+		expectedMessage string
+		expectedErrors  map[string][]string
+	}{
+		{
+			name:          "withdraw success",
+			statusCode:    http.StatusCreated,
+			body:          `[]`,
+			expectedError: false,
+		},
+		{
+			name:          "balance success",
+			statusCode:    http.StatusOK,
+			body:          `{"main_balance":"0.1"}`,
+			expectedError: false,
+		},
+		{
+			name:            "validation error",
+			statusCode:      http.StatusUnprocessableEntity,
+			body:            `{"code":30,"message":"Validation failed","errors":{"amount":["Amount is too small"]}}`,
+			expectedError:   true,
+			expectedCode:    30,
+			expectedMessage: "Validation failed",
+			expectedErrors:  map[string][]string{"amount": {"Amount is too small"}},
+		},
+		{
+			name:            "error with ok status",
+			statusCode:      http.StatusOK,
+			body:            `{"code":0,"message":"Too many requests"}`,
+			expectedError:   true,
+			expectedMessage: "Too many requests",
+		},
+		{
+			name:            "server error without body",
+			statusCode:      http.StatusBadGateway,
+			body:            ``,
+			expectedError:   true,
+			expectedMessage: http.StatusText(http.StatusBadGateway),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pErr := whitebit_plugin.ParseError(tc.statusCode, []byte(tc.body))
+			if !tc.expectedError {
+				assert.Nil(t, pErr)
+				return
+			}
+
+			assert.NotNil(t, pErr)
+			assert.Equal(t, tc.statusCode, pErr.StatusCode)
+			assert.Equal(t, tc.expectedCode, pErr.Code)
+			assert.Equal(t, tc.expectedMessage, pErr.Message)
+			assert.Equal(t, tc.expectedErrors, pErr.Errors)
+
+			// Ошибка биржи должна отличаться от ошибки соединения
+			_, ok := interfaces.AsProviderError(fmt.Errorf("wrapped: %w", pErr))
+			assert.True(t, ok)
+		})
+	}
+}
+
+func Test_Plugin_Whitebit_HistoryStatus(t *testing.T) {
+	for status, expected := range map[int]interfaces.HistoryStatus{
+		1:  interfaces.HistoryStatusPending,
+		3:  interfaces.HistoryStatusSuccess,
+		4:  interfaces.HistoryStatusFailed,
+		7:  interfaces.HistoryStatusSuccess,
+		9:  interfaces.HistoryStatusFailed,
+		15: interfaces.HistoryStatusPending,
+	} {
+		assert.Equal(t, expected, whitebit_plugin.HistoryStatus(status))
+	}
+}
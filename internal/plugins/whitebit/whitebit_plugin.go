@@ -1,15 +1,24 @@
 package whitebit_plugin
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
 )
 
+func init() {
+	plugins.Register(AppType.MerchantAutoPayoutWhitebit, InitWhitebitPlugin)
+}
+
 type WhitebitPlugin struct {
 	merchant   interfaces.MerchantI
 	autopayout interfaces.AutoPayoutI
@@ -46,52 +55,102 @@ func (plugin *WhitebitPlugin) AutoPayout() interfaces.AutoPayoutI {
 
 // Метод проверки соединение с аккаунтом
 // параметры которого были переданы в params
-// params должен соответствовать типу *models.WhitebitOptionParams
-func (plugin *WhitebitPlugin) Ping(params interface{}) (interface{}, error) {
-	b, err := SendRequest(
-		params.(*models.WhitebitOptionParams),
-		WhitebitbBalance,
-		map[string]interface{}{},
-	)
+func (plugin *WhitebitPlugin) Ping(ctx context.Context, params interfaces.OptionParams) error {
+	p, err := optionParams(params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return b, nil
+	_, err = SendRequest(ctx, p, WhitebitbBalance, map[string]interface{}{})
+	return err
 }
 
 // Метод получения истории транзакций аккаунта
 // параметры которого были переданы в params
-func (plugin *WhitebitPlugin) History(params, body interface{}) (interface{}, error) {
-	b, err := SendRequest(
-		params.(*models.WhitebitOptionParams),
-		WhitebitHistory,
-		body.(map[string]interface{}),
-	)
+func (plugin *WhitebitPlugin) History(ctx context.Context, params interfaces.OptionParams, q *interfaces.HistoryQuery) (*interfaces.History, error) {
+	p, err := optionParams(params)
 	if err != nil {
 		return nil, err
 	}
 
-	return b, nil
+	b, err := SendRequest(ctx, p, WhitebitHistory, PrepareBodyForHistory(q))
+	if err != nil {
+		return nil, err
+	}
+
+	var history models.WhitebitHistory
+	if err := json.Unmarshal(b, &history); err != nil {
+		return nil, err
+	}
+
+	res := &interfaces.History{
+		Limit:   history.Limit,
+		Offset:  history.Offset,
+		Total:   history.Total,
+		Records: make([]*interfaces.HistoryRecord, 0, len(history.Records)),
+	}
+
+	for _, r := range history.Records {
+		record, err := historyRecord(r)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Records = append(res.Records, record)
+	}
+
+	return res, nil
 }
 
-func (plugin *WhitebitPlugin) Balance(params, body interface{}) (interface{}, error) {
-	b, err := SendRequest(
-		params.(*models.WhitebitOptionParams),
-		WhitebitbBalance,
-		body.(map[string]interface{}),
-	)
+// Метод получения баланса аккаунта. Если ticker не передан,
+// возвращается баланс по всем валютам.
+func (plugin *WhitebitPlugin) Balance(ctx context.Context, params interfaces.OptionParams, ticker string) ([]*interfaces.Balance, error) {
+	p, err := optionParams(params)
 	if err != nil {
 		return nil, err
 	}
 
-	return b, err
+	body := map[string]interface{}{}
+	if ticker != "" {
+		body["ticker"] = ticker
+	}
+
+	b, err := SendRequest(ctx, p, WhitebitbBalance, body)
+	if err != nil {
+		return nil, err
+	}
+
+	type mainBalance struct {
+		MainBalance AppMoney.Money `json:"main_balance"`
+	}
+
+	if ticker != "" {
+		var balance mainBalance
+		if err := json.Unmarshal(b, &balance); err != nil {
+			return nil, err
+		}
+
+		return []*interfaces.Balance{{Ticker: ticker, Available: balance.MainBalance}}, nil
+	}
+
+	var balances map[string]mainBalance
+	if err := json.Unmarshal(b, &balances); err != nil {
+		return nil, err
+	}
+
+	arr := make([]*interfaces.Balance, 0, len(balances))
+	for t, balance := range balances {
+		arr = append(arr, &interfaces.Balance{Ticker: t, Available: balance.MainBalance})
+	}
+
+	return arr, nil
 }
 
 // Метод расшифровывает и декодирует опциональные параметры
 // которые храняться в поле Options структуры MerchantAutopayout
-func (plugin *WhitebitPlugin) GetOptionParams(options string) (interface{}, error) {
+func (plugin *WhitebitPlugin) GetOptionParams(options string) (interfaces.OptionParams, error) {
 	var p models.WhitebitOptionParams
+
 	dOptions, err := AppMath.AesDecrypt(options, hex.EncodeToString([]byte(plugin.cfg.AesKey)))
 	if err != nil {
 		return nil, err
@@ -103,3 +162,93 @@ func (plugin *WhitebitPlugin) GetOptionParams(options string) (interface{}, erro
 
 	return &p, nil
 }
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func optionParams(params interfaces.OptionParams) (*models.WhitebitOptionParams, error) {
+	p, ok := params.(*models.WhitebitOptionParams)
+	if !ok || p == nil {
+		return nil, fmt.Errorf("whitebit: unexpected option params type %T", params)
+	}
+
+	return p, nil
+}
+
+func PrepareBodyForHistory(q *interfaces.HistoryQuery) map[string]interface{} {
+	body := map[string]interface{}{
+		"limit":  q.Limit,
+		"offset": q.Offset,
+	}
+
+	if q.Method != 0 {
+		body["transactionMethod"] = int(q.Method)
+	}
+
+	if q.Ticker != "" {
+		body["ticker"] = q.Ticker
+	}
+
+	if q.Address != "" {
+		body["address"] = q.Address
+	}
+
+	if q.UniqueID != "" {
+		body["uniqueId"] = q.UniqueID
+	}
+
+	return body
+}
+
+// Статусы операций биржи: 3 и 7 — успешно, 4 и 9 — отменено/отклонено,
+// остальные — операция в процессе обработки
+func HistoryStatus(status int) interfaces.HistoryStatus {
+	switch status {
+	case 3, 7:
+		return interfaces.HistoryStatusSuccess
+	case 4, 9:
+		return interfaces.HistoryStatusFailed
+	default:
+		return interfaces.HistoryStatusPending
+	}
+}
+
+func historyRecord(r models.WhitebitHistoryRecord) (*interfaces.HistoryRecord, error) {
+	amount, err := AppMoney.NewFromString(r.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("whitebit: invalid amount %q: %w", r.Amount, err)
+	}
+
+	fee := AppMoney.Zero
+	if r.Fee != "" {
+		if fee, err = AppMoney.NewFromString(r.Fee); err != nil {
+			return nil, fmt.Errorf("whitebit: invalid fee %q: %w", r.Fee, err)
+		}
+	}
+
+	record := &interfaces.HistoryRecord{
+		Method:          interfaces.HistoryMethod(r.Method),
+		Status:          HistoryStatus(r.Status),
+		ProviderStatus:  r.Status,
+		Ticker:          r.Ticker,
+		Address:         r.Address,
+		Memo:            r.Memo,
+		Amount:          amount,
+		Fee:             fee,
+		TransactionHash: r.TransactionHash,
+		CreatedAt:       int64(r.CreatedAt),
+	}
+
+	if network, ok := r.Network.(string); ok {
+		record.Network = network
+	}
+
+	if r.UniqueId != nil {
+		record.UniqueID = fmt.Sprint(r.UniqueId)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,509 @@
+package whitebittest
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+/*
+	Имитация приватного API биржи Whitebit для тестов
+
+	Сервер проверяет подпись каждого запроса (X-TXC-APIKEY, X-TXC-PAYLOAD,
+	X-TXC-SIGNATURE) и то, что nonce каждого следующего запроса по ключу
+	строго больше предыдущего. Состояние аккаунта (балансы, адреса,
+	депозиты и выводы) задается из теста.
+*/
+
+// Конечные точки которые обслуживает сервер
+const (
+	PathBalance          = "/api/v4/main-account/balance"
+	PathHistory          = "/api/v4/main-account/history"
+	PathCreateNewAddress = "/api/v4/main-account/create-new-address"
+	PathWithdrawPay      = "/api/v4/main-account/withdraw-pay"
+)
+
+// Статусы операций биржи
+const (
+	StatusPending  = 15
+	StatusSuccess  = 3
+	StatusCanceled = 4
+)
+
+// Методы операций в истории
+const (
+	MethodDeposit  = 1
+	MethodWithdraw = 2
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	keys     map[string]string // публичный ключ -> секретный ключ
+	nonces   map[string]int64  // публичный ключ -> nonce последнего запроса
+	balances map[string]decimal.Decimal
+	records  []*models.WhitebitHistoryRecord
+	failures map[string][]*failure
+	requests map[string]int
+
+	addresses map[string]string // адрес -> тикер
+	memos     map[string]bool   // тикеры с общим адресом и memo
+	sequence  int
+}
+
+type failure struct {
+	statusCode int
+	body       string
+}
+
+// Функция запускает тестовый сервер. Сервер нужно
+// остановить вызовом Close после завершения теста.
+func NewServer() *Server {
+	s := &Server{
+		keys:      make(map[string]string),
+		nonces:    make(map[string]int64),
+		balances:  make(map[string]decimal.Decimal),
+		records:   []*models.WhitebitHistoryRecord{},
+		failures:  make(map[string][]*failure),
+		requests:  make(map[string]int),
+		addresses: make(map[string]string),
+		memos:     make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathBalance, s.handle(s.balance))
+	mux.HandleFunc(PathHistory, s.handle(s.history))
+	mux.HandleFunc(PathCreateNewAddress, s.handle(s.createNewAddress))
+	mux.HandleFunc(PathWithdrawPay, s.handle(s.withdrawPay))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Метод регистрирует пару API ключей и возвращает
+// параметры подключения к аккаунту для плагина
+func (s *Server) Account(publicKey, secretKey string) *models.WhitebitOptionParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[publicKey] = secretKey
+	return &models.WhitebitOptionParams{
+		PublicKey: publicKey,
+		SecretKey: secretKey,
+		BaseURL:   s.URL,
+	}
+}
+
+// Метод устанавливает доступный баланс по тикеру
+func (s *Server) SetBalance(ticker, amount string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balances[ticker] = decimal.RequireFromString(amount)
+}
+
+// Метод возвращает доступный баланс по тикеру
+func (s *Server) Balance(ticker string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.balances[ticker].String()
+}
+
+// Метод добавляет в историю депозит на адрес address
+// и возвращает хеш транзакции. Успешный депозит
+// зачисляется на баланс.
+func (s *Server) Deposit(address, ticker, amount string, status int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.record(MethodDeposit, address, ticker, amount)
+	r.TransactionHash = s.hash()
+	s.setStatus(r, status)
+
+	return r.TransactionHash
+}
+
+// Метод включает memo для тикера ticker. Все адреса тикера
+// совпадают, депозиты различаются только memo.
+func (s *Server) RequireMemo(ticker string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memos[ticker] = true
+}
+
+// Метод добавляет в историю депозит на адрес address с memo
+// и возвращает хеш транзакции
+func (s *Server) DepositMemo(address, memo, ticker, amount string, status int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.record(MethodDeposit, address, ticker, amount)
+	r.Memo = memo
+	r.TransactionHash = s.hash()
+	s.setStatus(r, status)
+
+	return r.TransactionHash
+}
+
+// Метод меняет статус операции найденной по хешу
+// транзакции или по uniqueId вывода
+func (s *Server) SetStatus(id string, status int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.records {
+		if r.TransactionHash == id || (r.UniqueId != nil && fmt.Sprint(r.UniqueId) == id) {
+			s.setStatus(r, status)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("whitebittest: operation %s not found", id)
+}
+
+// Метод возвращает копию всех выводов средств
+func (s *Server) Withdrawals() []models.WhitebitHistoryRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	arr := []models.WhitebitHistoryRecord{}
+	for _, r := range s.records {
+		if r.Method == MethodWithdraw {
+			arr = append(arr, *r)
+		}
+	}
+
+	return arr
+}
+
+// Метод заставляет следующий запрос на конечную
+// точку path завершиться с заданным ответом
+func (s *Server) FailNext(path string, statusCode int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[path] = append(s.failures[path], &failure{statusCode: statusCode, body: body})
+}
+
+// Метод возвращает количество принятых запросов на конечную точку path
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[path]
+}
+
+// Функция подписывает полезную нагрузку так же, как это делает биржа
+func Sign(secretKey, payload string) string {
+	h := hmac.New(sha512.New, []byte(secretKey))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+/*
+	==========================================================================================
+	ОБРАБОТЧИКИ КОНЕЧНЫХ ТОЧЕК
+	==========================================================================================
+*/
+
+func (s *Server) balance(body map[string]interface{}) (int, interface{}) {
+	if ticker, ok := body["ticker"].(string); ok && ticker != "" {
+		return http.StatusOK, map[string]string{"main_balance": s.balances[ticker].String()}
+	}
+
+	res := map[string]map[string]string{}
+	for ticker, amount := range s.balances {
+		res[ticker] = map[string]string{"main_balance": amount.String()}
+	}
+
+	return http.StatusOK, res
+}
+
+func (s *Server) history(body map[string]interface{}) (int, interface{}) {
+	limit, offset := 50, 0
+	if v, ok := body["limit"].(float64); ok {
+		limit = int(v)
+	}
+
+	if v, ok := body["offset"].(float64); ok {
+		offset = int(v)
+	}
+
+	if limit < 1 || limit > 100 || offset < 0 {
+		return validationError("limit", "The limit must be between 1 and 100.")
+	}
+
+	arr := []models.WhitebitHistoryRecord{}
+	for _, r := range s.records {
+		if v, ok := body["transactionMethod"].(float64); ok && int(v) != r.Method {
+			continue
+		}
+
+		if v, ok := body["ticker"].(string); ok && v != r.Ticker {
+			continue
+		}
+
+		if v, ok := body["address"].(string); ok && v != r.Address {
+			continue
+		}
+
+		if v, ok := body["uniqueId"].(string); ok && (r.UniqueId == nil || v != fmt.Sprint(r.UniqueId)) {
+			continue
+		}
+
+		arr = append(arr, *r)
+	}
+
+	// Биржа отдает сначала самые новые операции
+	sort.SliceStable(arr, func(i, j int) bool { return arr[i].CreatedAt > arr[j].CreatedAt })
+
+	res := &models.WhitebitHistory{
+		Limit:   limit,
+		Offset:  offset,
+		Total:   len(arr),
+		Records: []models.WhitebitHistoryRecord{},
+	}
+
+	if offset < len(arr) {
+		end := offset + limit
+		if end > len(arr) {
+			end = len(arr)
+		}
+		res.Records = arr[offset:end]
+	}
+
+	return http.StatusOK, res
+}
+
+func (s *Server) createNewAddress(body map[string]interface{}) (int, interface{}) {
+	ticker, _ := body["ticker"].(string)
+	if ticker == "" {
+		return validationError("ticker", "The ticker field is required.")
+	}
+
+	network, _ := body["network"].(string)
+
+	s.sequence++
+	res := &models.WhitebitApiHistory{}
+	if s.memos[ticker] {
+		res.Account.Address = fmt.Sprintf("%s%s", ticker, network)
+		res.Account.Memo = fmt.Sprintf("%08d", s.sequence)
+	} else {
+		res.Account.Address = fmt.Sprintf("%s%s%08d", ticker, network, s.sequence)
+	}
+	s.addresses[res.Account.Address] = ticker
+
+	return http.StatusOK, res
+}
+
+func (s *Server) withdrawPay(body map[string]interface{}) (int, interface{}) {
+	ticker, _ := body["ticker"].(string)
+	address, _ := body["address"].(string)
+	uniqueID, _ := body["uniqueId"].(string)
+	amountStr, _ := body["amount"].(string)
+
+	switch {
+	case ticker == "":
+		return validationError("ticker", "The ticker field is required.")
+	case address == "":
+		return validationError("address", "The address field is required.")
+	case uniqueID == "":
+		return validationError("uniqueId", "The unique id field is required.")
+	}
+
+	amount, err := decimal.NewFromString(amountStr)
+	if err != nil || !amount.IsPositive() {
+		return validationError("amount", "The amount must be a positive number.")
+	}
+
+	for _, r := range s.records {
+		if r.Method == MethodWithdraw && r.UniqueId != nil && fmt.Sprint(r.UniqueId) == uniqueID {
+			return validationError("uniqueId", "The unique id has already been taken.")
+		}
+	}
+
+	if s.balances[ticker].LessThan(amount) {
+		return validationError("amount", "Not enough money.")
+	}
+
+	s.balances[ticker] = s.balances[ticker].Sub(amount)
+
+	r := s.record(MethodWithdraw, address, ticker, amount.String())
+	r.UniqueId = uniqueID
+	if network, ok := body["network"].(string); ok {
+		r.Network = network
+	}
+
+	if memo, ok := body["memo"].(string); ok {
+		r.Memo = memo
+	}
+
+	return http.StatusCreated, []interface{}{}
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Обертка проверяет подпись и nonce запроса, отдает
+// запланированные ошибки и сериализует ответ обработчика
+func (s *Server) handle(fn func(body map[string]interface{}) (int, interface{})) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid body")
+			return
+		}
+
+		body, statusCode, message := s.authorize(r, b)
+		if message != "" {
+			writeError(w, statusCode, message)
+			return
+		}
+
+		if arr := s.failures[r.URL.Path]; len(arr) > 0 {
+			s.failures[r.URL.Path] = arr[1:]
+			w.WriteHeader(arr[0].statusCode)
+			w.Write([]byte(arr[0].body))
+			return
+		}
+
+		s.requests[r.URL.Path]++
+
+		statusCode, res := fn(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// Проверка подписи запроса по правилам биржи
+func (s *Server) authorize(r *http.Request, b []byte) (map[string]interface{}, int, string) {
+	secretKey, ok := s.keys[r.Header.Get("X-TXC-APIKEY")]
+	if !ok {
+		return nil, http.StatusUnauthorized, "Invalid api key"
+	}
+
+	payload := r.Header.Get("X-TXC-PAYLOAD")
+	if payload != base64.StdEncoding.EncodeToString(b) {
+		return nil, http.StatusUnauthorized, "Invalid payload"
+	}
+
+	if !hmac.Equal([]byte(r.Header.Get("X-TXC-SIGNATURE")), []byte(Sign(secretKey, payload))) {
+		return nil, http.StatusUnauthorized, "Invalid signature"
+	}
+
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil, http.StatusBadRequest, "Invalid body"
+	}
+
+	if body["request"] != r.URL.Path {
+		return nil, http.StatusUnauthorized, "Invalid request path in payload"
+	}
+
+	nonce, err := strconv.ParseInt(fmt.Sprint(body["nonce"]), 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, "Nonce not provided"
+	}
+
+	publicKey := r.Header.Get("X-TXC-APIKEY")
+	if nonce <= s.nonces[publicKey] {
+		return nil, http.StatusTooManyRequests, "Too many requests"
+	}
+	s.nonces[publicKey] = nonce
+
+	return body, 0, ""
+}
+
+func (s *Server) record(method int, address, ticker, amount string) *models.WhitebitHistoryRecord {
+	s.sequence++
+
+	r := &models.WhitebitHistoryRecord{
+		Address:   address,
+		Amount:    decimal.RequireFromString(amount).String(),
+		CreatedAt: int(time.Now().Unix()) + s.sequence,
+		Currency:  ticker,
+		Fee:       "0",
+		Method:    method,
+		Status:    StatusPending,
+		Ticker:    ticker,
+	}
+
+	s.records = append(s.records, r)
+	return r
+}
+
+// Смена статуса операции. Депозит зачисляется на баланс
+// в момент перехода в успешный статус, у вывода в этот
+// момент появляется хеш транзакции.
+func (s *Server) setStatus(r *models.WhitebitHistoryRecord, status int) {
+	if r.Status != StatusSuccess && status == StatusSuccess {
+		switch r.Method {
+		case MethodDeposit:
+			s.balances[r.Ticker] = s.balances[r.Ticker].Add(decimal.RequireFromString(r.Amount))
+		case MethodWithdraw:
+			if r.TransactionHash == "" {
+				r.TransactionHash = s.hash()
+			}
+		}
+	}
+
+	// Отмененный вывод возвращается на баланс
+	if r.Method == MethodWithdraw && r.Status != StatusCanceled && status == StatusCanceled {
+		s.balances[r.Ticker] = s.balances[r.Ticker].Add(decimal.RequireFromString(r.Amount))
+	}
+
+	r.Status = status
+}
+
+func (s *Server) hash() string {
+	s.sequence++
+
+	h := sha512.Sum512([]byte(strconv.Itoa(s.sequence)))
+	return hex.EncodeToString(h[:32])
+}
+
+func validationError(field, message string) (int, interface{}) {
+	return http.StatusUnprocessableEntity, map[string]interface{}{
+		"code":    30,
+		"message": "Validation failed",
+		"errors":  map[string][]string{field: {message}},
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    0,
+		"message": message,
+	})
+}
@@ -0,0 +1,239 @@
+package whitebittest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Whitebittest_Balance(t *testing.T) {
+	s := whitebittest.NewServer()
+	defer s.Close()
+
+	p := s.Account("public", "secret")
+	s.SetBalance("USDT", "150.5")
+	s.SetBalance("BTC", "0.1")
+
+	plugin := whitebit_plugin.InitWhitebitPlugin(&config.PluginsConfig{})
+
+	assert.NoError(t, plugin.Ping(context.Background(), p))
+
+	balance, err := plugin.Balance(context.Background(), p, "USDT")
+	assert.NoError(t, err)
+	assert.Len(t, balance, 1)
+	assert.Equal(t, "150.5", balance[0].Available.String())
+
+	all, err := plugin.Balance(context.Background(), p, "")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func Test_Whitebittest_DepositHistory(t *testing.T) {
+	s := whitebittest.NewServer()
+	defer s.Close()
+
+	p := s.Account("public", "secret")
+	plugin := whitebit_plugin.InitWhitebitPlugin(&config.PluginsConfig{})
+
+	addr, err := plugin.Merchant().CreateAddress(context.Background(), p, &interfaces.AddressRequest{
+		Currency: &interfaces.Currency{Code: "USDTTRC20", Ticker: "USDT", Network: "TRC20"},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, addr.Address)
+
+	hash := s.Deposit(addr.Address, "USDT", "100", whitebittest.StatusPending)
+	assert.Equal(t, "0", s.Balance("USDT"))
+
+	history, err := plugin.History(context.Background(), p, &interfaces.HistoryQuery{Limit: 100})
+	assert.NoError(t, err)
+	assert.Len(t, history.Records, 1)
+	assert.Equal(t, interfaces.HistoryDeposit, history.Records[0].Method)
+	assert.Equal(t, interfaces.HistoryStatusPending, history.Records[0].Status)
+	assert.Equal(t, hash, history.Records[0].TransactionHash)
+
+	// Подтвержденный депозит зачисляется на баланс
+	assert.NoError(t, s.SetStatus(hash, whitebittest.StatusSuccess))
+	assert.Equal(t, "100", s.Balance("USDT"))
+
+	history, err = plugin.History(context.Background(), p, &interfaces.HistoryQuery{
+		Method:  interfaces.HistoryDeposit,
+		Address: addr.Address,
+		Limit:   100,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, history.Records, 1)
+	assert.Equal(t, interfaces.HistoryStatusSuccess, history.Records[0].Status)
+	assert.True(t, AppMoney.NewFromInt(100).Equal(history.Records[0].Amount))
+}
+
+func Test_Whitebittest_WithdrawPay(t *testing.T) {
+	s := whitebittest.NewServer()
+	defer s.Close()
+
+	p := s.Account("public", "secret")
+	s.SetBalance("USDT", "100")
+
+	plugin := whitebit_plugin.InitWhitebitPlugin(&config.PluginsConfig{})
+	r := &interfaces.PayoutRequest{
+		Currency: &interfaces.Currency{Code: "USDTTRC20", Ticker: "USDT", Network: "TRC20"},
+		Address:  "TClientAddress",
+		Amount:   AppMoney.NewFromInt(40),
+		UniqueID: "1",
+	}
+
+	_, err := plugin.AutoPayout().Payout(context.Background(), p, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "60", s.Balance("USDT"))
+
+	withdrawals := s.Withdrawals()
+	assert.Len(t, withdrawals, 1)
+	assert.Equal(t, "TClientAddress", withdrawals[0].Address)
+	assert.Equal(t, whitebittest.StatusPending, withdrawals[0].Status)
+
+	// Повторный вывод с тем же uniqueId отклоняется как уже принятый
+	_, err = plugin.AutoPayout().Payout(context.Background(), p, r)
+	assert.ErrorIs(t, err, AppError.ErrPayoutDuplicate)
+	assert.Len(t, s.Withdrawals(), 1)
+
+	// Недостаточно средств
+	r.UniqueID, r.Amount = "2", AppMoney.NewFromInt(100)
+	_, err = plugin.AutoPayout().Payout(context.Background(), p, r)
+	pErr, ok := interfaces.AsProviderError(err)
+	assert.True(t, ok)
+	assert.Contains(t, pErr.Errors, "amount")
+
+	assert.NoError(t, s.SetStatus("1", whitebittest.StatusSuccess))
+	assert.NotEmpty(t, s.Withdrawals()[0].TransactionHash)
+}
+
+func Test_Whitebittest_FailNext(t *testing.T) {
+	s := whitebittest.NewServer()
+	defer s.Close()
+
+	p := s.Account("public", "secret")
+	plugin := whitebit_plugin.InitWhitebitPlugin(&config.PluginsConfig{})
+
+	// Запрос баланса повторяется клиентом, ошибки
+	// отдаются по очереди на каждую попытку
+	for i := 0; i <= whitebit_plugin.MaxRetries; i++ {
+		s.FailNext(whitebittest.PathBalance, http.StatusServiceUnavailable, ``)
+	}
+
+	err := plugin.Ping(context.Background(), p)
+	pErr, ok := interfaces.AsProviderError(err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, pErr.StatusCode)
+
+	assert.NoError(t, plugin.Ping(context.Background(), p))
+	assert.Equal(t, 1, s.Requests(whitebittest.PathBalance))
+}
+
+func Test_Whitebittest_Authorization(t *testing.T) {
+	s := whitebittest.NewServer()
+	defer s.Close()
+
+	s.Account("public", "secret")
+
+	testCases := []struct {
+		name         string
+		publicKey    string
+		secretKey    string
+		request      string
+		nonce        string
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			publicKey:    "public",
+			secretKey:    "secret",
+			request:      whitebittest.PathBalance,
+			nonce:        "1000",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "same nonce",
+			publicKey:    "public",
+			secretKey:    "secret",
+			request:      whitebittest.PathBalance,
+			nonce:        "1000",
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "smaller nonce",
+			publicKey:    "public",
+			secretKey:    "secret",
+			request:      whitebittest.PathBalance,
+			nonce:        "999",
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "unknown api key",
+			publicKey:    "unknown",
+			secretKey:    "secret",
+			request:      whitebittest.PathBalance,
+			nonce:        "1001",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "invalid signature",
+			publicKey:    "public",
+			secretKey:    "wrong",
+			request:      whitebittest.PathBalance,
+			nonce:        "1002",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "request path mismatch",
+			publicKey:    "public",
+			secretKey:    "secret",
+			request:      whitebittest.PathHistory,
+			nonce:        "1003",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "next nonce",
+			publicKey:    "public",
+			secretKey:    "secret",
+			request:      whitebittest.PathBalance,
+			nonce:        "1004",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(map[string]interface{}{
+				"request": tc.request,
+				"nonce":   tc.nonce,
+			})
+			assert.NoError(t, err)
+
+			payload := base64.StdEncoding.EncodeToString(b)
+
+			req, err := http.NewRequest(http.MethodPost, s.URL+whitebittest.PathBalance, bytes.NewBuffer(b))
+			assert.NoError(t, err)
+
+			req.Header.Set("Content-type", "application/json")
+			req.Header.Set("X-TXC-APIKEY", tc.publicKey)
+			req.Header.Set("X-TXC-PAYLOAD", payload)
+			req.Header.Set("X-TXC-SIGNATURE", whitebittest.Sign(tc.secretKey, payload))
+
+			res, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Выбор аккаунта мерчанта или автовыплат для направления обмена.
+//
+// Аккаунт выбирается среди активных привязок направления способом,
+// заданным в направлении отдельно для мерчантов и автовыплат.
+// Состояние взвешенного round-robin и балансы аккаунтов автовыплат
+// хранятся в памяти, балансы переиспользуются в течение
+// cfg.BalanceCacheTTL секунд.
+type Selector struct {
+	store  db.SQLStoreI
+	plugin *plugins.AppPlugins
+	cfg    *config.AccountsConfig
+	logger utils.LoggerI
+
+	mu       sync.Mutex
+	current  map[rrKey]int
+	balances map[string]*balance
+}
+
+type SelectorI interface {
+	Merchant(ctx context.Context, d *models.Direction, service string) (*models.MerchantAutopayout, error)
+	Autopayout(ctx context.Context, d *models.Direction, currency *AppInterfaces.Currency, amount AppMoney.Money) (*models.MerchantAutopayout, error)
+}
+
+// Привязка в состоянии взвешенного round-robin. У аккаунтов,
+// выбираемых без привязки к направлению, binding равен 0.
+type rrKey struct {
+	binding int
+	account int
+}
+
+// Доступный баланс аккаунта и время его получения
+type balance struct {
+	available AppMoney.Money
+	at        time.Time
+}
+
+func InitSelector(s db.SQLStoreI, p *plugins.AppPlugins, cfg *config.AccountsConfig, l utils.LoggerI) SelectorI {
+	return &Selector{
+		store:  s,
+		plugin: p,
+		cfg:    cfg,
+		logger: l,
+
+		current:  map[rrKey]int{},
+		balances: map[string]*balance{},
+	}
+}
+
+// Метод выбирает аккаунт мерчанта сервиса service
+// для приема средств по направлению d
+func (s *Selector) Merchant(ctx context.Context, d *models.Direction, service string) (*models.MerchantAutopayout, error) {
+	bindings, err := s.store.AdminPanel().Directions().Ma().GetActiveBindings(d.ID, AppType.UseAsMerchant)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := []*models.DirectionAccount{}
+	for _, da := range bindings {
+		if da.Account.Service == service {
+			arr = append(arr, da)
+		}
+	}
+
+	if da := s.choose(d.MerchantPolicy, arr); da != nil {
+		return da.Account, nil
+	}
+
+	return nil, AppError.ErrNoMerchantAutopatout
+}
+
+// Метод выбирает аккаунт для выплаты суммы amount в валюте currency по
+// направлению d. Баланс аккаунта запрашивается в тикере его платежной
+// системы. Если у направления нет привязок автовыплат, выбор
+// идет среди всех активных аккаунтов автовыплат. Учитываются только
+// аккаунты с подключенным плагином.
+func (s *Selector) Autopayout(ctx context.Context, d *models.Direction, currency *AppInterfaces.Currency, amount AppMoney.Money) (*models.MerchantAutopayout, error) {
+	bindings := []*models.DirectionAccount{}
+	if d.ID != 0 {
+		arr, err := s.store.AdminPanel().Directions().Ma().GetActiveBindings(d.ID, AppType.UseAsAutoPayout)
+		if err != nil {
+			return nil, err
+		}
+		bindings = arr
+	}
+
+	if len(bindings) == 0 {
+		arr, err := s.store.AdminPanel().MerchantAutopayout().GetAllByServiceType(AppType.UseAsAutoPayout, true)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ma := range arr {
+			bindings = append(bindings, &models.DirectionAccount{
+				Binding: &models.DirectionMA{MaID: ma.ID, Weight: 1},
+				Account: ma,
+			})
+		}
+	}
+
+	arr := []*models.DirectionAccount{}
+	for _, da := range bindings {
+		if _, err := s.plugin.Get(da.Account.Service); err == nil {
+			arr = append(arr, da)
+		}
+	}
+
+	if d.AutopayoutPolicy == AppType.SelectionBalance {
+		for _, da := range arr {
+			ticker := currency.Provider(da.Account.Service).Ticker
+			available, err := s.balance(ctx, da.Account, ticker)
+			if err != nil {
+				s.log(fmt.Sprintf("balance %s | account %d: %s", ticker, da.Account.ID, err.Error()))
+				continue
+			}
+
+			if amount.LessThanOrEqual(available) {
+				s.spend(da.Account, ticker, amount)
+				return da.Account, nil
+			}
+		}
+
+		if len(arr) > 0 {
+			return nil, fmt.Errorf("%w | %s %s", AppError.ErrNotEnoughBalance, amount.String(), currency.Code)
+		}
+
+		return nil, AppError.ErrNoAutopayoutAccount
+	}
+
+	if da := s.choose(d.AutopayoutPolicy, arr); da != nil {
+		return da.Account, nil
+	}
+
+	return nil, AppError.ErrNoAutopayoutAccount
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Метод выбирает привязку из arr, упорядоченного по
+// приоритету, способом policy. Для пустого arr вернет nil.
+func (s *Selector) choose(policy string, arr []*models.DirectionAccount) *models.DirectionAccount {
+	if len(arr) == 0 {
+		return nil
+	}
+
+	if policy != AppType.SelectionWeighted {
+		return arr[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Плавный взвешенный round-robin: каждая привязка набирает свой
+	// вес, выбранная привязка теряет суммарный вес всех привязок
+	var best *models.DirectionAccount
+	total := 0
+	for _, da := range arr {
+		w := da.Binding.Weight
+		if w < 1 {
+			w = 1
+		}
+
+		k := rrKey{binding: da.Binding.ID, account: da.Account.ID}
+		total += w
+		s.current[k] += w
+		if best == nil || s.current[k] > s.current[rrKey{binding: best.Binding.ID, account: best.Account.ID}] {
+			best = da
+		}
+	}
+
+	s.current[rrKey{binding: best.Binding.ID, account: best.Account.ID}] -= total
+	return best
+}
+
+// Метод возвращает доступный баланс аккаунта ma в валюте ticker
+func (s *Selector) balance(ctx context.Context, ma *models.MerchantAutopayout, ticker string) (AppMoney.Money, error) {
+	k := fmt.Sprintf("%d/%s", ma.ID, ticker)
+	ttl := time.Duration(s.cfg.BalanceCacheTTL) * time.Second
+
+	s.mu.Lock()
+	if b, ok := s.balances[k]; ok && time.Since(b.at) < ttl {
+		available := b.available
+		s.mu.Unlock()
+		return available, nil
+	}
+	s.mu.Unlock()
+
+	plugin, err := s.plugin.Get(ma.Service)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	params, err := plugin.GetOptionParams(ma.Options)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	arr, err := plugin.Balance(ctx, params, ticker)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	available := AppMoney.Zero
+	for _, b := range arr {
+		available = available.Add(b.Available)
+	}
+
+	s.mu.Lock()
+	s.balances[k] = &balance{available: available, at: time.Now()}
+	s.mu.Unlock()
+
+	return available, nil
+}
+
+// Метод уменьшает закешированный баланс аккаунта ma на выбранную
+// выплату amount, чтобы до обновления кеша следующие выплаты
+// не рассчитывали на уже занятые средства
+func (s *Selector) spend(ma *models.MerchantAutopayout, ticker string, amount AppMoney.Money) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b, ok := s.balances[fmt.Sprintf("%d/%s", ma.ID, ticker)]; ok {
+		b.available = b.available.Sub(amount)
+	}
+}
+
+func (s *Selector) log(info string) {
+	s.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  AppType.LogModuleListener,
+		Info:    info,
+	})
+}
@@ -0,0 +1,166 @@
+package accounts
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+
+	// Подключение плагинов мерчантов/автовыплат
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+)
+
+/*
+	Аккаунт мерчанта выбирается по приоритету привязки
+	либо взвешенным round-robin
+*/
+func Test_Accounts_Merchant(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	s := testSelector(store, cfg)
+
+	d := &models.Direction{ExchangeFrom: "USDTTRC20", ExchangeTo: "USDT", Status: true}
+	assert.NoError(t, store.AdminPanel().Directions().Create(d))
+
+	heavy := testAccount(t, store, cfg, AppType.UseAsMerchant, wb.Account("heavy-public", "heavy-secret"))
+	light := testAccount(t, store, cfg, AppType.UseAsMerchant, wb.Account("light-public", "light-secret"))
+	testBinding(t, store, d, heavy, AppType.UseAsMerchant, 1, 2)
+	testBinding(t, store, d, light, AppType.UseAsMerchant, 0, 1)
+
+	// По приоритету всегда выбирается привязка с меньшим приоритетом
+	for i := 0; i < 3; i++ {
+		ma, err := s.Merchant(ctx, d, AppType.MerchantAutoPayoutWhitebit)
+		assert.NoError(t, err)
+		assert.Equal(t, light.ID, ma.ID)
+	}
+
+	// Выбор распределяется пропорционально весам
+	d.MerchantPolicy = AppType.SelectionWeighted
+	picks := map[int]int{}
+	for i := 0; i < 6; i++ {
+		ma, err := s.Merchant(ctx, d, AppType.MerchantAutoPayoutWhitebit)
+		assert.NoError(t, err)
+		picks[ma.ID]++
+	}
+	assert.Equal(t, 4, picks[heavy.ID])
+	assert.Equal(t, 2, picks[light.ID])
+
+	// Аккаунтов другого сервиса у направления нет
+	_, err := s.Merchant(ctx, d, AppType.MerchantAutoPayoutMine)
+	assert.ErrorIs(t, err, AppError.ErrNoMerchantAutopatout)
+}
+
+/*
+	Аккаунт автовыплат выбирается с учетом баланса,
+	баланс запрашивается не чаще чем раз в BalanceCacheTTL
+*/
+func Test_Accounts_AutopayoutBalance(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	empty := whitebittest.NewServer()
+	defer empty.Close()
+	empty.SetBalance("USDT", "10")
+
+	full := whitebittest.NewServer()
+	defer full.Close()
+	full.SetBalance("USDT", "150")
+
+	store := mocksqlstore.Init()
+	s := testSelector(store, cfg)
+
+	d := &models.Direction{ExchangeFrom: "USDTTRC20", ExchangeTo: "USDT", Status: true, AutopayoutPolicy: AppType.SelectionBalance}
+	assert.NoError(t, store.AdminPanel().Directions().Create(d))
+
+	first := testAccount(t, store, cfg, AppType.UseAsAutoPayout, empty.Account("empty-public", "empty-secret"))
+	second := testAccount(t, store, cfg, AppType.UseAsAutoPayout, full.Account("full-public", "full-secret"))
+	testBinding(t, store, d, first, AppType.UseAsAutoPayout, 0, 1)
+	testBinding(t, store, d, second, AppType.UseAsAutoPayout, 1, 1)
+
+	usdt := &AppInterfaces.Currency{Code: "USDT", Ticker: "USDT"}
+
+	// Первого по приоритету аккаунта хватает на небольшую выплату
+	ma, err := s.Autopayout(ctx, d, usdt, AppMoney.NewFromInt(5))
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, ma.ID)
+
+	// Остатка на первом аккаунте уже не хватает
+	ma, err = s.Autopayout(ctx, d, usdt, AppMoney.NewFromInt(100))
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, ma.ID)
+
+	ma, err = s.Autopayout(ctx, d, usdt, AppMoney.NewFromInt(6))
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, ma.ID)
+
+	// Ни одного аккаунта с достаточным балансом
+	_, err = s.Autopayout(ctx, d, usdt, AppMoney.NewFromInt(100))
+	assert.ErrorIs(t, err, AppError.ErrNotEnoughBalance)
+
+	// Балансы запрошены у биржи по одному разу
+	assert.Equal(t, 1, empty.Requests(whitebittest.PathBalance))
+	assert.Equal(t, 1, full.Requests(whitebittest.PathBalance))
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func testSelector(store db.SQLStoreI, cfg *config.Config) SelectorI {
+	return InitSelector(store, plugins.InitAppPlugins(&cfg.Plugins), &cfg.Accounts, utils.InitLogger(store.AdminPanel().Logs()))
+}
+
+func testAccount(t *testing.T, store db.SQLStoreI, cfg *config.Config, serviceType int, p *models.WhitebitOptionParams) *models.MerchantAutopayout {
+	t.Helper()
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+
+	options, err := AppMath.AesEncrypt(string(b), hex.EncodeToString([]byte(cfg.Plugins.AesKey)))
+	assert.NoError(t, err)
+
+	m := &models.MerchantAutopayout{
+		Name:        p.PublicKey,
+		Service:     AppType.MerchantAutoPayoutWhitebit,
+		ServiceType: serviceType,
+		Options:     options,
+		Status:      true,
+	}
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Create(m))
+
+	return m
+}
+
+func testBinding(t *testing.T, store db.SQLStoreI, d *models.Direction, ma *models.MerchantAutopayout, serviceType, priority, weight int) {
+	t.Helper()
+
+	assert.NoError(t, store.AdminPanel().Directions().Ma().Create(&models.DirectionMA{
+		DirectionID: d.ID,
+		MaID:        ma.ID,
+		ServiceType: serviceType,
+		Status:      true,
+		Priority:    priority,
+		Weight:      weight,
+	}))
+}
@@ -0,0 +1,49 @@
+package mocksqlstore
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type AccountHealthRepository struct {
+	mu     sync.Mutex
+	health map[int]*models.AccountHealth
+}
+
+func (r *AccountHealthRepository) Get(h *models.AccountHealth) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := r.health[h.MaID]; v != nil {
+		*h = *v
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *AccountHealthRepository) Save(h *models.AccountHealth) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v := *h
+	r.health[h.MaID] = &v
+	return nil
+}
+
+func (r *AccountHealthRepository) GetAll() ([]*models.AccountHealth, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	arr := make([]*models.AccountHealth, 0, len(r.health))
+	for _, h := range r.health {
+		v := *h
+		arr = append(arr, &v)
+	}
+
+	sort.Slice(arr, func(i, j int) bool { return arr[i].MaID < arr[j].MaID })
+	return arr, nil
+}
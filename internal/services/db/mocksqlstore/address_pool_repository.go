@@ -0,0 +1,58 @@
+package mocksqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type AddressPoolRepository struct {
+	pool   map[int]*models.PoolAddress
+	nextID int
+}
+
+func (r *AddressPoolRepository) Release(a *models.PoolAddress) error {
+	for _, p := range r.pool {
+		if p.MaID == a.MaID && p.Currency == a.Currency && p.Address == a.Address {
+			return nil
+		}
+	}
+
+	r.nextID++
+	r.pool[r.nextID] = &models.PoolAddress{
+		ID:         r.nextID,
+		MaID:       a.MaID,
+		Currency:   a.Currency,
+		Address:    a.Address,
+		ReleasedAt: time.Now().UTC().Format(core.DateStandart),
+		CreatedAt:  time.Now().UTC().Format(core.DateStandart),
+	}
+
+	return nil
+}
+
+func (r *AddressPoolRepository) Acquire(a *models.PoolAddress, cooldown int) error {
+	border := time.Now().UTC().Add(-time.Duration(cooldown) * time.Minute)
+
+	first := 0
+	for id, p := range r.pool {
+		released, _ := time.Parse(core.DateStandart, p.ReleasedAt)
+		if released.After(border) {
+			continue
+		}
+
+		if p.MaID == a.MaID && p.Currency == a.Currency && (first == 0 || id < first) {
+			first = id
+		}
+	}
+
+	if first == 0 {
+		return sql.ErrNoRows
+	}
+
+	*a = *r.pool[first]
+	delete(r.pool, first)
+	return nil
+}
@@ -6,14 +6,22 @@ import (
 )
 
 type AdminPanelRepository struct {
-	botMessagesRepository        *BotMessagesRepository
-	notificationRepository       *NotificationRepository
-	exchangerRepository          *ExchangerRepository
-	userBillsRepository          *UserBillsRepository
-	logsRepository               *LoggerRepository
-	merchantAutopayoutRepository *MerchantAutopayoutRepository
-	exchangeRequestRepository    *ExchangeRequestRepository
-	directionsRepository         *DirectionsRepository
+	botMessagesRepository          *BotMessagesRepository
+	notificationRepository         *NotificationRepository
+	exchangerRepository            *ExchangerRepository
+	userBillsRepository            *UserBillsRepository
+	logsRepository                 *LoggerRepository
+	merchantAutopayoutRepository   *MerchantAutopayoutRepository
+	exchangeRequestRepository      *ExchangeRequestRepository
+	directionsRepository           *DirectionsRepository
+	payoutRepository               *PayoutRepository
+	requestStatusHistoryRepository *RequestStatusHistoryRepository
+	addressPoolRepository          *AddressPoolRepository
+	rateHistoryRepository          *RateHistoryRepository
+	reportJobRepository            *ReportJobRepository
+	historyCursorRepository        *HistoryCursorRepository
+	accountHealthRepository        *AccountHealthRepository
+	currencyRepository             *CurrencyRepository
 }
 
 func (r *AdminPanelRepository) Logs() db.LoggerRepository {
@@ -82,7 +90,8 @@ func (r *AdminPanelRepository) MerchantAutopayout() db.MerchantAutopayoutReposit
 	}
 
 	r.merchantAutopayoutRepository = &MerchantAutopayoutRepository{
-		ma: make(map[int]*models.MerchantAutopayout),
+		ma:      make(map[int]*models.MerchantAutopayout),
+		payouts: r.Payout().(*PayoutRepository),
 	}
 
 	return r.merchantAutopayoutRepository
@@ -94,7 +103,9 @@ func (r *AdminPanelRepository) ExchangeRequest() db.ExchangeRequestRepository {
 	}
 
 	r.exchangeRequestRepository = &ExchangeRequestRepository{
-		er: make(map[int]*models.ExchangeRequest),
+		er:         make(map[int]*models.ExchangeRequest),
+		history:    r.RequestStatusHistory().(*RequestStatusHistoryRepository),
+		directions: r.Directions().(*DirectionsRepository),
 	}
 
 	return r.exchangeRequestRepository
@@ -106,8 +117,106 @@ func (r *AdminPanelRepository) Directions() db.DirectionsRepository {
 	}
 
 	r.directionsRepository = &DirectionsRepository{
-		directions: make(map[int]*models.BotMessage),
+		directions: make(map[int]*models.Direction),
+		ma:         r.MerchantAutopayout().(*MerchantAutopayoutRepository),
 	}
 
 	return r.directionsRepository
 }
+
+func (r *AdminPanelRepository) Payout() db.PayoutRepository {
+	if r.payoutRepository != nil {
+		return r.payoutRepository
+	}
+
+	r.payoutRepository = &PayoutRepository{
+		payouts: make(map[int]*models.Payout),
+	}
+
+	return r.payoutRepository
+}
+
+func (r *AdminPanelRepository) RequestStatusHistory() db.RequestStatusHistoryRepository {
+	if r.requestStatusHistoryRepository != nil {
+		return r.requestStatusHistoryRepository
+	}
+
+	r.requestStatusHistoryRepository = &RequestStatusHistoryRepository{
+		history: make(map[int]*models.RequestStatusHistory),
+	}
+
+	return r.requestStatusHistoryRepository
+}
+
+func (r *AdminPanelRepository) AddressPool() db.AddressPoolRepository {
+	if r.addressPoolRepository != nil {
+		return r.addressPoolRepository
+	}
+
+	r.addressPoolRepository = &AddressPoolRepository{
+		pool: make(map[int]*models.PoolAddress),
+	}
+
+	return r.addressPoolRepository
+}
+
+func (r *AdminPanelRepository) RateHistory() db.RateHistoryRepository {
+	if r.rateHistoryRepository != nil {
+		return r.rateHistoryRepository
+	}
+
+	r.rateHistoryRepository = &RateHistoryRepository{
+		history: make(map[int]*models.RateHistory),
+	}
+
+	return r.rateHistoryRepository
+}
+
+func (r *AdminPanelRepository) ReportJob() db.ReportJobRepository {
+	if r.reportJobRepository != nil {
+		return r.reportJobRepository
+	}
+
+	r.reportJobRepository = &ReportJobRepository{
+		jobs: make(map[int]*models.ReportJob),
+	}
+
+	return r.reportJobRepository
+}
+
+func (r *AdminPanelRepository) HistoryCursor() db.HistoryCursorRepository {
+	if r.historyCursorRepository != nil {
+		return r.historyCursorRepository
+	}
+
+	r.historyCursorRepository = &HistoryCursorRepository{
+		cursors: make(map[int]*models.HistoryCursor),
+	}
+
+	return r.historyCursorRepository
+}
+
+func (r *AdminPanelRepository) AccountHealth() db.AccountHealthRepository {
+	if r.accountHealthRepository != nil {
+		return r.accountHealthRepository
+	}
+
+	r.accountHealthRepository = &AccountHealthRepository{
+		health: make(map[int]*models.AccountHealth),
+	}
+
+	return r.accountHealthRepository
+}
+
+func (r *AdminPanelRepository) Currency() db.CurrencyRepository {
+	if r.currencyRepository != nil {
+		return r.currencyRepository
+	}
+
+	r.currencyRepository = &CurrencyRepository{
+		currencies: make(map[int]*models.Currency),
+	}
+	r.currencyRepository.seed()
+
+	return r.currencyRepository
+}
@@ -0,0 +1,182 @@
+package mocksqlstore
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type CurrencyRepository struct {
+	mu         sync.Mutex
+	currencies map[int]*models.Currency
+
+	nextID int
+}
+
+func (r *CurrencyRepository) Create(c *models.Currency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findByCode(c.Code) != nil {
+		return sql.ErrNoRows
+	}
+
+	r.nextID++
+	c.ID = r.nextID
+	if c.ProviderCodes == nil {
+		c.ProviderCodes = map[string]AppInterfaces.ProviderCurrency{}
+	}
+	c.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+	c.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	r.currencies[c.ID] = copyCurrency(c)
+	return nil
+}
+
+// Код валюты после создания не меняется
+func (r *CurrencyRepository) Update(c *models.Currency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := r.currencies[c.ID]; v != nil {
+		c.Code = v.Code
+		if c.ProviderCodes == nil {
+			c.ProviderCodes = map[string]AppInterfaces.ProviderCurrency{}
+		}
+		c.CreatedBy = v.CreatedBy
+		c.CreatedAt = v.CreatedAt
+		c.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+		r.currencies[c.ID] = copyCurrency(c)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *CurrencyRepository) Delete(c *models.Currency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := r.currencies[c.ID]; v != nil {
+		*c = *copyCurrency(v)
+		delete(r.currencies, c.ID)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *CurrencyRepository) Get(c *models.Currency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := r.currencies[c.ID]; v != nil {
+		*c = *copyCurrency(v)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *CurrencyRepository) GetByCode(c *models.Currency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := r.findByCode(c.Code); v != nil {
+		*c = *copyCurrency(v)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *CurrencyRepository) GetAll() ([]*models.Currency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	arr := []*models.Currency{}
+	for id := 1; id <= r.nextID; id++ {
+		if v := r.currencies[id]; v != nil {
+			arr = append(arr, copyCurrency(v))
+		}
+	}
+
+	return arr, nil
+}
+
+func (r *CurrencyRepository) Count(querys interface{}) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.currencies), nil
+}
+
+func (r *CurrencyRepository) Selection(querys interface{}) ([]*models.Currency, error) {
+	q := querys.(*models.CurrencySelection)
+
+	arr, err := r.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := AppMath.OffsetThreshold(q.Page, q.Limit)
+	if offset >= len(arr) {
+		return []*models.Currency{}, nil
+	}
+
+	arr = arr[offset:]
+	if len(arr) > q.Limit {
+		arr = arr[:q.Limit]
+	}
+
+	return arr, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Каталог заполняется теми же валютами,
+// что и миграция таблицы `currencies`
+func (r *CurrencyRepository) seed() {
+	for _, c := range []*models.Currency{
+		{Code: "BTC", Ticker: "BTC", Decimals: 8},
+		{Code: "ETH", Ticker: "ETH", Decimals: 8},
+		{Code: "TRX", Ticker: "TRX", Decimals: 6},
+		{Code: "USDT", Ticker: "USDT", Network: "TRC20", Decimals: 6},
+		{Code: "USDTTRC20", Ticker: "USDT", Network: "TRC20", Decimals: 6},
+		{Code: "USDTERC20", Ticker: "USDT", Network: "ERC20", Decimals: 6},
+		{Code: "USDTOMNI", Ticker: "USDT", Network: "OMNI", Decimals: 8},
+		{Code: "SBERRUB", Ticker: "RUB", Decimals: 2},
+	} {
+		r.Create(c)
+	}
+}
+
+func (r *CurrencyRepository) findByCode(code string) *models.Currency {
+	for _, v := range r.currencies {
+		if v.Code == code {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func copyCurrency(c *models.Currency) *models.Currency {
+	v := *c
+	v.ProviderCodes = make(map[string]AppInterfaces.ProviderCurrency, len(c.ProviderCodes))
+	for service, p := range c.ProviderCodes {
+		v.ProviderCodes[service] = p
+	}
+
+	return &v
+}
@@ -1,31 +1,137 @@
 package mocksqlstore
 
-import "github.com/gefion-tech/tg-exchanger-server/internal/models"
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
 
 type DirectionsMaRepository struct {
 	dma map[int]*models.DirectionMA
+	ma  *MerchantAutopayoutRepository
+
+	nextID int
 }
 
-func (r *DirectionsMaRepository) Create(er *models.DirectionMA) error {
+func (r *DirectionsMaRepository) Create(dma *models.DirectionMA) error {
+	r.nextID++
+	dma.ID = r.nextID
+	if dma.Weight == 0 {
+		dma.Weight = 1
+	}
+	dma.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+	dma.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	c := *dma
+	r.dma[dma.ID] = &c
 	return nil
 }
 
-func (r *DirectionsMaRepository) Update(er *models.DirectionMA) error {
-	return nil
+func (r *DirectionsMaRepository) Update(dma *models.DirectionMA) error {
+	if v := r.dma[dma.ID]; v != nil {
+		v.ServiceType = dma.ServiceType
+		v.Status = dma.Status
+		v.Priority = dma.Priority
+		v.Weight = dma.Weight
+		if v.Weight == 0 {
+			v.Weight = 1
+		}
+		v.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+		r.rewrite(dma.ID, dma)
+		return nil
+	}
+
+	return sql.ErrNoRows
 }
 
-func (r *DirectionsMaRepository) Delete(er *models.DirectionMA) error {
-	return nil
+func (r *DirectionsMaRepository) Delete(dma *models.DirectionMA) error {
+	if r.dma[dma.ID] != nil {
+		r.rewrite(dma.ID, dma)
+		delete(r.dma, dma.ID)
+		return nil
+	}
+
+	return sql.ErrNoRows
 }
 
-func (r *DirectionsMaRepository) Get(er *models.DirectionMA) error {
-	return nil
+func (r *DirectionsMaRepository) Get(dma *models.DirectionMA) error {
+	if r.dma[dma.ID] != nil {
+		r.rewrite(dma.ID, dma)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *DirectionsMaRepository) GetActiveAccounts(directionID, serviceType int) ([]*models.MerchantAutopayout, error) {
+	bindings, err := r.GetActiveBindings(directionID, serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := make([]*models.MerchantAutopayout, 0, len(bindings))
+	for _, da := range bindings {
+		arr = append(arr, da.Account)
+	}
+
+	return arr, nil
+}
+
+func (r *DirectionsMaRepository) GetActiveBindings(directionID, serviceType int) ([]*models.DirectionAccount, error) {
+	arr := []*models.DirectionAccount{}
+	for id := 1; id <= r.nextID; id++ {
+		dma := r.dma[id]
+		if dma == nil || dma.DirectionID != directionID || dma.ServiceType != serviceType || !dma.Status {
+			continue
+		}
+
+		m := &models.MerchantAutopayout{ID: dma.MaID}
+		if err := r.ma.Get(m); err != nil || !m.Status {
+			continue
+		}
+
+		b := *dma
+		arr = append(arr, &models.DirectionAccount{Binding: &b, Account: m})
+	}
+
+	sort.SliceStable(arr, func(i, j int) bool {
+		return arr[i].Binding.Priority < arr[j].Binding.Priority
+	})
+
+	return arr, nil
 }
 
 func (r *DirectionsMaRepository) Count(querys interface{}) (int, error) {
-	return len(r.dma), nil
+	arr, err := r.Selection(querys)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(arr), nil
 }
 
 func (r *DirectionsMaRepository) Selection(querys interface{}) ([]*models.DirectionMA, error) {
-	return nil, nil
+	q := querys.(*models.DirectionMASelection)
+	arr := []*models.DirectionMA{}
+	for id := r.nextID; id > 0; id-- {
+		if dma := r.dma[id]; dma != nil && dma.DirectionID == q.DirectionID {
+			arr = append(arr, dma)
+		}
+	}
+
+	return arr, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *DirectionsMaRepository) rewrite(id int, to *models.DirectionMA) {
+	*to = *r.dma[id]
 }
@@ -1,12 +1,19 @@
 package mocksqlstore
 
 import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 )
 
 type DirectionsRepository struct {
-	directions map[int]*models.BotMessage
+	directions map[int]*models.Direction
+	ma         *MerchantAutopayoutRepository
 
 	directionsMaRepository *DirectionsMaRepository
 }
@@ -18,31 +25,126 @@ func (r *DirectionsRepository) Ma() db.DirectionsMaRepository {
 
 	r.directionsMaRepository = &DirectionsMaRepository{
 		dma: make(map[int]*models.DirectionMA),
+		ma:  r.ma,
 	}
 
 	return r.directionsMaRepository
 }
 
-func (r *DirectionsRepository) Create(er *models.Direction) error {
+func (r *DirectionsRepository) Create(d *models.Direction) error {
+	d.ID = len(r.directions) + 1
+	d.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+	d.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	r.directions[d.ID] = &models.Direction{}
+	*r.directions[d.ID] = *d
 	return nil
 }
 
-func (r *DirectionsRepository) Update(er *models.Direction) error {
-	return nil
+func (r *DirectionsRepository) Update(d *models.Direction) error {
+	if r.directions[d.ID] != nil {
+		d.CreatedBy = r.directions[d.ID].CreatedBy
+		d.CreatedAt = r.directions[d.ID].CreatedAt
+		d.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+		*r.directions[d.ID] = *d
+		return nil
+	}
+
+	return sql.ErrNoRows
 }
 
-func (r *DirectionsRepository) Delete(er *models.Direction) error {
+func (r *DirectionsRepository) SetCourseCorrection(d *models.Direction, from int) error {
+	if r.directions[d.ID] == nil || r.directions[d.ID].CourseCorrection != from {
+		return fmt.Errorf("%w: direction %d", AppError.ErrCourseCorrectionChanged, d.ID)
+	}
+
+	d.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+	r.directions[d.ID].CourseCorrection = d.CourseCorrection
+	r.directions[d.ID].UpdatedAt = d.UpdatedAt
 	return nil
 }
 
-func (r *DirectionsRepository) Get(er *models.Direction) error {
-	return nil
+func (r *DirectionsRepository) Delete(d *models.Direction) error {
+	if r.directions[d.ID] != nil {
+		r.rewrite(d.ID, d)
+		delete(r.directions, d.ID)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *DirectionsRepository) Get(d *models.Direction) error {
+	if r.directions[d.ID] != nil {
+		r.rewrite(d.ID, d)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *DirectionsRepository) GetByPair(d *models.Direction) error {
+	if found := r.findByPair(d.ExchangeFrom, d.ExchangeTo); found != nil {
+		r.rewrite(found.ID, d)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *DirectionsRepository) GetAllActive() ([]*models.Direction, error) {
+	arr := []*models.Direction{}
+	for id := 1; id <= len(r.directions); id++ {
+		if d := r.directions[id]; d != nil && d.Status {
+			c := *d
+			arr = append(arr, &c)
+		}
+	}
+
+	return arr, nil
 }
 
 func (r *DirectionsRepository) Count(querys interface{}) (int, error) {
-	return len(r.directions), nil
+	arr, err := r.Selection(querys)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(arr), nil
 }
 
 func (r *DirectionsRepository) Selection(querys interface{}) ([]*models.Direction, error) {
-	return nil, nil
+	q := querys.(*models.DirectionSelection)
+	arr := []*models.Direction{}
+	for id := len(r.directions); id > 0; id-- {
+		d := r.directions[id]
+		if d == nil || (q.Status != nil && d.Status != *q.Status) {
+			continue
+		}
+
+		arr = append(arr, d)
+	}
+
+	return arr, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Поиск направления по паре валют
+func (r *DirectionsRepository) findByPair(from, to string) *models.Direction {
+	for _, d := range r.directions {
+		if d.ExchangeFrom == from && d.ExchangeTo == to {
+			return d
+		}
+	}
+
+	return nil
+}
+
+func (r *DirectionsRepository) rewrite(id int, to *models.Direction) {
+	*to = *r.directions[id]
 }
@@ -1,37 +1,106 @@
 package mocksqlstore
 
 import (
+	"database/sql"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 )
 
 type ExchangeRequestRepository struct {
-	er map[int]*models.ExchangeRequest
+	er         map[int]*models.ExchangeRequest
+	history    *RequestStatusHistoryRepository
+	directions *DirectionsRepository
 }
 
 func (r *ExchangeRequestRepository) Create(er *models.ExchangeRequest) error {
+	er.ID = len(r.er) + 1
+	if er.CreatedAt == "" {
+		er.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+	}
+	er.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	r.er[er.ID] = &models.ExchangeRequest{}
+	*r.er[er.ID] = *er
 	return nil
 }
 
 func (r *ExchangeRequestRepository) Update(er *models.ExchangeRequest) error {
-	return nil
+	if r.er[er.ID] != nil {
+		r.er[er.ID].TransferredAmount = er.TransferredAmount
+		r.er[er.ID].TransactionHash = er.TransactionHash
+		r.er[er.ID].UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+		r.rewrite(er.ID, er)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *ExchangeRequestRepository) Transition(er *models.ExchangeRequest, h *models.RequestStatusHistory) error {
+	if !h.StatusFrom.CanTransitionTo(h.StatusTo) {
+		return AppError.ErrInvalidStatusTransition
+	}
+
+	if r.er[er.ID] == nil || r.er[er.ID].Status != h.StatusFrom {
+		return AppError.ErrInvalidStatusTransition
+	}
+
+	r.er[er.ID].Status = h.StatusTo
+	r.er[er.ID].TransferredAmount = er.TransferredAmount
+	r.er[er.ID].TransactionHash = er.TransactionHash
+	r.er[er.ID].UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+	r.rewrite(er.ID, er)
+
+	h.RequestID = er.ID
+	return r.history.create(h)
 }
 
 func (r *ExchangeRequestRepository) Delete(er *models.ExchangeRequest) error {
-	return nil
+	if r.er[er.ID] != nil {
+		r.rewrite(er.ID, er)
+		delete(r.er, er.ID)
+		return nil
+	}
+
+	return sql.ErrNoRows
 }
 
 func (r *ExchangeRequestRepository) Get(er *models.ExchangeRequest) error {
-	return nil
+	if r.er[er.ID] != nil {
+		r.rewrite(er.ID, er)
+		return nil
+	}
+
+	return sql.ErrNoRows
 }
 
 func (r *ExchangeRequestRepository) Count(querys interface{}) (int, error) {
-	return len(r.er), nil
+	arr, err := r.Selection(querys)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(arr), nil
 }
 
 func (r *ExchangeRequestRepository) Selection(querys interface{}) ([]*models.ExchangeRequest, error) {
+	q := querys.(*models.ExchangeRequestSelection)
 	arr := []*models.ExchangeRequest{}
-	for _, er := range r.er {
+	for id := len(r.er); id > 0; id-- {
+		er := r.er[id]
+		if er == nil ||
+			(q.Status != 0 && er.Status != q.Status) ||
+			(q.ExchangeFrom != "" && er.ExchangeFrom != q.ExchangeFrom) ||
+			(q.ExchangeTo != "" && er.ExchangeTo != q.ExchangeTo) ||
+			(q.Username != "" && er.CreatedBy.Username != q.Username) ||
+			(q.ChatID != 0 && er.CreatedBy.ChatID != q.ChatID) {
+			continue
+		}
+
 		arr = append(arr, er)
 	}
 
@@ -39,5 +108,58 @@ func (r *ExchangeRequestRepository) Selection(querys interface{}) ([]*models.Exc
 }
 
 func (r *ExchangeRequestRepository) GetAllByStatus(s ...AppType.ExchangeRequestStatus) ([]*models.ExchangeRequest, error) {
-	return nil, nil
+	arr := []*models.ExchangeRequest{}
+	for id := 1; id <= len(r.er); id++ {
+		if r.er[id] == nil {
+			continue
+		}
+
+		for _, status := range s {
+			if r.er[id].Status == status {
+				er := &models.ExchangeRequest{}
+				r.rewrite(id, er)
+				arr = append(arr, er)
+				break
+			}
+		}
+	}
+
+	return arr, nil
+}
+
+func (r *ExchangeRequestRepository) GetAllExpired(defaultTTL int) ([]*models.ExchangeRequest, error) {
+	arr := []*models.ExchangeRequest{}
+	for id := 1; id <= len(r.er); id++ {
+		if r.er[id] == nil || r.er[id].Status != AppType.ExchangeRequestNew {
+			continue
+		}
+
+		ttl := defaultTTL
+		if d := r.directions.findByPair(r.er[id].ExchangeFrom, r.er[id].ExchangeTo); d != nil && d.RequestTTL > 0 {
+			ttl = d.RequestTTL
+		}
+
+		createdAt, err := time.Parse(core.DateStandart, r.er[id].CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if time.Since(createdAt) > time.Duration(ttl)*time.Minute {
+			er := &models.ExchangeRequest{}
+			r.rewrite(id, er)
+			arr = append(arr, er)
+		}
+	}
+
+	return arr, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *ExchangeRequestRepository) rewrite(id int, to *models.ExchangeRequest) {
+	*to = *r.er[id]
 }
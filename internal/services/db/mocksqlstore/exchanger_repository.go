@@ -26,6 +26,8 @@ func (r *ExchangerRepository) Update(e *models.Exchanger) error {
 	if r.exchangers[e.ID] != nil {
 		r.exchangers[e.ID].Name = e.Name
 		r.exchangers[e.ID].UrlToParse = e.UrlToParse
+		r.exchangers[e.ID].Format = e.Format
+		r.exchangers[e.ID].Mapping = e.Mapping
 		r.exchangers[e.ID].UpdatedAt = time.Now().UTC().Format(core.DateStandart)
 		return nil
 
@@ -35,6 +37,8 @@ func (r *ExchangerRepository) Update(e *models.Exchanger) error {
 		if ex.ID == e.ID {
 			r.exchangers[e.ID].Name = e.Name
 			r.exchangers[e.ID].UrlToParse = e.UrlToParse
+		r.exchangers[e.ID].Format = e.Format
+		r.exchangers[e.ID].Mapping = e.Mapping
 			r.exchangers[e.ID].UpdatedAt = time.Now().UTC().Format(core.DateStandart)
 
 			r.rewrite(ex.ID, e)
@@ -48,6 +52,7 @@ func (r *ExchangerRepository) Update(e *models.Exchanger) error {
 func (r *ExchangerRepository) GetByName(e *models.Exchanger) error {
 	for _, ex := range r.exchangers {
 		if ex.Name == e.Name {
+			*e = *ex
 			return nil
 		}
 	}
@@ -55,6 +60,17 @@ func (r *ExchangerRepository) GetByName(e *models.Exchanger) error {
 	return sql.ErrNoRows
 }
 
+func (r *ExchangerRepository) GetAll() ([]*models.Exchanger, error) {
+	arr := []*models.Exchanger{}
+	for id := 1; id <= len(r.exchangers); id++ {
+		if e := r.exchangers[id]; e != nil {
+			arr = append(arr, e)
+		}
+	}
+
+	return arr, nil
+}
+
 func (r *ExchangerRepository) Delete(e *models.Exchanger) error {
 	if r.exchangers[e.ID] != nil {
 		defer delete(r.exchangers, r.exchangers[e.ID].ID)
@@ -0,0 +1,50 @@
+package mocksqlstore
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+// Позиции хранятся копиями, чтобы изменения
+// у вызывающей стороны не попадали в хранилище
+type HistoryCursorRepository struct {
+	mu      sync.Mutex
+	cursors map[int]*models.HistoryCursor
+}
+
+func (r *HistoryCursorRepository) Get(c *models.HistoryCursor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := r.cursors[c.MaID]; v != nil {
+		*c = *copyCursor(v)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *HistoryCursorRepository) Save(c *models.HistoryCursor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+	r.cursors[c.MaID] = copyCursor(c)
+	*c = *copyCursor(c)
+	return nil
+}
+
+func copyCursor(c *models.HistoryCursor) *models.HistoryCursor {
+	v := *c
+	v.Seen = append([]string{}, c.Seen...)
+	v.Pending = make(map[string]int64, len(c.Pending))
+	for k, t := range c.Pending {
+		v.Pending[k] = t
+	}
+
+	return &v
+}
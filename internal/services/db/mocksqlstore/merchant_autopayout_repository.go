@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 )
 
 type MerchantAutopayoutRepository struct {
-	ma map[int]*models.MerchantAutopayout
+	ma      map[int]*models.MerchantAutopayout
+	payouts *PayoutRepository
 }
 
 func (r *MerchantAutopayoutRepository) Create(m *models.MerchantAutopayout) error {
@@ -39,6 +41,16 @@ func (r *MerchantAutopayoutRepository) Update(m *models.MerchantAutopayout) erro
 	return sql.ErrNoRows
 }
 
+func (r *MerchantAutopayoutRepository) SetStatus(id int, status bool) error {
+	if v, ok := r.ma[id]; ok {
+		v.Status = status
+		v.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (r *MerchantAutopayoutRepository) Get(m *models.MerchantAutopayout) error {
 	for _, v := range r.ma {
 		if v.ID == m.ID {
@@ -51,6 +63,12 @@ func (r *MerchantAutopayoutRepository) Get(m *models.MerchantAutopayout) error {
 }
 
 func (r *MerchantAutopayoutRepository) Delete(m *models.MerchantAutopayout) error {
+	for _, v := range r.payouts.payouts {
+		if v.MaID == m.ID {
+			return AppError.ErrRecordInUse
+		}
+	}
+
 	for _, v := range r.ma {
 		if v.ID == m.ID {
 			r.rewrite(m.ID, m)
@@ -81,7 +99,16 @@ func (r *MerchantAutopayoutRepository) GetFistIfActive(service string) (*models.
 }
 
 func (r *MerchantAutopayoutRepository) GetAllByServiceType(serviceType int, status bool) ([]*models.MerchantAutopayout, error) {
-	return nil, nil
+	arr := []*models.MerchantAutopayout{}
+	for id := 1; id <= len(r.ma); id++ {
+		if r.ma[id] != nil && r.ma[id].ServiceType == serviceType && r.ma[id].Status == status {
+			m := &models.MerchantAutopayout{}
+			r.rewrite(id, m)
+			arr = append(arr, m)
+		}
+	}
+
+	return arr, nil
 }
 
 /*
@@ -0,0 +1,94 @@
+package mocksqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type PayoutRepository struct {
+	payouts map[int]*models.Payout
+}
+
+func (r *PayoutRepository) Create(p *models.Payout) error {
+	p.ID = len(r.payouts) + 1
+	p.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+	p.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	r.payouts[p.ID] = p
+	return nil
+}
+
+func (r *PayoutRepository) Update(p *models.Payout) error {
+	if r.payouts[p.ID] != nil {
+		r.payouts[p.ID].Status = p.Status
+		r.payouts[p.ID].RawResponse = p.RawResponse
+		r.payouts[p.ID].Error = p.Error
+		r.payouts[p.ID].UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+		r.rewrite(p.ID, p)
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *PayoutRepository) GetLastByRequest(p *models.Payout) error {
+	last := 0
+	for id, v := range r.payouts {
+		if v.RequestID == p.RequestID && id > last {
+			last = id
+		}
+	}
+
+	if last == 0 {
+		return sql.ErrNoRows
+	}
+
+	r.rewrite(last, p)
+	return nil
+}
+
+func (r *PayoutRepository) Count(querys interface{}) (int, error) {
+	arr, err := r.Selection(querys)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(arr), nil
+}
+
+func (r *PayoutRepository) Selection(querys interface{}) ([]*models.Payout, error) {
+	q := querys.(*models.PayoutSelection)
+	arr := []*models.Payout{}
+	for _, p := range r.payouts {
+		if (q.RequestID != 0 && p.RequestID != q.RequestID) || (q.MaID != 0 && p.MaID != q.MaID) {
+			continue
+		}
+
+		arr = append(arr, p)
+	}
+
+	return arr, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *PayoutRepository) rewrite(id int, to *models.Payout) {
+	to.ID = r.payouts[id].ID
+	to.RequestID = r.payouts[id].RequestID
+	to.MaID = r.payouts[id].MaID
+	to.Ticker = r.payouts[id].Ticker
+	to.Network = r.payouts[id].Network
+	to.Amount = r.payouts[id].Amount
+	to.Status = r.payouts[id].Status
+	to.RawResponse = r.payouts[id].RawResponse
+	to.Error = r.payouts[id].Error
+	to.CreatedAt = r.payouts[id].CreatedAt
+	to.UpdatedAt = r.payouts[id].UpdatedAt
+}
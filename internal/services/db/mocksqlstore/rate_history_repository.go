@@ -0,0 +1,127 @@
+package mocksqlstore
+
+import (
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type RateHistoryRepository struct {
+	history map[int]*models.RateHistory
+
+	nextID int
+}
+
+func (r *RateHistoryRepository) Create(arr []*models.RateHistory) error {
+	for _, h := range arr {
+		r.nextID++
+		h.ID = r.nextID
+		if h.CreatedAt == "" {
+			h.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+		}
+
+		c := *h
+		r.history[h.ID] = &c
+	}
+
+	return nil
+}
+
+func (r *RateHistoryRepository) Series(q *models.RateHistorySelection) ([]*models.RateHistoryPoint, error) {
+	arr := []*models.RateHistoryPoint{}
+	buckets := map[string][]*models.RateHistory{}
+
+	for _, h := range r.selection(q) {
+		t, _ := time.Parse(core.DateStandart, h.CreatedAt)
+		switch q.Interval {
+		case AppType.RateHistoryMinute:
+			t = t.Truncate(time.Minute)
+		case AppType.RateHistoryHour:
+			t = t.Truncate(time.Hour)
+		case AppType.RateHistoryDay:
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		k := t.Format(core.DateStandart)
+		if len(buckets[k]) == 0 {
+			arr = append(arr, &models.RateHistoryPoint{Time: k})
+		}
+		buckets[k] = append(buckets[k], h)
+	}
+
+	for _, p := range arr {
+		p.RateHistoryStats = *stats(buckets[p.Time])
+	}
+
+	return arr, nil
+}
+
+func (r *RateHistoryRepository) Summary(q *models.RateHistorySelection) (*models.RateHistoryStats, error) {
+	return stats(r.selection(q)), nil
+}
+
+func (r *RateHistoryRepository) DeleteBefore(date string) (int, error) {
+	c := 0
+	for id, h := range r.history {
+		if h.CreatedAt < date {
+			delete(r.history, id)
+			c++
+		}
+	}
+
+	return c, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Записи в порядке добавления, подходящие под запрос q
+func (r *RateHistoryRepository) selection(q *models.RateHistorySelection) []*models.RateHistory {
+	arr := []*models.RateHistory{}
+	for id := 1; id <= r.nextID; id++ {
+		h := r.history[id]
+		if h == nil ||
+			h.DirectionID != q.DirectionID ||
+			h.CreatedAt < q.DateFrom || h.CreatedAt >= q.DateTo ||
+			(q.ExchangerID != 0 && h.ExchangerID != q.ExchangerID) ||
+			(q.ExchangerID == 0 && !h.Reference) {
+			continue
+		}
+
+		arr = append(arr, h)
+	}
+
+	return arr
+}
+
+func stats(arr []*models.RateHistory) *models.RateHistoryStats {
+	s := &models.RateHistoryStats{Count: len(arr)}
+
+	source, course := []decimal.Decimal{}, []decimal.Decimal{}
+	for _, h := range arr {
+		source = append(source, h.SourceOut.Div(h.SourceIn.Decimal))
+		course = append(course, h.Out.Div(h.In.Decimal))
+	}
+
+	s.Source, s.Course = rateStats(source), rateStats(course)
+	return s
+}
+
+func rateStats(arr []decimal.Decimal) models.RateStats {
+	if len(arr) == 0 {
+		return models.RateStats{Min: AppMoney.Zero, Max: AppMoney.Zero, Avg: AppMoney.Zero}
+	}
+
+	return models.RateStats{
+		Min: AppMoney.New(decimal.Min(arr[0], arr[1:]...).Round(8)),
+		Max: AppMoney.New(decimal.Max(arr[0], arr[1:]...).Round(8)),
+		Avg: AppMoney.New(decimal.Avg(arr[0], arr[1:]...).Round(8)),
+	}
+}
@@ -0,0 +1,97 @@
+package mocksqlstore
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+// Задачи обрабатываются в отдельных горутинах,
+// поэтому доступ к хранилищу синхронизирован
+type ReportJobRepository struct {
+	mu   sync.Mutex
+	jobs map[int]*models.ReportJob
+
+	nextID int
+}
+
+func (r *ReportJobRepository) Create(rj *models.ReportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	rj.ID = r.nextID
+	rj.Status = AppType.ReportStatusPending
+	if len(rj.Params) == 0 {
+		rj.Params = []byte("{}")
+	}
+	rj.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+	rj.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	r.jobs[rj.ID] = &models.ReportJob{}
+	*r.jobs[rj.ID] = *rj
+	return nil
+}
+
+func (r *ReportJobRepository) Update(rj *models.ReportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if j := r.jobs[rj.ID]; j != nil {
+		j.Status, j.File, j.Error = rj.Status, rj.File, rj.Error
+		j.UpdatedAt = time.Now().UTC().Format(core.DateStandart)
+		*rj = *j
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *ReportJobRepository) Get(rj *models.ReportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if j := r.jobs[rj.ID]; j != nil {
+		*rj = *j
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *ReportJobRepository) GetAllUnfinished() ([]*models.ReportJob, error) {
+	return r.selection(func(j *models.ReportJob) bool {
+		return j.Status == AppType.ReportStatusPending || j.Status == AppType.ReportStatusRunning
+	}), nil
+}
+
+func (r *ReportJobRepository) GetAllExpired(before string) ([]*models.ReportJob, error) {
+	return r.selection(func(j *models.ReportJob) bool {
+		return j.Status == AppType.ReportStatusDone && j.UpdatedAt < before
+	}), nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *ReportJobRepository) selection(match func(j *models.ReportJob) bool) []*models.ReportJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	arr := []*models.ReportJob{}
+	for id := 1; id <= r.nextID; id++ {
+		if j := r.jobs[id]; j != nil && match(j) {
+			c := *j
+			arr = append(arr, &c)
+		}
+	}
+
+	return arr
+}
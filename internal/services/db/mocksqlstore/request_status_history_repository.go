@@ -0,0 +1,39 @@
+package mocksqlstore
+
+import (
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type RequestStatusHistoryRepository struct {
+	history map[int]*models.RequestStatusHistory
+}
+
+func (r *RequestStatusHistoryRepository) GetAllByRequest(requestID int) ([]*models.RequestStatusHistory, error) {
+	arr := []*models.RequestStatusHistory{}
+	for id := 1; id <= len(r.history); id++ {
+		if r.history[id].RequestID == requestID {
+			h := *r.history[id]
+			arr = append(arr, &h)
+		}
+	}
+
+	return arr, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *RequestStatusHistoryRepository) create(h *models.RequestStatusHistory) error {
+	h.ID = len(r.history) + 1
+	h.CreatedAt = time.Now().UTC().Format(core.DateStandart)
+
+	r.history[h.ID] = &models.RequestStatusHistory{}
+	*r.history[h.ID] = *h
+	return nil
+}
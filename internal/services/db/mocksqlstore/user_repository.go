@@ -53,5 +53,12 @@ func (r *UserRepository) FindByUsername(username string) (*models.User, error) {
 }
 
 func (r *UserRepository) GetAllManagers() ([]*models.User, error) {
-	return nil, nil
+	uArr := []*models.User{}
+	for _, u := range r.users {
+		if u.Hash != nil {
+			uArr = append(uArr, u)
+		}
+	}
+
+	return uArr, nil
 }
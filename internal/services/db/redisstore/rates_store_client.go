@@ -0,0 +1,58 @@
+package redisstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+type RatesClient struct {
+	client *redis.Client
+}
+
+type RatesClientI interface {
+	/*
+		Сохранить текущий курс направления
+	*/
+	SaveRate(from, to string, body []byte, d time.Duration) error
+
+	/*
+		Получить текущий курс направления
+	*/
+	FetchRate(from, to string) (string, error)
+
+	/*
+		Закрыть установленное соединение с клиентом
+	*/
+	Close() error
+
+	/*
+	   Очистить все хранилище
+	*/
+	Clear()
+}
+
+func InitRatesClient(c *redis.Client) RatesClientI {
+	return &RatesClient{client: c}
+}
+
+func (c *RatesClient) SaveRate(from, to string, body []byte, d time.Duration) error {
+	return c.client.Set(rateKey(from, to), body, d).Err()
+}
+
+func (c *RatesClient) FetchRate(from, to string) (string, error) {
+	return c.client.Get(rateKey(from, to)).Result()
+}
+
+func (c *RatesClient) Close() error {
+	return c.client.Close()
+}
+
+func (c *RatesClient) Clear() {
+	c.client.FlushAllAsync()
+}
+
+func rateKey(from, to string) string {
+	return fmt.Sprintf("rate:%s:%s", from, to)
+}
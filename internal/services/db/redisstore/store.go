@@ -9,6 +9,7 @@ import (
 type AppRedisDictionaries struct {
 	Registration RegistrationClientI
 	Auth         AuthClientI
+	Rates        RatesClientI
 }
 
 // Redis хранилище
@@ -35,11 +36,19 @@ func InitAppRedisDictionaries(cfg *config.RedisConfig) (*AppRedisDictionaries, f
 		return nil, nil, err
 	}
 
+	// Инициализация хранилища текущих курсов направлений
+	rRates, err := db.InitRedis(cfg, 3)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return &AppRedisDictionaries{
 			Registration: InitRegistrationClient(rRegistration),
 			Auth:         InitAuthClient(rAuth),
+			Rates:        InitRatesClient(rRates),
 		}, func() {
 			rRegistration.Close()
 			rAuth.Close()
+			rRates.Close()
 		}, nil
 }
@@ -14,6 +14,14 @@ type AdminPanelRepository interface {
 	MerchantAutopayout() MerchantAutopayoutRepository
 	ExchangeRequest() ExchangeRequestRepository
 	Directions() DirectionsRepository
+	Payout() PayoutRepository
+	RequestStatusHistory() RequestStatusHistoryRepository
+	AddressPool() AddressPoolRepository
+	RateHistory() RateHistoryRepository
+	ReportJob() ReportJobRepository
+	HistoryCursor() HistoryCursorRepository
+	AccountHealth() AccountHealthRepository
+	Currency() CurrencyRepository
 }
 
 type UserRepository interface {
@@ -43,6 +51,7 @@ type ExchangerRepository interface {
 	Delete(e *models.Exchanger) error
 	Update(e *models.Exchanger) error
 	GetByName(e *models.Exchanger) error
+	GetAll() ([]*models.Exchanger, error)
 	Count(querys interface{}) (int, error)
 	Selection(querys interface{}) ([]*models.Exchanger, error)
 }
@@ -69,6 +78,7 @@ type BotMessagesRepository interface {
 type MerchantAutopayoutRepository interface {
 	Create(m *models.MerchantAutopayout) error
 	Update(m *models.MerchantAutopayout) error
+	SetStatus(id int, status bool) error
 	Delete(m *models.MerchantAutopayout) error
 	Get(m *models.MerchantAutopayout) error
 	Count(querys interface{}) (int, error)
@@ -85,13 +95,18 @@ type ExchangeRequestRepository interface {
 	Count(querys interface{}) (int, error)
 	Selection(querys interface{}) ([]*models.ExchangeRequest, error)
 	GetAllByStatus(s ...AppType.ExchangeRequestStatus) ([]*models.ExchangeRequest, error)
+	GetAllExpired(defaultTTL int) ([]*models.ExchangeRequest, error)
+	Transition(r *models.ExchangeRequest, h *models.RequestStatusHistory) error
 }
 
 type DirectionsRepository interface {
 	Create(m *models.Direction) error
 	Update(m *models.Direction) error
+	SetCourseCorrection(m *models.Direction, from int) error
 	Delete(m *models.Direction) error
 	Get(m *models.Direction) error
+	GetByPair(m *models.Direction) error
+	GetAllActive() ([]*models.Direction, error)
 	Count(querys interface{}) (int, error)
 	Selection(querys interface{}) ([]*models.Direction, error)
 
@@ -103,6 +118,62 @@ type DirectionsMaRepository interface {
 	Update(dma *models.DirectionMA) error
 	Delete(dma *models.DirectionMA) error
 	Get(dma *models.DirectionMA) error
+	GetActiveAccounts(directionID, serviceType int) ([]*models.MerchantAutopayout, error)
+	GetActiveBindings(directionID, serviceType int) ([]*models.DirectionAccount, error)
 	Count(querys interface{}) (int, error)
 	Selection(querys interface{}) ([]*models.DirectionMA, error)
 }
+
+type PayoutRepository interface {
+	Create(p *models.Payout) error
+	Update(p *models.Payout) error
+	GetLastByRequest(p *models.Payout) error
+	Count(querys interface{}) (int, error)
+	Selection(querys interface{}) ([]*models.Payout, error)
+}
+
+type RequestStatusHistoryRepository interface {
+	GetAllByRequest(requestID int) ([]*models.RequestStatusHistory, error)
+}
+
+type AddressPoolRepository interface {
+	Release(a *models.PoolAddress) error
+	Acquire(a *models.PoolAddress, cooldown int) error
+}
+
+type RateHistoryRepository interface {
+	Create(arr []*models.RateHistory) error
+	Series(q *models.RateHistorySelection) ([]*models.RateHistoryPoint, error)
+	Summary(q *models.RateHistorySelection) (*models.RateHistoryStats, error)
+	DeleteBefore(date string) (int, error)
+}
+
+type ReportJobRepository interface {
+	Create(rj *models.ReportJob) error
+	Update(rj *models.ReportJob) error
+	Get(rj *models.ReportJob) error
+	GetAllUnfinished() ([]*models.ReportJob, error)
+	GetAllExpired(before string) ([]*models.ReportJob, error)
+}
+
+type HistoryCursorRepository interface {
+	Get(c *models.HistoryCursor) error
+	Save(c *models.HistoryCursor) error
+}
+
+type AccountHealthRepository interface {
+	Get(h *models.AccountHealth) error
+	Save(h *models.AccountHealth) error
+	GetAll() ([]*models.AccountHealth, error)
+}
+
+type CurrencyRepository interface {
+	Create(c *models.Currency) error
+	Update(c *models.Currency) error
+	Delete(c *models.Currency) error
+	Get(c *models.Currency) error
+	GetByCode(c *models.Currency) error
+	GetAll() ([]*models.Currency, error)
+	Count(querys interface{}) (int, error)
+	Selection(querys interface{}) ([]*models.Currency, error)
+}
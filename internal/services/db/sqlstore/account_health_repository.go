@@ -0,0 +1,112 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type AccountHealthRepository struct {
+	store *sql.DB
+}
+
+/*
+	Получить состояние аккаунта из таблицы `account_health`
+*/
+func (r *AccountHealthRepository) Get(h *models.AccountHealth) error {
+	return r.scan(h, r.store.QueryRow(
+		`
+		SELECT ma_id, alive, latency, last_error, last_error_at, consecutive_failures, checks, failures, last_success_at, checked_at
+		FROM account_health
+		WHERE ma_id=$1
+		`,
+		h.MaID,
+	))
+}
+
+/*
+	Сохранить состояние аккаунта в таблице `account_health`
+*/
+func (r *AccountHealthRepository) Save(h *models.AccountHealth) error {
+	return r.scan(h, r.store.QueryRow(
+		`
+		INSERT INTO account_health(ma_id, alive, latency, last_error, last_error_at, consecutive_failures, checks, failures, last_success_at, checked_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		ON CONFLICT (ma_id) DO UPDATE
+		SET alive=EXCLUDED.alive, latency=EXCLUDED.latency, last_error=EXCLUDED.last_error, last_error_at=EXCLUDED.last_error_at,
+			consecutive_failures=EXCLUDED.consecutive_failures, checks=EXCLUDED.checks, failures=EXCLUDED.failures,
+			last_success_at=EXCLUDED.last_success_at, checked_at=EXCLUDED.checked_at
+		RETURNING ma_id, alive, latency, last_error, last_error_at, consecutive_failures, checks, failures, last_success_at, checked_at
+		`,
+		h.MaID,
+		h.Alive,
+		h.Latency,
+		h.LastError,
+		h.LastErrorAt,
+		h.ConsecutiveFailures,
+		h.Checks,
+		h.Failures,
+		h.LastSuccessAt,
+		h.CheckedAt,
+	))
+}
+
+// Получить состояния всех проверенных аккаунтов
+func (r *AccountHealthRepository) GetAll() ([]*models.AccountHealth, error) {
+	arr := []*models.AccountHealth{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT ma_id, alive, latency, last_error, last_error_at, consecutive_failures, checks, failures, last_success_at, checked_at
+		FROM account_health
+		ORDER BY ma_id
+		`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h := &models.AccountHealth{}
+		if err := rows.Scan(
+			&h.MaID,
+			&h.Alive,
+			&h.Latency,
+			&h.LastError,
+			&h.LastErrorAt,
+			&h.ConsecutiveFailures,
+			&h.Checks,
+			&h.Failures,
+			&h.LastSuccessAt,
+			&h.CheckedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, h)
+	}
+
+	return arr, rows.Err()
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *AccountHealthRepository) scan(h *models.AccountHealth, row *sql.Row) error {
+	return row.Scan(
+		&h.MaID,
+		&h.Alive,
+		&h.Latency,
+		&h.LastError,
+		&h.LastErrorAt,
+		&h.ConsecutiveFailures,
+		&h.Checks,
+		&h.Failures,
+		&h.LastSuccessAt,
+		&h.CheckedAt,
+	)
+}
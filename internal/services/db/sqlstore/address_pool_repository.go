@@ -0,0 +1,66 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type AddressPoolRepository struct {
+	store *sql.DB
+}
+
+// Метод возвращает адрес в пул для повторной выдачи и запоминает
+// время освобождения. Повторное освобождение того же адреса
+// игнорируется.
+func (r *AddressPoolRepository) Release(a *models.PoolAddress) error {
+	if _, err := r.store.Exec(
+		`
+		INSERT INTO address_pool(ma_id, currency, address)
+		SELECT $1, $2, $3
+		ON CONFLICT (ma_id, currency, address) DO NOTHING
+		`,
+		a.MaID,
+		a.Currency,
+		a.Address,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Метод забирает из пула самый старый свободный адрес аккаунта
+// a.MaID для валюты a.Currency, освобожденный не менее cooldown
+// минут назад. Запоздавший платеж по просроченной заявке не будет
+// засчитан новой заявке. Если свободных адресов нет,
+// возвращается sql.ErrNoRows.
+func (r *AddressPoolRepository) Acquire(a *models.PoolAddress, cooldown int) error {
+	if err := r.store.QueryRow(
+		`
+		DELETE FROM address_pool
+		WHERE id=(
+			SELECT id FROM address_pool
+			WHERE ma_id=$1 AND currency=$2 AND released_at <= now() - make_interval(mins => $3)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, ma_id, currency, address, released_at, created_at
+		`,
+		a.MaID,
+		a.Currency,
+		cooldown,
+	).Scan(
+		&a.ID,
+		&a.MaID,
+		&a.Currency,
+		&a.Address,
+		&a.ReleasedAt,
+		&a.CreatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
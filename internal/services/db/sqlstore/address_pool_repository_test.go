@@ -0,0 +1,63 @@
+package sqlstore_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/mocks"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL_AddressPoolRepository(t *testing.T) {
+	config := config.InitTestConfig(t)
+
+	database, teardown := db.TestDB(t, &config.Services.DB)
+	defer teardown("users", "bot_messages", "merchant_autopayout", "address_pool")
+
+	// Вызываю создание хранилища
+	s := sqlstore.Init(database)
+
+	u, err := db.CreateUser(t, s)
+	assert.NoError(t, err)
+	assert.NotNil(t, u)
+
+	var m *models.BotMessage
+	assert.NoError(t, mapstructure.Decode(mocks.BOT_MESSAGE_REQ, &m))
+	m.MessageText = "some text"
+	m.CreatedBy = mocks.MANAGER_IN_ADMIN_REQ["username"].(string)
+	assert.NoError(t, s.AdminPanel().BotMessages().Create(m))
+
+	var ma *models.MerchantAutopayout
+	assert.NoError(t, mapstructure.Decode(mocks.MerchantAutopayout, &ma))
+	ma.MessageID = m.ID
+	ma.CreatedBy = u.Username
+	assert.NoError(t, s.AdminPanel().MerchantAutopayout().Create(ma))
+
+	// Пул пуст
+	assert.Equal(t, sql.ErrNoRows, s.AdminPanel().AddressPool().Acquire(&models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20"}, 0))
+
+	assert.NoError(t, s.AdminPanel().AddressPool().Release(&models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20", Address: "address-1"}))
+	assert.NoError(t, s.AdminPanel().AddressPool().Release(&models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20", Address: "address-2"}))
+
+	// Повторное освобождение адреса не создает дубликат
+	assert.NoError(t, s.AdminPanel().AddressPool().Release(&models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20", Address: "address-1"}))
+
+	// Недавно освобожденный адрес не выдается
+	assert.Equal(t, sql.ErrNoRows, s.AdminPanel().AddressPool().Acquire(&models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20"}, 60))
+
+	// Адрес другой валюты не выдается
+	assert.Equal(t, sql.ErrNoRows, s.AdminPanel().AddressPool().Acquire(&models.PoolAddress{MaID: ma.ID, Currency: "BTC"}, 0))
+
+	for _, expected := range []string{"address-1", "address-2"} {
+		a := &models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20"}
+		assert.NoError(t, s.AdminPanel().AddressPool().Acquire(a, 0))
+		assert.Equal(t, expected, a.Address)
+	}
+
+	assert.Equal(t, sql.ErrNoRows, s.AdminPanel().AddressPool().Acquire(&models.PoolAddress{MaID: ma.ID, Currency: "USDTTRC20"}, 0))
+}
@@ -7,15 +7,23 @@ import (
 )
 
 type AdminPanelRepository struct {
-	store                        *sql.DB
-	botMessagesRepository        *BotMessagesRepository
-	notificationRepository       *NotificationRepository
-	exchangerRepository          *ExchangerRepository
-	userBillsRepository          *UserBillsRepository
-	logsRepository               *LoggerRepository
-	merchantAutopayoutRepository *MerchantAutopayoutRepository
-	exchangeRequestRepository    *ExchangeRequestRepository
-	directionsRepository         *DirectionsRepository
+	store                          *sql.DB
+	botMessagesRepository          *BotMessagesRepository
+	notificationRepository         *NotificationRepository
+	exchangerRepository            *ExchangerRepository
+	userBillsRepository            *UserBillsRepository
+	logsRepository                 *LoggerRepository
+	merchantAutopayoutRepository   *MerchantAutopayoutRepository
+	exchangeRequestRepository      *ExchangeRequestRepository
+	directionsRepository           *DirectionsRepository
+	payoutRepository               *PayoutRepository
+	requestStatusHistoryRepository *RequestStatusHistoryRepository
+	addressPoolRepository          *AddressPoolRepository
+	rateHistoryRepository          *RateHistoryRepository
+	reportJobRepository            *ReportJobRepository
+	historyCursorRepository        *HistoryCursorRepository
+	accountHealthRepository        *AccountHealthRepository
+	currencyRepository             *CurrencyRepository
 }
 
 /*
@@ -119,3 +127,99 @@ func (r *AdminPanelRepository) Directions() db.DirectionsRepository {
 
 	return r.directionsRepository
 }
+
+func (r *AdminPanelRepository) Payout() db.PayoutRepository {
+	if r.payoutRepository != nil {
+		return r.payoutRepository
+	}
+
+	r.payoutRepository = &PayoutRepository{
+		store: r.store,
+	}
+
+	return r.payoutRepository
+}
+
+func (r *AdminPanelRepository) RequestStatusHistory() db.RequestStatusHistoryRepository {
+	if r.requestStatusHistoryRepository != nil {
+		return r.requestStatusHistoryRepository
+	}
+
+	r.requestStatusHistoryRepository = &RequestStatusHistoryRepository{
+		store: r.store,
+	}
+
+	return r.requestStatusHistoryRepository
+}
+
+func (r *AdminPanelRepository) AddressPool() db.AddressPoolRepository {
+	if r.addressPoolRepository != nil {
+		return r.addressPoolRepository
+	}
+
+	r.addressPoolRepository = &AddressPoolRepository{
+		store: r.store,
+	}
+
+	return r.addressPoolRepository
+}
+
+func (r *AdminPanelRepository) RateHistory() db.RateHistoryRepository {
+	if r.rateHistoryRepository != nil {
+		return r.rateHistoryRepository
+	}
+
+	r.rateHistoryRepository = &RateHistoryRepository{
+		store: r.store,
+	}
+
+	return r.rateHistoryRepository
+}
+
+func (r *AdminPanelRepository) ReportJob() db.ReportJobRepository {
+	if r.reportJobRepository != nil {
+		return r.reportJobRepository
+	}
+
+	r.reportJobRepository = &ReportJobRepository{
+		store: r.store,
+	}
+
+	return r.reportJobRepository
+}
+
+func (r *AdminPanelRepository) HistoryCursor() db.HistoryCursorRepository {
+	if r.historyCursorRepository != nil {
+		return r.historyCursorRepository
+	}
+
+	r.historyCursorRepository = &HistoryCursorRepository{
+		store: r.store,
+	}
+
+	return r.historyCursorRepository
+}
+
+func (r *AdminPanelRepository) AccountHealth() db.AccountHealthRepository {
+	if r.accountHealthRepository != nil {
+		return r.accountHealthRepository
+	}
+
+	r.accountHealthRepository = &AccountHealthRepository{
+		store: r.store,
+	}
+
+	return r.accountHealthRepository
+}
+
+func (r *AdminPanelRepository) Currency() db.CurrencyRepository {
+	if r.currencyRepository != nil {
+		return r.currencyRepository
+	}
+
+	r.currencyRepository = &CurrencyRepository{
+		store: r.store,
+	}
+
+	return r.currencyRepository
+}
@@ -0,0 +1,229 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type CurrencyRepository struct {
+	store *sql.DB
+}
+
+/*
+	Создать запись в таблице `currencies`
+*/
+func (r *CurrencyRepository) Create(c *models.Currency) error {
+	providers, err := marshalProviders(c)
+	if err != nil {
+		return err
+	}
+
+	return r.scan(c, r.store.QueryRow(
+		`
+		INSERT INTO currencies(code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9
+		WHERE NOT EXISTS (SELECT code FROM currencies WHERE code=$1)
+		RETURNING id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		`,
+		c.Code,
+		c.Ticker,
+		c.Network,
+		c.Decimals,
+		c.MemoRequired,
+		c.MinDeposit,
+		c.MinWithdraw,
+		providers,
+		c.CreatedBy,
+	))
+}
+
+/*
+	Обновить запись в таблице `currencies`. Код валюты не меняется,
+	на него ссылаются направления обмена и заявки
+*/
+func (r *CurrencyRepository) Update(c *models.Currency) error {
+	providers, err := marshalProviders(c)
+	if err != nil {
+		return err
+	}
+
+	return r.scan(c, r.store.QueryRow(
+		`
+		UPDATE currencies
+		SET ticker=$1, network=$2, decimals=$3, memo_required=$4, min_deposit=$5, min_withdraw=$6, provider_codes=$7::jsonb, updated_at=$8
+		WHERE id=$9
+		RETURNING id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		`,
+		c.Ticker,
+		c.Network,
+		c.Decimals,
+		c.MemoRequired,
+		c.MinDeposit,
+		c.MinWithdraw,
+		providers,
+		time.Now().UTC().Format(core.DateStandart),
+		c.ID,
+	))
+}
+
+/*
+	Удалить запись из таблицы `currencies`
+*/
+func (r *CurrencyRepository) Delete(c *models.Currency) error {
+	return r.scan(c, r.store.QueryRow(
+		`
+		DELETE FROM currencies
+		WHERE id=$1
+		RETURNING id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		`,
+		c.ID,
+	))
+}
+
+/*
+	Получить запись из таблицы `currencies`
+*/
+func (r *CurrencyRepository) Get(c *models.Currency) error {
+	return r.scan(c, r.store.QueryRow(
+		`
+		SELECT id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		FROM currencies
+		WHERE id=$1
+		`,
+		c.ID,
+	))
+}
+
+/*
+	Получить запись из таблицы `currencies` по коду валюты
+*/
+func (r *CurrencyRepository) GetByCode(c *models.Currency) error {
+	return r.scan(c, r.store.QueryRow(
+		`
+		SELECT id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		FROM currencies
+		WHERE code=$1
+		`,
+		c.Code,
+	))
+}
+
+// Получить весь каталог валют
+func (r *CurrencyRepository) GetAll() ([]*models.Currency, error) {
+	rows, err := r.store.Query(
+		`
+		SELECT id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		FROM currencies
+		ORDER BY id
+		`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.scanRows(rows)
+}
+
+/*
+	Подсчет кол-ва записей в таблице `currencies`
+*/
+func (r *CurrencyRepository) Count(querys interface{}) (int, error) {
+	var c int
+	if err := r.store.QueryRow(
+		`
+		SELECT count(*)
+		FROM currencies
+		`,
+	).Scan(
+		&c,
+	); err != nil {
+		return 0, err
+	}
+
+	return c, nil
+}
+
+func (r *CurrencyRepository) Selection(querys interface{}) ([]*models.Currency, error) {
+	q := querys.(*models.CurrencySelection)
+
+	rows, err := r.store.Query(
+		`
+		SELECT id, code, ticker, network, decimals, memo_required, min_deposit, min_withdraw, provider_codes, created_by, created_at, updated_at
+		FROM currencies
+		ORDER BY id
+		OFFSET $1
+		LIMIT $2
+		`,
+		AppMath.OffsetThreshold(q.Page, q.Limit),
+		q.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.scanRows(rows)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *CurrencyRepository) scan(c *models.Currency, row scanner) error {
+	var providers []byte
+	if err := row.Scan(
+		&c.ID,
+		&c.Code,
+		&c.Ticker,
+		&c.Network,
+		&c.Decimals,
+		&c.MemoRequired,
+		&c.MinDeposit,
+		&c.MinWithdraw,
+		&providers,
+		&c.CreatedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	c.ProviderCodes = map[string]AppInterfaces.ProviderCurrency{}
+	return json.Unmarshal(providers, &c.ProviderCodes)
+}
+
+func (r *CurrencyRepository) scanRows(rows *sql.Rows) ([]*models.Currency, error) {
+	defer rows.Close()
+
+	arr := []*models.Currency{}
+	for rows.Next() {
+		c := &models.Currency{}
+		if err := r.scan(c, rows); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, c)
+	}
+
+	return arr, rows.Err()
+}
+
+func marshalProviders(c *models.Currency) (string, error) {
+	if c.ProviderCodes == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(c.ProviderCodes)
+	return string(b), err
+}
@@ -0,0 +1,78 @@
+package sqlstore_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppInterfaces "github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL_CurrencyRepository(t *testing.T) {
+	config := config.InitTestConfig(t)
+
+	// Каталог заполняется миграцией, поэтому таблица не очищается
+	database, teardown := db.TestDB(t, &config.Services.DB)
+	defer teardown()
+
+	// Вызываю создание хранилища
+	s := sqlstore.Init(database)
+
+	// Валюты из миграции
+	usdt := &models.Currency{Code: "USDTTRC20"}
+	assert.NoError(t, s.AdminPanel().Currency().GetByCode(usdt))
+	assert.Equal(t, "USDT", usdt.Ticker)
+	assert.Equal(t, AppType.CurrencyNetworkTRC20, usdt.Network)
+
+	c := &models.Currency{
+		Code:        "USDTBEP20",
+		Ticker:      "USDT",
+		Network:     "BEP20",
+		Decimals:    6,
+		MinDeposit:  AppMoney.NewFromInt(10),
+		MinWithdraw: AppMoney.NewFromInt(20),
+		ProviderCodes: map[string]AppInterfaces.ProviderCurrency{
+			AppType.MerchantAutoPayoutWhitebit: {Network: "BSC"},
+		},
+	}
+	assert.NoError(t, s.AdminPanel().Currency().Create(c))
+	assert.NotZero(t, c.ID)
+	defer s.AdminPanel().Currency().Delete(&models.Currency{ID: c.ID})
+
+	// Код валюты уникален
+	assert.ErrorIs(t, s.AdminPanel().Currency().Create(&models.Currency{Code: "USDTBEP20", Ticker: "USDT"}), sql.ErrNoRows)
+
+	got := &models.Currency{ID: c.ID}
+	assert.NoError(t, s.AdminPanel().Currency().Get(got))
+	assert.Equal(t, "BSC", got.ProviderCodes[AppType.MerchantAutoPayoutWhitebit].Network)
+	assert.True(t, AppMoney.NewFromInt(20).Equal(got.MinWithdraw))
+
+	// Код валюты не обновляется
+	got.Code = "USDTBSC"
+	got.MemoRequired = true
+	assert.NoError(t, s.AdminPanel().Currency().Update(got))
+	assert.Equal(t, "USDTBEP20", got.Code)
+	assert.True(t, got.MemoRequired)
+
+	t.Run("selection", func(t *testing.T) {
+		all, err := s.AdminPanel().Currency().GetAll()
+		assert.NoError(t, err)
+
+		count, err := s.AdminPanel().Currency().Count(&models.CurrencySelection{})
+		assert.NoError(t, err)
+		assert.Equal(t, len(all), count)
+
+		arr, err := s.AdminPanel().Currency().Selection(&models.CurrencySelection{Page: 1, Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, arr, 2)
+	})
+
+	assert.NoError(t, s.AdminPanel().Currency().Delete(&models.Currency{ID: c.ID}))
+	assert.ErrorIs(t, s.AdminPanel().Currency().GetByCode(&models.Currency{Code: "USDTBEP20"}), sql.ErrNoRows)
+}
@@ -17,20 +17,24 @@ type DirectionsMaRepository struct {
 func (r *DirectionsMaRepository) Create(dma *models.DirectionMA) error {
 	if err := r.store.QueryRow(
 		`
-		INSERT INTO directions_ma(direction_id, ma_id, service_type, status)
-		SELECT $1, $2, $3, $4
-		RETURNING id, direction_id, ma_id, service_type, status, created_at, updated_at
+		INSERT INTO directions_ma(direction_id, ma_id, service_type, status, priority, weight)
+		SELECT $1, $2, $3, $4, $5, COALESCE(NULLIF($6, 0), 1)
+		RETURNING id, direction_id, ma_id, service_type, status, priority, weight, created_at, updated_at
 		`,
 		dma.DirectionID,
 		dma.MaID,
 		dma.ServiceType,
 		dma.Status,
+		dma.Priority,
+		dma.Weight,
 	).Scan(
 		&dma.ID,
 		&dma.DirectionID,
 		&dma.MaID,
 		&dma.ServiceType,
 		&dma.Status,
+		&dma.Priority,
+		&dma.Weight,
 		&dma.CreatedAt,
 		&dma.UpdatedAt,
 	); err != nil {
@@ -44,12 +48,14 @@ func (r *DirectionsMaRepository) Update(dma *models.DirectionMA) error {
 	if err := r.store.QueryRow(
 		`
 		UPDATE directions_ma
-		SET service_type=$1, status=$2, updated_at=$3
-		WHERE id=$4
-		RETURNING id, direction_id, ma_id, service_type, status, created_at, updated_at
+		SET service_type=$1, status=$2, priority=$3, weight=COALESCE(NULLIF($4, 0), 1), updated_at=$5
+		WHERE id=$6
+		RETURNING id, direction_id, ma_id, service_type, status, priority, weight, created_at, updated_at
 		`,
 		dma.ServiceType,
 		dma.Status,
+		dma.Priority,
+		dma.Weight,
 		time.Now().UTC().Format(core.DateStandart),
 		dma.ID,
 	).Scan(
@@ -58,6 +64,8 @@ func (r *DirectionsMaRepository) Update(dma *models.DirectionMA) error {
 		&dma.MaID,
 		&dma.ServiceType,
 		&dma.Status,
+		&dma.Priority,
+		&dma.Weight,
 		&dma.CreatedAt,
 		&dma.UpdatedAt,
 	); err != nil {
@@ -70,7 +78,7 @@ func (r *DirectionsMaRepository) Update(dma *models.DirectionMA) error {
 func (r *DirectionsMaRepository) Get(dma *models.DirectionMA) error {
 	if err := r.store.QueryRow(
 		`
-		SELECT id, direction_id, ma_id, service_type, status, created_at, updated_at
+		SELECT id, direction_id, ma_id, service_type, status, priority, weight, created_at, updated_at
 		FROM directions_ma
 		WHERE id=$1
 		`,
@@ -81,6 +89,8 @@ func (r *DirectionsMaRepository) Get(dma *models.DirectionMA) error {
 		&dma.MaID,
 		&dma.ServiceType,
 		&dma.Status,
+		&dma.Priority,
+		&dma.Weight,
 		&dma.CreatedAt,
 		&dma.UpdatedAt,
 	); err != nil {
@@ -90,12 +100,113 @@ func (r *DirectionsMaRepository) Get(dma *models.DirectionMA) error {
 	return nil
 }
 
+// Получить активные аккаунты мерчантов/автовыплат типа serviceType,
+// привязанные к направлению directionID активной привязкой
+func (r *DirectionsMaRepository) GetActiveAccounts(directionID, serviceType int) ([]*models.MerchantAutopayout, error) {
+	arr := []*models.MerchantAutopayout{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT m.id, m.name, m.service, m.service_type, m.options, m.status, m.message_id, m.created_by, m.created_at, m.updated_at
+		FROM directions_ma AS dma
+		JOIN merchant_autopayout AS m ON m.id=dma.ma_id
+		WHERE dma.direction_id=$1 AND dma.service_type=$2 AND dma.status=TRUE AND m.status=TRUE
+		ORDER BY dma.priority, dma.id
+		`,
+		directionID,
+		serviceType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := &models.MerchantAutopayout{}
+		if err := rows.Scan(
+			&m.ID,
+			&m.Name,
+			&m.Service,
+			&m.ServiceType,
+			&m.Options,
+			&m.Status,
+			&m.MessageID,
+			&m.CreatedBy,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, m)
+	}
+
+	return arr, rows.Err()
+}
+
+// Получить активные привязки типа serviceType к направлению
+// directionID вместе с активными аккаунтами мерчантов/автовыплат
+func (r *DirectionsMaRepository) GetActiveBindings(directionID, serviceType int) ([]*models.DirectionAccount, error) {
+	arr := []*models.DirectionAccount{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT dma.id, dma.direction_id, dma.ma_id, dma.service_type, dma.status, dma.priority, dma.weight, dma.created_at, dma.updated_at,
+		m.id, m.name, m.service, m.service_type, m.options, m.status, m.message_id, m.created_by, m.created_at, m.updated_at
+		FROM directions_ma AS dma
+		JOIN merchant_autopayout AS m ON m.id=dma.ma_id
+		WHERE dma.direction_id=$1 AND dma.service_type=$2 AND dma.status=TRUE AND m.status=TRUE
+		ORDER BY dma.priority, dma.id
+		`,
+		directionID,
+		serviceType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		da := &models.DirectionAccount{
+			Binding: &models.DirectionMA{},
+			Account: &models.MerchantAutopayout{},
+		}
+		if err := rows.Scan(
+			&da.Binding.ID,
+			&da.Binding.DirectionID,
+			&da.Binding.MaID,
+			&da.Binding.ServiceType,
+			&da.Binding.Status,
+			&da.Binding.Priority,
+			&da.Binding.Weight,
+			&da.Binding.CreatedAt,
+			&da.Binding.UpdatedAt,
+			&da.Account.ID,
+			&da.Account.Name,
+			&da.Account.Service,
+			&da.Account.ServiceType,
+			&da.Account.Options,
+			&da.Account.Status,
+			&da.Account.MessageID,
+			&da.Account.CreatedBy,
+			&da.Account.CreatedAt,
+			&da.Account.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, da)
+	}
+
+	return arr, rows.Err()
+}
+
 func (r *DirectionsMaRepository) Delete(dma *models.DirectionMA) error {
 	if err := r.store.QueryRow(
 		`
 		DELETE FROM directions_ma
 		WHERE id=$1
-		RETURNING id, direction_id, ma_id, service_type, status, created_at, updated_at
+		RETURNING id, direction_id, ma_id, service_type, status, priority, weight, created_at, updated_at
 		`,
 		dma.ID,
 	).Scan(
@@ -104,6 +215,8 @@ func (r *DirectionsMaRepository) Delete(dma *models.DirectionMA) error {
 		&dma.MaID,
 		&dma.ServiceType,
 		&dma.Status,
+		&dma.Priority,
+		&dma.Weight,
 		&dma.CreatedAt,
 		&dma.UpdatedAt,
 	); err != nil {
@@ -137,7 +250,7 @@ func (r *DirectionsMaRepository) Selection(querys interface{}) ([]*models.Direct
 
 	rows, err := r.store.Query(
 		`
-		SELECT id, direction_id, ma_id, service_type, status, created_at, updated_at
+		SELECT id, direction_id, ma_id, service_type, status, priority, weight, created_at, updated_at
 		FROM directions_ma
 		WHERE direction_id=$1
 		ORDER BY id DESC
@@ -162,6 +275,8 @@ func (r *DirectionsMaRepository) Selection(querys interface{}) ([]*models.Direct
 				&dma.MaID,
 				&dma.ServiceType,
 				&dma.Status,
+				&dma.Priority,
+				&dma.Weight,
 				&dma.CreatedAt,
 				&dma.UpdatedAt,
 			); err != nil {
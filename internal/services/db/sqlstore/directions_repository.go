@@ -2,6 +2,7 @@ package sqlstore
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -45,15 +46,28 @@ func (r *DirectionsRepository) Ma() db.DirectionsMaRepository {
 func (r *DirectionsRepository) Create(d *models.Direction) error {
 	if err := r.store.QueryRow(
 		`
-		INSERT INTO exchange_directions(exchange_from, exchange_to, course_correction, address_verification, status, created_by)
-		SELECT $1, $2, $3, $4, $5, $6
-		RETURNING id, exchange_from, exchange_to, course_correction, address_verification, status, created_by, created_at, updated_at
+		INSERT INTO exchange_directions(exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by)
+		SELECT $1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'absolute'), $8, $9, $10, $11, $12, $13, $14, $15, $16, COALESCE(NULLIF($17, ''), 'priority'), COALESCE(NULLIF($18, ''), 'priority'), $19
+		RETURNING id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
 		`,
 		d.ExchangeFrom,
 		d.ExchangeTo,
 		d.CourseCorrection,
 		d.AddressVerification,
 		d.Status,
+		d.RequestTTL,
+		d.ToleranceType,
+		d.Tolerance,
+		d.AutoPricing,
+		d.TargetPosition,
+		d.CorrectionMin,
+		d.CorrectionMax,
+		d.MinAmount,
+		d.MaxAmount,
+		d.ReserveType,
+		d.Reserve,
+		d.MerchantPolicy,
+		d.AutopayoutPolicy,
 		d.CreatedBy,
 	).Scan(
 		&d.ID,
@@ -62,6 +76,19 @@ func (r *DirectionsRepository) Create(d *models.Direction) error {
 		&d.CourseCorrection,
 		&d.AddressVerification,
 		&d.Status,
+		&d.RequestTTL,
+		&d.ToleranceType,
+		&d.Tolerance,
+		&d.AutoPricing,
+		&d.TargetPosition,
+		&d.CorrectionMin,
+		&d.CorrectionMax,
+		&d.MinAmount,
+		&d.MaxAmount,
+		&d.ReserveType,
+		&d.Reserve,
+		&d.MerchantPolicy,
+		&d.AutopayoutPolicy,
 		&d.CreatedBy,
 		&d.CreatedAt,
 		&d.UpdatedAt,
@@ -76,15 +103,28 @@ func (r *DirectionsRepository) Update(d *models.Direction) error {
 	if err := r.store.QueryRow(
 		`
 		UPDATE exchange_directions
-		SET exchange_from=$1, exchange_to=$2, course_correction=$3, address_verification=$4, status=$5, updated_at=$6
-		WHERE id=$7
-		RETURNING id, exchange_from, exchange_to, course_correction, address_verification, status, created_by, created_at, updated_at
+		SET exchange_from=$1, exchange_to=$2, course_correction=$3, address_verification=$4, status=$5, request_ttl=$6, tolerance_type=COALESCE(NULLIF($7, ''), 'absolute'), tolerance=$8, auto_pricing=$9, target_position=$10, correction_min=$11, correction_max=$12, min_amount=$13, max_amount=$14, reserve_type=$15, reserve=$16, merchant_policy=COALESCE(NULLIF($17, ''), 'priority'), autopayout_policy=COALESCE(NULLIF($18, ''), 'priority'), updated_at=$19
+		WHERE id=$20
+		RETURNING id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
 		`,
 		d.ExchangeFrom,
 		d.ExchangeTo,
 		d.CourseCorrection,
 		d.AddressVerification,
 		d.Status,
+		d.RequestTTL,
+		d.ToleranceType,
+		d.Tolerance,
+		d.AutoPricing,
+		d.TargetPosition,
+		d.CorrectionMin,
+		d.CorrectionMax,
+		d.MinAmount,
+		d.MaxAmount,
+		d.ReserveType,
+		d.Reserve,
+		d.MerchantPolicy,
+		d.AutopayoutPolicy,
 		time.Now().UTC().Format(core.DateStandart),
 		d.ID,
 	).Scan(
@@ -94,6 +134,19 @@ func (r *DirectionsRepository) Update(d *models.Direction) error {
 		&d.CourseCorrection,
 		&d.AddressVerification,
 		&d.Status,
+		&d.RequestTTL,
+		&d.ToleranceType,
+		&d.Tolerance,
+		&d.AutoPricing,
+		&d.TargetPosition,
+		&d.CorrectionMin,
+		&d.CorrectionMax,
+		&d.MinAmount,
+		&d.MaxAmount,
+		&d.ReserveType,
+		&d.Reserve,
+		&d.MerchantPolicy,
+		&d.AutopayoutPolicy,
 		&d.CreatedBy,
 		&d.CreatedAt,
 		&d.UpdatedAt,
@@ -104,12 +157,41 @@ func (r *DirectionsRepository) Update(d *models.Direction) error {
 	return nil
 }
 
+// Метод сохраняет только корректировку курса направления d.
+// Запись обновляется, если корректировка в БД все еще равна from,
+// иначе изменение другого пользователя не перезаписывается.
+func (r *DirectionsRepository) SetCourseCorrection(d *models.Direction, from int) error {
+	if err := r.store.QueryRow(
+		`
+		UPDATE exchange_directions
+		SET course_correction=$1, updated_at=$2
+		WHERE id=$3 AND course_correction=$4
+		RETURNING course_correction, updated_at
+		`,
+		d.CourseCorrection,
+		time.Now().UTC().Format(core.DateStandart),
+		d.ID,
+		from,
+	).Scan(
+		&d.CourseCorrection,
+		&d.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: direction %d", AppError.ErrCourseCorrectionChanged, d.ID)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func (r *DirectionsRepository) Delete(d *models.Direction) error {
 	if err := r.store.QueryRow(
 		`
 		DELETE FROM exchange_directions
 		WHERE id=$1
-		RETURNING id, exchange_from, exchange_to, course_correction, address_verification, status, created_by, created_at, updated_at
+		RETURNING id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
 		`,
 		d.ID,
 	).Scan(
@@ -119,6 +201,19 @@ func (r *DirectionsRepository) Delete(d *models.Direction) error {
 		&d.CourseCorrection,
 		&d.AddressVerification,
 		&d.Status,
+		&d.RequestTTL,
+		&d.ToleranceType,
+		&d.Tolerance,
+		&d.AutoPricing,
+		&d.TargetPosition,
+		&d.CorrectionMin,
+		&d.CorrectionMax,
+		&d.MinAmount,
+		&d.MaxAmount,
+		&d.ReserveType,
+		&d.Reserve,
+		&d.MerchantPolicy,
+		&d.AutopayoutPolicy,
 		&d.CreatedBy,
 		&d.CreatedAt,
 		&d.UpdatedAt,
@@ -132,7 +227,7 @@ func (r *DirectionsRepository) Delete(d *models.Direction) error {
 func (r *DirectionsRepository) Get(d *models.Direction) error {
 	if err := r.store.QueryRow(
 		`
-		SELECT id, exchange_from, exchange_to, course_correction, address_verification, status, created_by, created_at, updated_at
+		SELECT id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
 		FROM exchange_directions
 		WHERE id=$1
 		`,
@@ -144,6 +239,19 @@ func (r *DirectionsRepository) Get(d *models.Direction) error {
 		&d.CourseCorrection,
 		&d.AddressVerification,
 		&d.Status,
+		&d.RequestTTL,
+		&d.ToleranceType,
+		&d.Tolerance,
+		&d.AutoPricing,
+		&d.TargetPosition,
+		&d.CorrectionMin,
+		&d.CorrectionMax,
+		&d.MinAmount,
+		&d.MaxAmount,
+		&d.ReserveType,
+		&d.Reserve,
+		&d.MerchantPolicy,
+		&d.AutopayoutPolicy,
 		&d.CreatedBy,
 		&d.CreatedAt,
 		&d.UpdatedAt,
@@ -154,6 +262,98 @@ func (r *DirectionsRepository) Get(d *models.Direction) error {
 	return nil
 }
 
+// Поиск направления по паре валют d.ExchangeFrom -> d.ExchangeTo
+func (r *DirectionsRepository) GetByPair(d *models.Direction) error {
+	if err := r.store.QueryRow(
+		`
+		SELECT id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
+		FROM exchange_directions
+		WHERE exchange_from=$1 AND exchange_to=$2
+		`,
+		d.ExchangeFrom,
+		d.ExchangeTo,
+	).Scan(
+		&d.ID,
+		&d.ExchangeFrom,
+		&d.ExchangeTo,
+		&d.CourseCorrection,
+		&d.AddressVerification,
+		&d.Status,
+		&d.RequestTTL,
+		&d.ToleranceType,
+		&d.Tolerance,
+		&d.AutoPricing,
+		&d.TargetPosition,
+		&d.CorrectionMin,
+		&d.CorrectionMax,
+		&d.MinAmount,
+		&d.MaxAmount,
+		&d.ReserveType,
+		&d.Reserve,
+		&d.MerchantPolicy,
+		&d.AutopayoutPolicy,
+		&d.CreatedBy,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Получить все активные направления
+func (r *DirectionsRepository) GetAllActive() ([]*models.Direction, error) {
+	arr := []*models.Direction{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
+		FROM exchange_directions
+		WHERE status=TRUE
+		ORDER BY id
+		`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := &models.Direction{}
+		if err := rows.Scan(
+			&d.ID,
+			&d.ExchangeFrom,
+			&d.ExchangeTo,
+			&d.CourseCorrection,
+			&d.AddressVerification,
+			&d.Status,
+			&d.RequestTTL,
+			&d.ToleranceType,
+			&d.Tolerance,
+			&d.AutoPricing,
+			&d.TargetPosition,
+			&d.CorrectionMin,
+			&d.CorrectionMax,
+			&d.MinAmount,
+			&d.MaxAmount,
+			&d.ReserveType,
+			&d.Reserve,
+			&d.MerchantPolicy,
+			&d.AutopayoutPolicy,
+			&d.CreatedBy,
+			&d.CreatedAt,
+			&d.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, d)
+	}
+
+	return arr, rows.Err()
+}
+
 func (r *DirectionsRepository) Count(querys interface{}) (int, error) {
 	q := querys.(*models.DirectionSelection)
 	var c int
@@ -178,7 +378,7 @@ func (r *DirectionsRepository) Selection(querys interface{}) ([]*models.Directio
 	arr := []*models.Direction{}
 
 	sb := fmt.Sprintf(`
-		SELECT id, exchange_from, exchange_to, course_correction, address_verification, status, created_by, created_at, updated_at
+		SELECT id, exchange_from, exchange_to, course_correction, address_verification, status, request_ttl, tolerance_type, tolerance, auto_pricing, target_position, correction_min, correction_max, min_amount, max_amount, reserve_type, reserve, merchant_policy, autopayout_policy, created_by, created_at, updated_at
 		FROM exchange_directions
 		WHERE %s
 		ORDER BY id DESC
@@ -206,6 +406,19 @@ func (r *DirectionsRepository) Selection(querys interface{}) ([]*models.Directio
 				&d.CourseCorrection,
 				&d.AddressVerification,
 				&d.Status,
+				&d.RequestTTL,
+				&d.ToleranceType,
+				&d.Tolerance,
+				&d.AutoPricing,
+				&d.TargetPosition,
+				&d.CorrectionMin,
+				&d.CorrectionMax,
+				&d.MinAmount,
+				&d.MaxAmount,
+				&d.ReserveType,
+				&d.Reserve,
+				&d.MerchantPolicy,
+				&d.AutopayoutPolicy,
 				&d.CreatedBy,
 				&d.CreatedAt,
 				&d.UpdatedAt,
@@ -2,8 +2,8 @@ package sqlstore
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -21,19 +21,22 @@ type ExchangeRequestRepository struct {
 func (r *ExchangeRequestRepository) Create(er *models.ExchangeRequest) error {
 	if err := r.store.QueryRow(
 		`
-		INSERT INTO request(request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, created_by_username, created_by_chat_id)
-		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
-		RETURNING id, request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, created_at, updated_at
+		INSERT INTO request(request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, created_by_username, created_by_chat_id, ma_id)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		RETURNING id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
 		`,
 		er.Status,
 		er.ExchangeFrom,
 		er.ExchangeTo,
 		er.Course,
 		er.Address,
+		er.Memo,
 		er.ClientAddress,
+		er.ClientMemo,
 		er.ExpectedAmount,
 		er.CreatedBy.Username,
 		er.CreatedBy.ChatID,
+		er.MaID,
 	).Scan(
 		&er.ID,
 		&er.Status,
@@ -41,12 +44,15 @@ func (r *ExchangeRequestRepository) Create(er *models.ExchangeRequest) error {
 		&er.ExchangeTo,
 		&er.Course,
 		&er.Address,
+		&er.Memo,
 		&er.ClientAddress,
+		&er.ClientMemo,
 		&er.ExpectedAmount,
 		&er.TransferredAmount,
 		&er.TransactionHash,
 		&er.CreatedBy.Username,
 		&er.CreatedBy.ChatID,
+		&er.MaID,
 		&er.CreatedAt,
 		&er.UpdatedAt,
 	); err != nil {
@@ -59,7 +65,7 @@ func (r *ExchangeRequestRepository) Create(er *models.ExchangeRequest) error {
 func (r *ExchangeRequestRepository) Get(er *models.ExchangeRequest) error {
 	if err := r.store.QueryRow(
 		`
-		SELECT id, request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, created_at, updated_at
+		SELECT id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
 		FROM request
 		WHERE id=$1
 		`,
@@ -71,12 +77,15 @@ func (r *ExchangeRequestRepository) Get(er *models.ExchangeRequest) error {
 		&er.ExchangeTo,
 		&er.Course,
 		&er.Address,
+		&er.Memo,
 		&er.ClientAddress,
+		&er.ClientMemo,
 		&er.ExpectedAmount,
 		&er.TransferredAmount,
 		&er.TransactionHash,
 		&er.CreatedBy.Username,
 		&er.CreatedBy.ChatID,
+		&er.MaID,
 		&er.CreatedAt,
 		&er.UpdatedAt,
 	); err != nil {
@@ -86,17 +95,18 @@ func (r *ExchangeRequestRepository) Get(er *models.ExchangeRequest) error {
 	return nil
 }
 
+// Метод обновляет данные о поступивших средствах. Статус
+// заявки меняется только через метод Transition.
 func (r *ExchangeRequestRepository) Update(er *models.ExchangeRequest) error {
 	if err := r.store.QueryRow(
 		`
 		UPDATE request
-		SET request_status=$1, transferred_amount=$2, transaction_hash=$3, updated_at=$4
-		WHERE id=$5
-		RETURNING id, request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, created_at, updated_at
+		SET transferred_amount=$1, transaction_hash=$2, updated_at=$3
+		WHERE id=$4
+		RETURNING id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
 		`,
-		er.Status,
 		er.TransferredAmount,
-		*er.TransactionHash,
+		er.TransactionHash,
 		time.Now().UTC().Format(core.DateStandart),
 		er.ID,
 	).Scan(
@@ -106,12 +116,15 @@ func (r *ExchangeRequestRepository) Update(er *models.ExchangeRequest) error {
 		&er.ExchangeTo,
 		&er.Course,
 		&er.Address,
+		&er.Memo,
 		&er.ClientAddress,
+		&er.ClientMemo,
 		&er.ExpectedAmount,
 		&er.TransferredAmount,
 		&er.TransactionHash,
 		&er.CreatedBy.Username,
 		&er.CreatedBy.ChatID,
+		&er.MaID,
 		&er.CreatedAt,
 		&er.UpdatedAt,
 	); err != nil {
@@ -122,12 +135,87 @@ func (r *ExchangeRequestRepository) Update(er *models.ExchangeRequest) error {
 	return nil
 }
 
+// Метод переводит заявку в статус h.StatusTo и в той же транзакции
+// записывает переход в историю статусов. Если статус заявки в БД
+// уже отличается от h.StatusFrom, переход отклоняется.
+func (r *ExchangeRequestRepository) Transition(er *models.ExchangeRequest, h *models.RequestStatusHistory) error {
+	if !h.StatusFrom.CanTransitionTo(h.StatusTo) {
+		return fmt.Errorf("%w: %d -> %d", AppError.ErrInvalidStatusTransition, h.StatusFrom, h.StatusTo)
+	}
+
+	tx, err := r.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(
+		`
+		UPDATE request
+		SET request_status=$1, transferred_amount=$2, transaction_hash=$3, updated_at=$4
+		WHERE id=$5 AND request_status=$6
+		RETURNING id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
+		`,
+		h.StatusTo,
+		er.TransferredAmount,
+		er.TransactionHash,
+		time.Now().UTC().Format(core.DateStandart),
+		er.ID,
+		h.StatusFrom,
+	).Scan(
+		&er.ID,
+		&er.Status,
+		&er.ExchangeFrom,
+		&er.ExchangeTo,
+		&er.Course,
+		&er.Address,
+		&er.Memo,
+		&er.ClientAddress,
+		&er.ClientMemo,
+		&er.ExpectedAmount,
+		&er.TransferredAmount,
+		&er.TransactionHash,
+		&er.CreatedBy.Username,
+		&er.CreatedBy.ChatID,
+		&er.MaID,
+		&er.CreatedAt,
+		&er.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: request %d is not in status %d", AppError.ErrInvalidStatusTransition, er.ID, h.StatusFrom)
+		}
+
+		return err
+	}
+
+	h.RequestID = er.ID
+	if err := tx.QueryRow(
+		`
+		INSERT INTO request_status_history(request_id, status_from, status_to, actor, reason)
+		SELECT $1, $2, $3, $4, $5
+		RETURNING id, created_at
+		`,
+		er.ID,
+		h.StatusFrom,
+		h.StatusTo,
+		h.Actor,
+		h.Reason,
+	).Scan(
+		&h.ID,
+		&h.CreatedAt,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *ExchangeRequestRepository) Delete(er *models.ExchangeRequest) error {
 	if err := r.store.QueryRow(
 		`
 		DELETE FROM request
 		WHERE id=$1
-		RETURNING id, request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, created_at, updated_at
+		RETURNING id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
 		`,
 		er.ID,
 	).Scan(
@@ -137,12 +225,15 @@ func (r *ExchangeRequestRepository) Delete(er *models.ExchangeRequest) error {
 		&er.ExchangeTo,
 		&er.Course,
 		&er.Address,
+		&er.Memo,
 		&er.ClientAddress,
+		&er.ClientMemo,
 		&er.ExpectedAmount,
 		&er.TransferredAmount,
 		&er.TransactionHash,
 		&er.CreatedBy.Username,
 		&er.CreatedBy.ChatID,
+		&er.MaID,
 		&er.CreatedAt,
 		&er.UpdatedAt,
 	); err != nil {
@@ -156,15 +247,16 @@ func (r *ExchangeRequestRepository) Count(querys interface{}) (int, error) {
 	q := querys.(*models.ExchangeRequestSelection)
 	var c int
 
+	where, args := r.queryGeneration(q)
 	sb := fmt.Sprintf(`
 		SELECT count(*)
 		FROM request
 		%s
 	`,
-		r.queryGeneration(q),
+		where,
 	)
 
-	if err := r.store.QueryRow(sb).Scan(&c); err != nil {
+	if err := r.store.QueryRow(sb, args...).Scan(&c); err != nil {
 		return 0, err
 	}
 
@@ -175,20 +267,21 @@ func (r *ExchangeRequestRepository) Selection(querys interface{}) ([]*models.Exc
 	q := querys.(*models.ExchangeRequestSelection)
 	arr := []*models.ExchangeRequest{}
 
+	where, args := r.queryGeneration(q)
 	sb := fmt.Sprintf(`
-		SELECT id, request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, created_at, updated_at
+		SELECT id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
 		FROM request
 		%s
 		ORDER BY id DESC
 		OFFSET %d
 		LIMIT %d
 	`,
-		r.queryGeneration(q),
+		where,
 		AppMath.OffsetThreshold(*q.Page, *q.Limit),
 		*q.Limit,
 	)
 
-	rows, err := r.store.Query(sb)
+	rows, err := r.store.Query(sb, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -204,12 +297,15 @@ func (r *ExchangeRequestRepository) Selection(querys interface{}) ([]*models.Exc
 				&er.ExchangeTo,
 				&er.Course,
 				&er.Address,
+				&er.Memo,
 				&er.ClientAddress,
+				&er.ClientMemo,
 				&er.ExpectedAmount,
 				&er.TransferredAmount,
 				&er.TransactionHash,
 				&er.CreatedBy.Username,
 				&er.CreatedBy.ChatID,
+				&er.MaID,
 				&er.CreatedAt,
 				&er.UpdatedAt,
 			); err != nil {
@@ -229,7 +325,7 @@ func (r *ExchangeRequestRepository) GetAllByStatus(status ...AppType.ExchangeReq
 	arr := []*models.ExchangeRequest{}
 
 	sb := fmt.Sprintf(`
-	SELECT id, request_status, exchange_from, exchange_to, course, address, client_address, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, created_at, updated_at
+	SELECT id, request_status, exchange_from, exchange_to, course, address, memo, client_address, client_memo, expected_amount, transferred_amount, transaction_hash, created_by_username, created_by_chat_id, ma_id, created_at, updated_at
 	FROM request
 	WHERE %s
 	ORDER BY id DESC
@@ -253,12 +349,15 @@ func (r *ExchangeRequestRepository) GetAllByStatus(status ...AppType.ExchangeReq
 				&er.ExchangeTo,
 				&er.Course,
 				&er.Address,
+				&er.Memo,
 				&er.ClientAddress,
+				&er.ClientMemo,
 				&er.ExpectedAmount,
 				&er.TransferredAmount,
 				&er.TransactionHash,
 				&er.CreatedBy.Username,
 				&er.CreatedBy.ChatID,
+				&er.MaID,
 				&er.CreatedAt,
 				&er.UpdatedAt,
 			); err != nil {
@@ -274,6 +373,58 @@ func (r *ExchangeRequestRepository) GetAllByStatus(status ...AppType.ExchangeReq
 	return arr, nil
 }
 
+// Метод возвращает новые заявки, срок жизни которых истек.
+// Срок жизни задается в минутах для каждого направления обмена,
+// если для направления он не задан используется defaultTTL.
+func (r *ExchangeRequestRepository) GetAllExpired(defaultTTL int) ([]*models.ExchangeRequest, error) {
+	arr := []*models.ExchangeRequest{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT r.id, r.request_status, r.exchange_from, r.exchange_to, r.course, r.address, r.memo, r.client_address, r.client_memo, r.expected_amount, r.transferred_amount, r.transaction_hash, r.created_by_username, r.created_by_chat_id, r.ma_id, r.created_at, r.updated_at
+		FROM request r
+		LEFT JOIN exchange_directions d ON d.exchange_from=r.exchange_from AND d.exchange_to=r.exchange_to
+		WHERE r.request_status=$1 AND r.created_at < now() - make_interval(mins => COALESCE(NULLIF(d.request_ttl, 0), $2))
+		ORDER BY r.id
+		`,
+		AppType.ExchangeRequestNew,
+		defaultTTL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		er := &models.ExchangeRequest{}
+		if err := rows.Scan(
+			&er.ID,
+			&er.Status,
+			&er.ExchangeFrom,
+			&er.ExchangeTo,
+			&er.Course,
+			&er.Address,
+			&er.Memo,
+			&er.ClientAddress,
+			&er.ClientMemo,
+			&er.ExpectedAmount,
+			&er.TransferredAmount,
+			&er.TransactionHash,
+			&er.CreatedBy.Username,
+			&er.CreatedBy.ChatID,
+			&er.MaID,
+			&er.CreatedAt,
+			&er.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, er)
+	}
+
+	return arr, rows.Err()
+}
+
 /*
 	==========================================================================================
 	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
@@ -289,10 +440,46 @@ func (r *ExchangeRequestRepository) statusQueryGeneration(status ...AppType.Exch
 	return strings.Join(arr, " OR ")
 }
 
-func (r *ExchangeRequestRepository) queryGeneration(q *models.ExchangeRequestSelection) string {
+func (r *ExchangeRequestRepository) queryGeneration(q *models.ExchangeRequestSelection) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	add := func(condition string, v interface{}) {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
 	if q.Status != 0 {
-		return "WHERE request_status=" + strconv.Itoa(int(q.Status))
+		add("request_status=$%d", q.Status)
+	}
+
+	if q.ExchangeFrom != "" {
+		add("exchange_from=$%d", q.ExchangeFrom)
+	}
+
+	if q.ExchangeTo != "" {
+		add("exchange_to=$%d", q.ExchangeTo)
+	}
+
+	if q.Username != "" {
+		add("created_by_username=$%d", q.Username)
+	}
+
+	if q.ChatID != 0 {
+		add("created_by_chat_id=$%d", q.ChatID)
+	}
+
+	if q.DateFrom != "" {
+		add("created_at >= $%d", q.DateFrom)
+	}
+
+	if q.DateTo != "" {
+		add("created_at < $%d", q.DateTo)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
 	}
 
-	return ""
+	return "WHERE " + strings.Join(conditions, " AND "), args
 }
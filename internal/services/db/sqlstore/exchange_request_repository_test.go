@@ -0,0 +1,68 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL_ExchangeRequestRepository_GetAllExpired(t *testing.T) {
+	config := config.InitTestConfig(t)
+
+	database, teardown := db.TestDB(t, &config.Services.DB)
+	defer teardown("users", "request", "exchange_directions")
+
+	// Вызываю создание хранилища
+	s := sqlstore.Init(database)
+
+	u, err := db.CreateUser(t, s)
+	assert.NoError(t, err)
+	assert.NotNil(t, u)
+
+	assert.NoError(t, s.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom:     "USDTTRC20",
+		ExchangeTo:       "SBERRUB",
+		CourseCorrection: 1,
+		RequestTTL:       10,
+	}))
+
+	create := func(from string, status AppType.ExchangeRequestStatus, age string) *models.ExchangeRequest {
+		er := &models.ExchangeRequest{
+			Status:         status,
+			ExchangeFrom:   from,
+			ExchangeTo:     "SBERRUB",
+			Course:         "1",
+			Address:        "address",
+			ClientAddress:  "client_address",
+			ExpectedAmount: AppMoney.NewFromInt(10),
+			CreatedBy: models.UserFromBotRequest{
+				ChatID:   u.ChatID,
+				Username: u.Username,
+			},
+		}
+		assert.NoError(t, s.AdminPanel().ExchangeRequest().Create(er))
+
+		_, err := database.Exec("UPDATE request SET created_at=now() - $1::interval WHERE id=$2", age, er.ID)
+		assert.NoError(t, err)
+
+		return er
+	}
+
+	expired := create("USDTTRC20", AppType.ExchangeRequestNew, "30 minutes")
+	create("USDTTRC20", AppType.ExchangeRequestNew, "5 minutes")
+	create("USDTTRC20", AppType.ExchangeRequestPaid, "30 minutes")
+	byDefault := create("BTC", AppType.ExchangeRequestNew, "30 minutes")
+	create("BTC", AppType.ExchangeRequestNew, "15 minutes")
+
+	arr, err := s.AdminPanel().ExchangeRequest().GetAllExpired(20)
+	assert.NoError(t, err)
+	assert.Len(t, arr, 2)
+	assert.Equal(t, expired.ID, arr[0].ID)
+	assert.Equal(t, byDefault.ID, arr[1].ID)
+}
@@ -27,17 +27,21 @@ type ExchangerRepository struct {
 func (r *ExchangerRepository) Create(e *models.Exchanger) error {
 	if err := r.store.QueryRow(
 		`
-		INSERT INTO exchangers(name, url, created_by)
-		SELECT $1, $2, $3
-		RETURNING id, name, url, created_by, created_at, updated_at
+		INSERT INTO exchangers(name, url, format, mapping, created_by)
+		SELECT $1, $2, COALESCE(NULLIF($3, ''), 'xml'), COALESCE(NULLIF($4, ''), '{}')::jsonb, $5
+		RETURNING id, name, url, format, mapping, created_by, created_at, updated_at
 		`,
 		e.Name,
 		e.UrlToParse,
+		e.Format,
+		string(e.Mapping),
 		e.CreatedBy,
 	).Scan(
 		&e.ID,
 		&e.Name,
 		&e.UrlToParse,
+		&e.Format,
+		&e.Mapping,
 		&e.CreatedBy,
 		&e.CreatedAt,
 		&e.UpdatedAt,
@@ -57,18 +61,22 @@ func (r *ExchangerRepository) Update(e *models.Exchanger) error {
 	if err := r.store.QueryRow(
 		`
 		UPDATE exchangers
-		SET name=$1, url=$2, updated_at=$3
-		WHERE id=$4
-		RETURNING id, name, url, created_by, created_at, updated_at
+		SET name=$1, url=$2, format=COALESCE(NULLIF($3, ''), 'xml'), mapping=COALESCE(NULLIF($4, ''), '{}')::jsonb, updated_at=$5
+		WHERE id=$6
+		RETURNING id, name, url, format, mapping, created_by, created_at, updated_at
 		`,
 		e.Name,
 		e.UrlToParse,
+		e.Format,
+		string(e.Mapping),
 		time.Now().UTC().Format(core.DateStandart),
 		e.ID,
 	).Scan(
 		&e.ID,
 		&e.Name,
 		&e.UrlToParse,
+		&e.Format,
+		&e.Mapping,
 		&e.CreatedBy,
 		&e.CreatedAt,
 		&e.UpdatedAt,
@@ -106,7 +114,7 @@ func (r *ExchangerRepository) Selection(querys interface{}) ([]*models.Exchanger
 
 	rows, err := r.store.Query(
 		`
-		SELECT id, name, url, created_by, created_at, updated_at
+		SELECT id, name, url, format, mapping, created_by, created_at, updated_at
 		FROM exchangers
 		ORDER BY id DESC
 		OFFSET $1
@@ -126,6 +134,8 @@ func (r *ExchangerRepository) Selection(querys interface{}) ([]*models.Exchanger
 			&e.ID,
 			&e.Name,
 			&e.UrlToParse,
+			&e.Format,
+			&e.Mapping,
 			&e.CreatedBy,
 			&e.CreatedAt,
 			&e.UpdatedAt,
@@ -148,7 +158,7 @@ func (r *ExchangerRepository) Selection(querys interface{}) ([]*models.Exchanger
 func (r *ExchangerRepository) GetByName(e *models.Exchanger) error {
 	if err := r.store.QueryRow(
 		`
-		SELECT id, name, url, created_by, created_at, updated_at
+		SELECT id, name, url, format, mapping, created_by, created_at, updated_at
 		FROM exchangers
 		WHERE name=$1		
 		`,
@@ -157,6 +167,8 @@ func (r *ExchangerRepository) GetByName(e *models.Exchanger) error {
 		&e.ID,
 		&e.Name,
 		&e.UrlToParse,
+		&e.Format,
+		&e.Mapping,
 		&e.CreatedBy,
 		&e.CreatedAt,
 		&e.UpdatedAt,
@@ -167,6 +179,45 @@ func (r *ExchangerRepository) GetByName(e *models.Exchanger) error {
 	return nil
 }
 
+/*
+	Получить все записи из таблицы `exchangers`
+*/
+func (r *ExchangerRepository) GetAll() ([]*models.Exchanger, error) {
+	arr := []*models.Exchanger{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT id, name, url, format, mapping, created_by, created_at, updated_at
+		FROM exchangers
+		ORDER BY id
+		`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := models.Exchanger{}
+		if err := rows.Scan(
+			&e.ID,
+			&e.Name,
+			&e.UrlToParse,
+			&e.Format,
+			&e.Mapping,
+			&e.CreatedBy,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, &e)
+	}
+
+	return arr, rows.Err()
+}
+
 /*
 	Удалить запись в таблице `exchangers`
 
@@ -177,13 +228,15 @@ func (r *ExchangerRepository) Delete(e *models.Exchanger) error {
 		`
 		DELETE FROM exchangers
 		WHERE id=$1
-		RETURNING id, name, url, created_by, created_at, updated_at
+		RETURNING id, name, url, format, mapping, created_by, created_at, updated_at
 		`,
 		e.ID,
 	).Scan(
 		&e.ID,
 		&e.Name,
 		&e.UrlToParse,
+		&e.Format,
+		&e.Mapping,
 		&e.CreatedBy,
 		&e.CreatedAt,
 		&e.UpdatedAt,
@@ -0,0 +1,91 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type HistoryCursorRepository struct {
+	store *sql.DB
+}
+
+/*
+	Получить позицию слушателя в истории аккаунта из таблицы `history_cursors`
+*/
+func (r *HistoryCursorRepository) Get(c *models.HistoryCursor) error {
+	return r.scan(c, r.store.QueryRow(
+		`
+		SELECT ma_id, created_at, seen, pending, gap_offset, ceiling, head, updated_at
+		FROM history_cursors
+		WHERE ma_id=$1
+		`,
+		c.MaID,
+	))
+}
+
+/*
+	Сохранить позицию слушателя в истории аккаунта в таблице `history_cursors`
+*/
+func (r *HistoryCursorRepository) Save(c *models.HistoryCursor) error {
+	seen, err := json.Marshal(c.Seen)
+	if err != nil {
+		return err
+	}
+
+	pending, err := json.Marshal(c.Pending)
+	if err != nil {
+		return err
+	}
+
+	return r.scan(c, r.store.QueryRow(
+		`
+		INSERT INTO history_cursors(ma_id, created_at, seen, pending, gap_offset, ceiling, head, updated_at)
+		SELECT $1, $2, $3::jsonb, $4::jsonb, $5, $6, $7, $8
+		ON CONFLICT (ma_id) DO UPDATE
+		SET created_at=EXCLUDED.created_at, seen=EXCLUDED.seen, pending=EXCLUDED.pending,
+			gap_offset=EXCLUDED.gap_offset, ceiling=EXCLUDED.ceiling, head=EXCLUDED.head, updated_at=EXCLUDED.updated_at
+		RETURNING ma_id, created_at, seen, pending, gap_offset, ceiling, head, updated_at
+		`,
+		c.MaID,
+		c.CreatedAt,
+		string(seen),
+		string(pending),
+		c.Offset,
+		c.Ceiling,
+		c.Head,
+		time.Now().UTC().Format(core.DateStandart),
+	))
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *HistoryCursorRepository) scan(c *models.HistoryCursor, row *sql.Row) error {
+	var seen, pending []byte
+	if err := row.Scan(
+		&c.MaID,
+		&c.CreatedAt,
+		&seen,
+		&pending,
+		&c.Offset,
+		&c.Ceiling,
+		&c.Head,
+		&c.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	c.Seen, c.Pending = []string{}, map[string]int64{}
+	if err := json.Unmarshal(seen, &c.Seen); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(pending, &c.Pending)
+}
@@ -2,6 +2,7 @@ package sqlstore
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/lib/pq"
 )
 
 type MerchantAutopayoutRepository struct {
@@ -84,6 +86,26 @@ func (r *MerchantAutopayoutRepository) Update(m *models.MerchantAutopayout) erro
 	return nil
 }
 
+// Метод изменяет только статус аккаунта id, остальные поля
+// аккаунта, измененные менеджером, не перезаписываются
+func (r *MerchantAutopayoutRepository) SetStatus(id int, status bool) error {
+	if err := r.store.QueryRow(
+		`
+		UPDATE merchant_autopayout
+		SET status=$1, updated_at=$2
+		WHERE id=$3
+		RETURNING id
+		`,
+		status,
+		time.Now().UTC().Format(core.DateStandart),
+		id,
+	).Scan(&id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *MerchantAutopayoutRepository) Get(m *models.MerchantAutopayout) error {
 	if err := r.store.QueryRow(
 		`
@@ -130,6 +152,12 @@ func (r *MerchantAutopayoutRepository) Delete(m *models.MerchantAutopayout) erro
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	); err != nil {
+		// На аккаунт ссылаются выплаты, их история не удаляется
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation" {
+			return AppError.ErrRecordInUse
+		}
+
 		return err
 	}
 
@@ -244,6 +272,7 @@ func (r *MerchantAutopayoutRepository) GetAllByServiceType(serviceType int, stat
 		SELECT id, name, service, service_type, options, status, message_id, created_by, created_at, updated_at
 		FROM merchant_autopayout
 		WHERE service_type=$1 AND status=$2
+		ORDER BY id
 		`,
 		serviceType,
 		status,
@@ -1,6 +1,7 @@
 package sqlstore_test
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
 
@@ -239,6 +240,18 @@ func Test_SQL_MerchantAutopayoutRepository_Create_Update_Delete(t *testing.T) {
 		}
 	})
 
+	t.Run("SetStatus", func(t *testing.T) {
+		assert.NoError(t, s.AdminPanel().MerchantAutopayout().SetStatus(ma.ID, false))
+
+		m := &models.MerchantAutopayout{ID: ma.ID}
+		assert.NoError(t, s.AdminPanel().MerchantAutopayout().Get(m))
+		assert.False(t, m.Status)
+		assert.Equal(t, ma.Name, m.Name)
+		assert.Equal(t, ma.Options, m.Options)
+
+		assert.ErrorIs(t, s.AdminPanel().MerchantAutopayout().SetStatus(0, false), sql.ErrNoRows)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		assert.NoError(t, s.AdminPanel().MerchantAutopayout().Delete(ma))
 		assert.NotNil(t, ma)
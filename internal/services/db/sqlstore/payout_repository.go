@@ -0,0 +1,198 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type PayoutRepository struct {
+	store *sql.DB
+}
+
+func (r *PayoutRepository) Create(p *models.Payout) error {
+	if err := r.store.QueryRow(
+		`
+		INSERT INTO payouts(request_id, ma_id, ticker, network, amount, payout_status)
+		SELECT $1, $2, $3, $4, $5, $6
+		RETURNING id, request_id, ma_id, ticker, network, amount, payout_status, raw_response, error_text, created_at, updated_at
+		`,
+		p.RequestID,
+		p.MaID,
+		p.Ticker,
+		p.Network,
+		p.Amount,
+		p.Status,
+	).Scan(
+		&p.ID,
+		&p.RequestID,
+		&p.MaID,
+		&p.Ticker,
+		&p.Network,
+		&p.Amount,
+		&p.Status,
+		&p.RawResponse,
+		&p.Error,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) Update(p *models.Payout) error {
+	if err := r.store.QueryRow(
+		`
+		UPDATE payouts
+		SET payout_status=$1, raw_response=$2, error_text=$3, updated_at=$4
+		WHERE id=$5
+		RETURNING id, request_id, ma_id, ticker, network, amount, payout_status, raw_response, error_text, created_at, updated_at
+		`,
+		p.Status,
+		p.RawResponse,
+		p.Error,
+		time.Now().UTC().Format(core.DateStandart),
+		p.ID,
+	).Scan(
+		&p.ID,
+		&p.RequestID,
+		&p.MaID,
+		&p.Ticker,
+		&p.Network,
+		&p.Amount,
+		&p.Status,
+		&p.RawResponse,
+		&p.Error,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Получить последнюю попытку автовыплаты по заявке p.RequestID
+func (r *PayoutRepository) GetLastByRequest(p *models.Payout) error {
+	if err := r.store.QueryRow(
+		`
+		SELECT id, request_id, ma_id, ticker, network, amount, payout_status, raw_response, error_text, created_at, updated_at
+		FROM payouts
+		WHERE request_id=$1
+		ORDER BY id DESC
+		LIMIT 1
+		`,
+		p.RequestID,
+	).Scan(
+		&p.ID,
+		&p.RequestID,
+		&p.MaID,
+		&p.Ticker,
+		&p.Network,
+		&p.Amount,
+		&p.Status,
+		&p.RawResponse,
+		&p.Error,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) Count(querys interface{}) (int, error) {
+	q := querys.(*models.PayoutSelection)
+	var c int
+
+	sb := fmt.Sprintf(`
+		SELECT count(*)
+		FROM payouts
+		%s
+	`,
+		r.queryGeneration(q),
+	)
+
+	if err := r.store.QueryRow(sb).Scan(&c); err != nil {
+		return 0, err
+	}
+
+	return c, nil
+}
+
+func (r *PayoutRepository) Selection(querys interface{}) ([]*models.Payout, error) {
+	q := querys.(*models.PayoutSelection)
+	arr := []*models.Payout{}
+
+	sb := fmt.Sprintf(`
+		SELECT id, request_id, ma_id, ticker, network, amount, payout_status, raw_response, error_text, created_at, updated_at
+		FROM payouts
+		%s
+		ORDER BY id DESC
+		OFFSET %d
+		LIMIT %d
+	`,
+		r.queryGeneration(q),
+		AppMath.OffsetThreshold(*q.Page, *q.Limit),
+		*q.Limit,
+	)
+
+	rows, err := r.store.Query(sb)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows != nil {
+		for rows.Next() {
+			p := &models.Payout{}
+			if err := rows.Scan(
+				&p.ID,
+				&p.RequestID,
+				&p.MaID,
+				&p.Ticker,
+				&p.Network,
+				&p.Amount,
+				&p.Status,
+				&p.RawResponse,
+				&p.Error,
+				&p.CreatedAt,
+				&p.UpdatedAt,
+			); err != nil {
+				continue
+			}
+
+			arr = append(arr, p)
+		}
+
+		return arr, nil
+	}
+
+	return nil, AppError.ErrInvalidCondition
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *PayoutRepository) queryGeneration(q *models.PayoutSelection) string {
+	if q.RequestID != 0 {
+		return fmt.Sprintf("WHERE request_id=%d", q.RequestID)
+	}
+
+	if q.MaID != 0 {
+		return fmt.Sprintf("WHERE ma_id=%d", q.MaID)
+	}
+
+	return ""
+}
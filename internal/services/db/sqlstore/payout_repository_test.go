@@ -0,0 +1,120 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/mocks"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL_PayoutRepository(t *testing.T) {
+	config := config.InitTestConfig(t)
+
+	database, teardown := db.TestDB(t, &config.Services.DB)
+	defer teardown("users", "bot_messages", "merchant_autopayout", "request", "payouts")
+
+	// Вызываю создание хранилища
+	s := sqlstore.Init(database)
+
+	u, err := db.CreateUser(t, s)
+	assert.NoError(t, err)
+	assert.NotNil(t, u)
+
+	// Создание сообщения
+	var m *models.BotMessage
+	assert.NoError(t, mapstructure.Decode(mocks.BOT_MESSAGE_REQ, &m))
+	m.MessageText = "some text"
+	m.CreatedBy = u.Username
+	assert.NoError(t, s.AdminPanel().BotMessages().Create(m))
+
+	// Создание аккаунта автовыплат
+	var ma *models.MerchantAutopayout
+	assert.NoError(t, mapstructure.Decode(mocks.MerchantAutopayout, &ma))
+	ma.MessageID = m.ID
+	ma.CreatedBy = u.Username
+	ma.ServiceType = AppType.UseAsAutoPayout
+	assert.NoError(t, s.AdminPanel().MerchantAutopayout().Create(ma))
+
+	// Создание заявки
+	er := &models.ExchangeRequest{
+		Status:         AppType.ExchangeRequestPaid,
+		ExchangeFrom:   "BTC",
+		ExchangeTo:     "USDTTRC20",
+		Course:         "1",
+		Address:        "address",
+		ClientAddress:  "client_address",
+		ExpectedAmount: AppMoney.NewFromInt(10),
+		CreatedBy: models.UserFromBotRequest{
+			ChatID:   u.ChatID,
+			Username: u.Username,
+		},
+	}
+	assert.NoError(t, s.AdminPanel().ExchangeRequest().Create(er))
+
+	// Попыток автовыплаты еще нет
+	assert.Error(t, s.AdminPanel().Payout().GetLastByRequest(&models.Payout{RequestID: er.ID}))
+
+	first := &models.Payout{
+		RequestID: er.ID,
+		MaID:      ma.ID,
+		Amount:    er.ExpectedAmount,
+		Ticker:    "USDT",
+		Network:   AppType.CurrencyNetworkTRC20,
+		Status:    AppType.PayoutPending,
+	}
+	assert.NoError(t, s.AdminPanel().Payout().Create(first))
+	assert.NotZero(t, first.ID)
+
+	e := "connection refused"
+	first.Status = AppType.PayoutFailed
+	first.Error = &e
+	assert.NoError(t, s.AdminPanel().Payout().Update(first))
+	assert.Equal(t, AppType.PayoutFailed, first.Status)
+	assert.Equal(t, e, *first.Error)
+	assert.Nil(t, first.RawResponse)
+
+	second := &models.Payout{
+		RequestID: er.ID,
+		MaID:      ma.ID,
+		Amount:    er.ExpectedAmount,
+		Status:    AppType.PayoutPending,
+	}
+	assert.NoError(t, s.AdminPanel().Payout().Create(second))
+
+	last := &models.Payout{RequestID: er.ID}
+	assert.NoError(t, s.AdminPanel().Payout().GetLastByRequest(last))
+	assert.Equal(t, second.ID, last.ID)
+	assert.Equal(t, ma.ID, last.MaID)
+	assert.Equal(t, AppType.PayoutPending, last.Status)
+
+	p, l := 1, 15
+	t.Run("Selection by request", func(t *testing.T) {
+		q := &models.PayoutSelection{Page: &p, Limit: &l, RequestID: er.ID}
+		assert.NoError(t, q.Validation())
+
+		c, err := s.AdminPanel().Payout().Count(q)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, c)
+
+		arr, err := s.AdminPanel().Payout().Selection(q)
+		assert.NoError(t, err)
+		assert.Len(t, arr, 2)
+	})
+
+	t.Run("Selection by account", func(t *testing.T) {
+		q := &models.PayoutSelection{Page: &p, Limit: &l, MaID: ma.ID}
+		assert.NoError(t, q.Validation())
+
+		arr, err := s.AdminPanel().Payout().Selection(q)
+		assert.NoError(t, err)
+		assert.Len(t, arr, 2)
+		assert.Equal(t, "USDT", arr[1].Ticker)
+	})
+}
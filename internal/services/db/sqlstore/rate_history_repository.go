@@ -0,0 +1,166 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type RateHistoryRepository struct {
+	store *sql.DB
+}
+
+// Агрегаты курса источника и скорректированного курса
+const rateHistoryStats = `
+	count(*),
+	COALESCE(ROUND(MIN(source_out/source_in), 8), 0),
+	COALESCE(ROUND(MAX(source_out/source_in), 8), 0),
+	COALESCE(ROUND(AVG(source_out/source_in), 8), 0),
+	COALESCE(ROUND(MIN(rate_out/rate_in), 8), 0),
+	COALESCE(ROUND(MAX(rate_out/rate_in), 8), 0),
+	COALESCE(ROUND(AVG(rate_out/rate_in), 8), 0)
+`
+
+// Без источника выбирается история опорного курса направления
+const rateHistoryCondition = `
+	direction_id=$1 AND created_at >= $2 AND created_at < $3
+	AND (exchanger_id=$4 OR ($4=0 AND reference=TRUE))
+`
+
+/*
+	Сохранить курсы одного обновления в таблицу `rate_history`
+*/
+func (r *RateHistoryRepository) Create(arr []*models.RateHistory) error {
+	tx, err := r.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`
+		INSERT INTO rate_history(direction_id, exchanger_id, source_in, source_out, course_correction, rate_in, rate_out, reference)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8
+		RETURNING id, created_at
+		`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, h := range arr {
+		if err := stmt.QueryRow(
+			h.DirectionID,
+			h.ExchangerID,
+			h.SourceIn,
+			h.SourceOut,
+			h.CourseCorrection,
+			h.In,
+			h.Out,
+			h.Reference,
+		).Scan(
+			&h.ID,
+			&h.CreatedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+/*
+	Временной ряд курса направления с шагом q.Interval
+*/
+func (r *RateHistoryRepository) Series(q *models.RateHistorySelection) ([]*models.RateHistoryPoint, error) {
+	arr := []*models.RateHistoryPoint{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT date_trunc($5, created_at) AS t,`+rateHistoryStats+`
+		FROM rate_history
+		WHERE `+rateHistoryCondition+`
+		GROUP BY t
+		ORDER BY t
+		`,
+		q.DirectionID,
+		q.DateFrom,
+		q.DateTo,
+		q.ExchangerID,
+		q.Interval,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := &models.RateHistoryPoint{}
+		if err := rows.Scan(
+			&p.Time,
+			&p.Count,
+			&p.Source.Min,
+			&p.Source.Max,
+			&p.Source.Avg,
+			&p.Course.Min,
+			&p.Course.Max,
+			&p.Course.Avg,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, p)
+	}
+
+	return arr, rows.Err()
+}
+
+/*
+	Сводка по курсу направления за весь период
+*/
+func (r *RateHistoryRepository) Summary(q *models.RateHistorySelection) (*models.RateHistoryStats, error) {
+	s := &models.RateHistoryStats{}
+
+	if err := r.store.QueryRow(
+		`
+		SELECT`+rateHistoryStats+`
+		FROM rate_history
+		WHERE `+rateHistoryCondition,
+		q.DirectionID,
+		q.DateFrom,
+		q.DateTo,
+		q.ExchangerID,
+	).Scan(
+		&s.Count,
+		&s.Source.Min,
+		&s.Source.Max,
+		&s.Source.Avg,
+		&s.Course.Min,
+		&s.Course.Max,
+		&s.Course.Avg,
+	); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+/*
+	Удалить записи старше date из таблицы `rate_history`
+*/
+func (r *RateHistoryRepository) DeleteBefore(date string) (int, error) {
+	res, err := r.store.Exec(
+		`
+		DELETE FROM rate_history
+		WHERE created_at < $1
+		`,
+		date,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := res.RowsAffected()
+	return int(c), err
+}
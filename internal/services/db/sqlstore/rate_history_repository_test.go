@@ -0,0 +1,90 @@
+package sqlstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/mocks"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL_RateHistoryRepository(t *testing.T) {
+	config := config.InitTestConfig(t)
+
+	db, teardown := db.TestDB(t, &config.Services.DB)
+	defer teardown("rate_history", "exchange_directions", "exchangers")
+
+	s := sqlstore.Init(db)
+
+	var ex *models.Exchanger
+	mapstructure.Decode(mocks.ADMIN_EXCHANGER, &ex)
+	assert.NoError(t, s.AdminPanel().Exchanger().Create(ex))
+
+	d := &models.Direction{
+		ExchangeFrom:     "USDTTRC20",
+		ExchangeTo:       "SBERRUB",
+		CourseCorrection: 100,
+		RequestTTL:       10,
+	}
+	assert.NoError(t, s.AdminPanel().Directions().Create(d))
+
+	// Создание
+	assert.NoError(t, s.AdminPanel().RateHistory().Create([]*models.RateHistory{
+		{
+			DirectionID: d.ID,
+			ExchangerID: ex.ID,
+			SourceIn:    AppMoney.RequireFromString("1"),
+			SourceOut:   AppMoney.RequireFromString("100"),
+			In:          AppMoney.RequireFromString("1"),
+			Out:         AppMoney.RequireFromString("101"),
+			Reference:   true,
+		},
+		{
+			DirectionID: d.ID,
+			ExchangerID: ex.ID,
+			SourceIn:    AppMoney.RequireFromString("1"),
+			SourceOut:   AppMoney.RequireFromString("90"),
+			In:          AppMoney.RequireFromString("1"),
+			Out:         AppMoney.RequireFromString("90.9"),
+		},
+	}))
+
+	now := time.Now().UTC()
+	q := &models.RateHistorySelection{
+		DirectionID: d.ID,
+		Interval:    AppType.RateHistoryHour,
+		DateFrom:    now.Add(-time.Hour).Format(core.DateStandart),
+		DateTo:      now.Add(time.Hour).Format(core.DateStandart),
+	}
+
+	// Временной ряд опорного курса
+	points, err := s.AdminPanel().RateHistory().Series(q)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, points)
+
+	// Сводка по опорному курсу
+	summary, err := s.AdminPanel().RateHistory().Summary(q)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Count)
+	assert.Equal(t, "101", summary.Course.Max.String())
+
+	// Сводка по всем котировкам источника
+	q.ExchangerID = ex.ID
+	summary, err = s.AdminPanel().RateHistory().Summary(q)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Count)
+	assert.Equal(t, "90", summary.Source.Min.String())
+
+	// Удаление устаревших записей
+	c, err := s.AdminPanel().RateHistory().DeleteBefore(now.Add(time.Hour).Format(core.DateStandart))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, c)
+}
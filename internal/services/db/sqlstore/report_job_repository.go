@@ -0,0 +1,169 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type ReportJobRepository struct {
+	store *sql.DB
+}
+
+/*
+	Создать задачу в таблице `report_jobs`
+*/
+func (r *ReportJobRepository) Create(rj *models.ReportJob) error {
+	if err := r.store.QueryRow(
+		`
+		INSERT INTO report_jobs(type, status, params, created_by)
+		SELECT $1, $2, COALESCE(NULLIF($3, ''), '{}')::jsonb, $4
+		RETURNING id, type, status, params, file, error, created_by, created_at, updated_at
+		`,
+		rj.Type,
+		AppType.ReportStatusPending,
+		string(rj.Params),
+		rj.CreatedBy,
+	).Scan(
+		&rj.ID,
+		&rj.Type,
+		&rj.Status,
+		&rj.Params,
+		&rj.File,
+		&rj.Error,
+		&rj.CreatedBy,
+		&rj.CreatedAt,
+		&rj.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+	Обновить статус, файл и ошибку задачи в таблице `report_jobs`
+*/
+func (r *ReportJobRepository) Update(rj *models.ReportJob) error {
+	if err := r.store.QueryRow(
+		`
+		UPDATE report_jobs
+		SET status=$1, file=$2, error=$3, updated_at=$4
+		WHERE id=$5
+		RETURNING id, type, status, params, file, error, created_by, created_at, updated_at
+		`,
+		rj.Status,
+		rj.File,
+		rj.Error,
+		time.Now().UTC().Format(core.DateStandart),
+		rj.ID,
+	).Scan(
+		&rj.ID,
+		&rj.Type,
+		&rj.Status,
+		&rj.Params,
+		&rj.File,
+		&rj.Error,
+		&rj.CreatedBy,
+		&rj.CreatedAt,
+		&rj.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReportJobRepository) Get(rj *models.ReportJob) error {
+	if err := r.store.QueryRow(
+		`
+		SELECT id, type, status, params, file, error, created_by, created_at, updated_at
+		FROM report_jobs
+		WHERE id=$1
+		`,
+		rj.ID,
+	).Scan(
+		&rj.ID,
+		&rj.Type,
+		&rj.Status,
+		&rj.Params,
+		&rj.File,
+		&rj.Error,
+		&rj.CreatedBy,
+		&rj.CreatedAt,
+		&rj.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Получить все задачи, формирование которых не было
+// завершено, например из-за перезапуска сервера
+func (r *ReportJobRepository) GetAllUnfinished() ([]*models.ReportJob, error) {
+	return r.selection(
+		`
+		SELECT id, type, status, params, file, error, created_by, created_at, updated_at
+		FROM report_jobs
+		WHERE status=$1 OR status=$2
+		ORDER BY id
+		`,
+		AppType.ReportStatusPending,
+		AppType.ReportStatusRunning,
+	)
+}
+
+// Получить все готовые отчеты, сформированные раньше before
+func (r *ReportJobRepository) GetAllExpired(before string) ([]*models.ReportJob, error) {
+	return r.selection(
+		`
+		SELECT id, type, status, params, file, error, created_by, created_at, updated_at
+		FROM report_jobs
+		WHERE status=$1 AND updated_at < $2
+		ORDER BY id
+		`,
+		AppType.ReportStatusDone,
+		before,
+	)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *ReportJobRepository) selection(query string, args ...interface{}) ([]*models.ReportJob, error) {
+	arr := []*models.ReportJob{}
+
+	rows, err := r.store.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rj := &models.ReportJob{}
+		if err := rows.Scan(
+			&rj.ID,
+			&rj.Type,
+			&rj.Status,
+			&rj.Params,
+			&rj.File,
+			&rj.Error,
+			&rj.CreatedBy,
+			&rj.CreatedAt,
+			&rj.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, rj)
+	}
+
+	return arr, rows.Err()
+}
@@ -0,0 +1,49 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+type RequestStatusHistoryRepository struct {
+	store *sql.DB
+}
+
+// Метод возвращает историю статусов заявки в хронологическом порядке
+func (r *RequestStatusHistoryRepository) GetAllByRequest(requestID int) ([]*models.RequestStatusHistory, error) {
+	arr := []*models.RequestStatusHistory{}
+
+	rows, err := r.store.Query(
+		`
+		SELECT id, request_id, status_from, status_to, actor, reason, created_at
+		FROM request_status_history
+		WHERE request_id=$1
+		ORDER BY id
+		`,
+		requestID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h := &models.RequestStatusHistory{}
+		if err := rows.Scan(
+			&h.ID,
+			&h.RequestID,
+			&h.StatusFrom,
+			&h.StatusTo,
+			&h.Actor,
+			&h.Reason,
+			&h.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, h)
+	}
+
+	return arr, rows.Err()
+}
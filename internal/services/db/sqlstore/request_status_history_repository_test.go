@@ -0,0 +1,78 @@
+package sqlstore_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL_ExchangeRequestRepository_Transition(t *testing.T) {
+	config := config.InitTestConfig(t)
+
+	database, teardown := db.TestDB(t, &config.Services.DB)
+	defer teardown("users", "request", "request_status_history")
+
+	// Вызываю создание хранилища
+	s := sqlstore.Init(database)
+
+	u, err := db.CreateUser(t, s)
+	assert.NoError(t, err)
+	assert.NotNil(t, u)
+
+	er := &models.ExchangeRequest{
+		Status:         AppType.ExchangeRequestNew,
+		ExchangeFrom:   "BTC",
+		ExchangeTo:     "USDTTRC20",
+		Course:         "1",
+		Address:        "address",
+		ClientAddress:  "client_address",
+		ExpectedAmount: AppMoney.NewFromInt(10),
+		CreatedBy: models.UserFromBotRequest{
+			ChatID:   u.ChatID,
+			Username: u.Username,
+		},
+	}
+	assert.NoError(t, s.AdminPanel().ExchangeRequest().Create(er))
+
+	// Недопустимый переход
+	_, err = er.Transition(AppType.ExchangeRequestDone, AppType.ExchangeRequestActorListener, "")
+	assert.True(t, errors.Is(err, AppError.ErrInvalidStatusTransition))
+
+	hash := "hash"
+	er.TransferredAmount = AppMoney.NewFromInt(10)
+	er.TransactionHash = &hash
+
+	h, err := er.Transition(AppType.ExchangeRequestPaid, AppType.ExchangeRequestActorListener, "deposit received")
+	assert.NoError(t, err)
+	assert.NoError(t, s.AdminPanel().ExchangeRequest().Transition(er, h))
+	assert.Equal(t, AppType.ExchangeRequestPaid, er.Status)
+	assert.Equal(t, hash, *er.TransactionHash)
+	assert.NotZero(t, h.ID)
+
+	// Статус заявки в БД уже изменился
+	stale := &models.ExchangeRequest{ID: er.ID, Status: AppType.ExchangeRequestNew}
+	h2, err := stale.Transition(AppType.ExchangeRequestCanceled, AppType.ExchangeRequestActorBot, "canceled by user")
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(s.AdminPanel().ExchangeRequest().Transition(stale, h2), AppError.ErrInvalidStatusTransition))
+
+	// Update не меняет статус заявки
+	er.Status = AppType.ExchangeRequestDone
+	assert.NoError(t, s.AdminPanel().ExchangeRequest().Update(er))
+	assert.Equal(t, AppType.ExchangeRequestPaid, er.Status)
+
+	history, err := s.AdminPanel().RequestStatusHistory().GetAllByRequest(er.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, AppType.ExchangeRequestNew, history[0].StatusFrom)
+	assert.Equal(t, AppType.ExchangeRequestPaid, history[0].StatusTo)
+	assert.Equal(t, AppType.ExchangeRequestActorListener, history[0].Actor)
+	assert.Equal(t, "deposit received", history[0].Reason)
+}
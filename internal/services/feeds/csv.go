@@ -0,0 +1,68 @@
+package feeds
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+// Выгрузка в формате csv. Первая строка - названия колонок,
+// колонки котировки задаются названиями в mapping.
+type csvParser struct {
+	mapping *models.FeedMapping
+}
+
+func (p *csvParser) Parse(r io.Reader) ([]models.OneObmenItem, error) {
+	cr := csv.NewReader(r)
+	cr.Comma, _ = utf8.DecodeRuneInString(p.mapping.Delimiter)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range []string{p.mapping.From, p.mapping.To, p.mapping.In, p.mapping.Out} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("header: column %s not found", name)
+		}
+	}
+
+	arr := []*fields{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		col := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+
+		arr = append(arr, &fields{
+			from:   col(p.mapping.From),
+			to:     col(p.mapping.To),
+			in:     col(p.mapping.In),
+			out:    col(p.mapping.Out),
+			amount: col(p.mapping.Amount),
+		})
+	}
+
+	return items(arr)
+}
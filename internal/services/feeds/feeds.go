@@ -0,0 +1,155 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+// Максимальный размер выгрузки источника в байтах
+const MaxFeedSize = 10 << 20
+
+// Разбор выгрузки курсов источника в котировки формата 1obmen
+type ParserI interface {
+	Parse(r io.Reader) ([]models.OneObmenItem, error)
+}
+
+// Функция возвращает парсер выгрузки формата format
+// с расположением полей m. Пустой формат - xml.
+func InitParser(format string, m *models.FeedMapping) (ParserI, error) {
+	switch format {
+	case "", AppType.FeedFormatXML:
+		return &xmlParser{}, nil
+	case AppType.FeedFormatJSON:
+		return &jsonParser{mapping: m}, nil
+	case AppType.FeedFormatCSV:
+		return &csvParser{mapping: m}, nil
+	}
+
+	return nil, fmt.Errorf("unknown feed format %s", format)
+}
+
+// Функция возвращает парсер выгрузки источника ex
+func InitExchangerParser(ex *models.Exchanger) (ParserI, error) {
+	m, err := ex.FeedMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	return InitParser(ex.Format, m)
+}
+
+// Функция загружает выгрузку по адресу url и разбирает ее парсером p.
+// Выгрузка больше MaxFeedSize не разбирается.
+func Fetch(ctx context.Context, client *http.Client, url string, p ParserI) ([]models.OneObmenItem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, MaxFeedSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) > MaxFeedSize {
+		return nil, fmt.Errorf("%w: %d bytes", AppError.ErrFeedTooLarge, MaxFeedSize)
+	}
+
+	return p.Parse(bytes.NewReader(b))
+}
+
+// Функция загружает и разбирает выгрузку источника ex
+func FetchExchanger(ctx context.Context, client *http.Client, ex *models.Exchanger) ([]models.OneObmenItem, error) {
+	p, err := InitExchangerParser(ex)
+	if err != nil {
+		return nil, err
+	}
+
+	return Fetch(ctx, client, ex.UrlToParse, p)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Набор строковых значений полей одной котировки
+type fields struct {
+	from, to, in, out, amount string
+}
+
+// Функция собирает котировку из строковых значений полей
+func item(f *fields) (models.OneObmenItem, error) {
+	it := models.OneObmenItem{
+		From: strings.TrimSpace(f.from),
+		To:   strings.TrimSpace(f.to),
+	}
+
+	if it.From == "" || it.To == "" {
+		return it, fmt.Errorf("empty currency pair")
+	}
+
+	var err error
+	if it.In, err = AppMoney.NewFromString(strings.TrimSpace(f.in)); err != nil {
+		return it, fmt.Errorf("in: %w", err)
+	}
+
+	if it.Out, err = AppMoney.NewFromString(strings.TrimSpace(f.out)); err != nil {
+		return it, fmt.Errorf("out: %w", err)
+	}
+
+	it.Amount = AppMoney.Zero
+	if strings.TrimSpace(f.amount) != "" {
+		if it.Amount, err = AppMoney.NewFromString(strings.TrimSpace(f.amount)); err != nil {
+			return it, fmt.Errorf("amount: %w", err)
+		}
+	}
+
+	return it, nil
+}
+
+// Функция собирает котировки из строковых значений. Котировки
+// с ошибками пропускаются, но если не удалось разобрать ни одной,
+// возвращается ошибка первой из них.
+func items(arr []*fields) ([]models.OneObmenItem, error) {
+	res := make([]models.OneObmenItem, 0, len(arr))
+
+	var first error
+	for i, f := range arr {
+		it, err := item(f)
+		if err != nil {
+			if first == nil {
+				first = fmt.Errorf("item %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		res = append(res, it)
+	}
+
+	if len(res) == 0 && first != nil {
+		return nil, first
+	}
+
+	return res, nil
+}
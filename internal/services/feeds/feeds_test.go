@@ -0,0 +1,176 @@
+package feeds_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/feeds"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Feeds_Parse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		format  string
+		mapping string
+		body    string
+		items   []string
+		isValid bool
+	}{
+		{
+			name:   "xml",
+			format: AppType.FeedFormatXML,
+			body: `
+				<rates>
+					<item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out> 75.5 </out><amount>100000</amount></item>
+				</rates>`,
+			items:   []string{"USDTTRC20/SBERRUB 1 75.5 100000"},
+			isValid: true,
+		},
+		{
+			name:    "json with default mapping",
+			format:  AppType.FeedFormatJSON,
+			body:    `[{"from":"USDTTRC20","to":"SBERRUB","in":1,"out":"75.5","amount":100000}]`,
+			items:   []string{"USDTTRC20/SBERRUB 1 75.5 100000"},
+			isValid: true,
+		},
+		{
+			name:    "json with nested paths",
+			format:  AppType.FeedFormatJSON,
+			mapping: `{"items":"data.rates","from":"pair.0","to":"pair.1","in":"price.give","out":"price.get","amount":"reserve"}`,
+			body: `{"data":{"rates":[
+				{"pair":["USDTTRC20","SBERRUB"],"price":{"give":1,"get":75.5},"reserve":"100000"},
+				{"pair":["BTC","SBERRUB"],"price":{"give":1,"get":"n/a"}}
+			]}}`,
+			items:   []string{"USDTTRC20/SBERRUB 1 75.5 100000"},
+			isValid: true,
+		},
+		{
+			name:    "json with object items",
+			format:  AppType.FeedFormatJSON,
+			mapping: `{"items":"rates"}`,
+			body:    `{"rates":{"b":{"from":"BTC","to":"SBERRUB","in":1,"out":3000000},"a":{"from":"USDTTRC20","to":"SBERRUB","in":1,"out":75.5}}}`,
+			items:   []string{"USDTTRC20/SBERRUB 1 75.5 0", "BTC/SBERRUB 1 3000000 0"},
+			isValid: true,
+		},
+		{
+			name:    "json items not found",
+			format:  AppType.FeedFormatJSON,
+			mapping: `{"items":"data"}`,
+			body:    `[]`,
+			isValid: false,
+		},
+		{
+			name:    "json without valid items",
+			format:  AppType.FeedFormatJSON,
+			body:    `[{"from":"USDTTRC20","to":"SBERRUB"}]`,
+			isValid: false,
+		},
+		{
+			name:    "csv",
+			format:  AppType.FeedFormatCSV,
+			mapping: `{"from":"currency_from","to":"currency_to","delimiter":";"}`,
+			body:    "currency_from;currency_to;in;out;amount\nUSDTTRC20;SBERRUB;1;75.5;100000\n",
+			items:   []string{"USDTTRC20/SBERRUB 1 75.5 100000"},
+			isValid: true,
+		},
+		{
+			name:    "csv without column",
+			format:  AppType.FeedFormatCSV,
+			body:    "from,to,in\nUSDTTRC20,SBERRUB,1\n",
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := feeds.InitExchangerParser(&models.Exchanger{Format: tc.format, Mapping: []byte(tc.mapping)})
+			assert.NoError(t, err)
+
+			arr, err := p.Parse(strings.NewReader(tc.body))
+			if !tc.isValid {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			items := []string{}
+			for _, it := range arr {
+				items = append(items, fmt.Sprintf("%s/%s %s %s %s", it.From, it.To, it.In.String(), it.Out.String(), it.Amount.String()))
+			}
+			assert.Equal(t, tc.items, items)
+		})
+	}
+}
+
+func Test_Feeds_FetchExchanger(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rates.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, `{"result":[{"from":"USDTTRC20","to":"SBERRUB","in":1,"out":75.5}]}`)
+	}))
+	defer source.Close()
+
+	ex := &models.Exchanger{
+		UrlToParse: source.URL + "/rates.json",
+		Format:     AppType.FeedFormatJSON,
+		Mapping:    []byte(`{"items":"result"}`),
+	}
+
+	arr, err := feeds.FetchExchanger(context.Background(), http.DefaultClient, ex)
+	assert.NoError(t, err)
+	assert.Len(t, arr, 1)
+
+	ex.UrlToParse = source.URL
+	_, err = feeds.FetchExchanger(context.Background(), http.DefaultClient, ex)
+	assert.Error(t, err)
+
+	_, err = feeds.InitParser("yaml", &models.FeedMapping{})
+	assert.Error(t, err)
+
+	// Выгрузка больше допустимого размера не разбирается
+	large := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":[`+strings.Repeat(" ", feeds.MaxFeedSize)+`]}`)
+	}))
+	defer large.Close()
+
+	ex.UrlToParse = large.URL
+	_, err = feeds.FetchExchanger(context.Background(), http.DefaultClient, ex)
+	assert.ErrorIs(t, err, AppError.ErrFeedTooLarge)
+}
+
+func Test_Feeds_MappingValidation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mapping string
+		isValid bool
+	}{
+		{name: "empty", mapping: ``, isValid: true},
+		{name: "valid", mapping: `{"items":"data","delimiter":";"}`, isValid: true},
+		{name: "not an object", mapping: `[]`, isValid: false},
+		{name: "invalid field type", mapping: `{"from":1}`, isValid: false},
+		{name: "long delimiter", mapping: `{"delimiter":";;"}`, isValid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &models.FeedTestParse{UrlToParse: "https://example.com/rates.json", Format: AppType.FeedFormatJSON, Mapping: []byte(tc.mapping)}
+			if tc.isValid {
+				assert.NoError(t, r.Validation())
+			} else {
+				assert.Error(t, r.Validation())
+			}
+		})
+	}
+}
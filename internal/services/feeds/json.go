@@ -0,0 +1,112 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+// Выгрузка в формате json. Котировки берутся из массива или
+// объекта по пути mapping.Items, поля котировки - по путям
+// относительно элемента.
+type jsonParser struct {
+	mapping *models.FeedMapping
+}
+
+func (p *jsonParser) Parse(r io.Reader) ([]models.OneObmenItem, error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+
+	var root interface{}
+	if err := d.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	v, ok := lookup(root, p.mapping.Items)
+	if !ok {
+		return nil, fmt.Errorf("items: path %s not found", p.mapping.Items)
+	}
+
+	var elements []interface{}
+	switch t := v.(type) {
+	case []interface{}:
+		elements = t
+	case map[string]interface{}:
+		// Котировки в объекте, например по ключу валютной пары
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			elements = append(elements, t[k])
+		}
+	default:
+		return nil, fmt.Errorf("items: path %s is not an array or object", p.mapping.Items)
+	}
+
+	arr := make([]*fields, 0, len(elements))
+	for _, el := range elements {
+		arr = append(arr, &fields{
+			from:   str(el, p.mapping.From),
+			to:     str(el, p.mapping.To),
+			in:     str(el, p.mapping.In),
+			out:    str(el, p.mapping.Out),
+			amount: str(el, p.mapping.Amount),
+		})
+	}
+
+	return items(arr)
+}
+
+// Функция возвращает значение по пути через точку,
+// числовой сегмент пути - индекс элемента массива
+func lookup(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+
+	for _, seg := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			next, ok := t[seg]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, false
+			}
+			v = t[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// Функция возвращает строковое или числовое значение по пути
+func str(v interface{}, path string) string {
+	v, ok := lookup(v, path)
+	if !ok {
+		return ""
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	}
+
+	return ""
+}
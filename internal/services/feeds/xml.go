@@ -0,0 +1,20 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+// Выгрузка в формате 1obmen <rates><item>...</item></rates>
+type xmlParser struct{}
+
+func (p *xmlParser) Parse(r io.Reader) ([]models.OneObmenItem, error) {
+	data := models.OneObmen{}
+	if err := xml.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data.Rates, nil
+}
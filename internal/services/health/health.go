@@ -0,0 +1,250 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/supervisor"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Процесс плановой проверки аккаунтов мерчантов/автовыплат.
+//
+// Периодически пингует все активные аккаунты и сохраняет результат
+// в таблицу `account_health`. Аккаунт, не ответивший cfg.MaxFailures
+// раз подряд, отключается, а менеджеры получают уведомление.
+// Аккаунты сервисов, плагин которых не поддерживает пинг, пропускаются.
+type Checker struct {
+	store  db.SQLStoreI
+	nsq    nsqstore.NsqI
+	plugin *plugins.AppPlugins
+	logger utils.LoggerI
+
+	supervisor supervisor.SupervisorI
+}
+
+type CheckerI interface {
+	Check(ctx context.Context, cfg *config.HealthConfig) error
+	Supervise(ctx context.Context, cfg *config.HealthConfig)
+	Status() (*models.AccountsHealthStatus, error)
+}
+
+func InitChecker(s db.SQLStoreI, q nsqstore.NsqI, p *plugins.AppPlugins, l utils.LoggerI) CheckerI {
+	return &Checker{
+		store:  s,
+		nsq:    q,
+		plugin: p,
+		logger: l,
+
+		supervisor: supervisor.Init(AppType.LogModuleHealth, 0, 0, l),
+	}
+}
+
+// Метод запускает периодическую проверку аккаунтов под наблюдением
+// супервизора. Блокирует выполнение до отмены контекста ctx.
+func (h *Checker) Supervise(ctx context.Context, cfg *config.HealthConfig) {
+	h.supervisor.Run(ctx, func(ctx context.Context) error {
+		for {
+			t := time.NewTimer(time.Duration(cfg.Interval) * time.Second)
+
+			if err := h.Check(ctx, cfg); err != nil {
+				t.Stop()
+				return err
+			}
+			h.supervisor.Tick()
+
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil
+			case <-t.C:
+			}
+		}
+	})
+}
+
+// Одна итерация проверки всех активных аккаунтов
+func (h *Checker) Check(ctx context.Context, cfg *config.HealthConfig) error {
+	arr := []*models.MerchantAutopayout{}
+	for _, serviceType := range []int{AppType.UseAsMerchant, AppType.UseAsAutoPayout} {
+		accounts, err := h.store.AdminPanel().MerchantAutopayout().GetAllByServiceType(serviceType, true)
+		if err != nil {
+			return err
+		}
+
+		arr = append(arr, accounts...)
+	}
+
+	for _, ma := range arr {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := h.check(ctx, cfg, ma); err != nil {
+			h.log(fmt.Sprintf("account %d: %s", ma.ID, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// Метод возвращает состояние процесса и результаты
+// последних проверок всех аккаунтов
+func (h *Checker) Status() (*models.AccountsHealthStatus, error) {
+	arr, err := h.store.AdminPanel().AccountHealth().GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AccountsHealthStatus{
+		WorkerState: h.supervisor.State(),
+		Accounts:    arr,
+	}, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Метод проверяет аккаунт ma и обновляет его состояние
+func (h *Checker) check(ctx context.Context, cfg *config.HealthConfig, ma *models.MerchantAutopayout) error {
+	health := &models.AccountHealth{MaID: ma.ID}
+	if err := h.store.AdminPanel().AccountHealth().Get(health); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// Аккаунт снова включен менеджером после
+	// автоматического отключения
+	if cfg.MaxFailures > 0 && health.ConsecutiveFailures >= cfg.MaxFailures {
+		health.ConsecutiveFailures = 0
+	}
+
+	latency, err := h.ping(ctx, cfg, ma)
+	if errors.Is(err, AppError.ErrPluginNotSupported) {
+		return nil
+	}
+
+	// Проверка прервана остановкой сервера
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	now := time.Now().UTC().Format(core.DateStandart)
+	health.Checks++
+	health.Latency = latency.Milliseconds()
+	health.CheckedAt = now
+
+	if err != nil {
+		health.Alive = false
+		health.Failures++
+		health.ConsecutiveFailures++
+		health.LastError = err.Error()
+		health.LastErrorAt = &now
+	} else {
+		health.Alive = true
+		health.ConsecutiveFailures = 0
+		health.LastSuccessAt = &now
+	}
+
+	if err := h.store.AdminPanel().AccountHealth().Save(health); err != nil {
+		return err
+	}
+
+	if cfg.MaxFailures > 0 && health.ConsecutiveFailures >= cfg.MaxFailures {
+		return h.disable(ma, health)
+	}
+
+	return nil
+}
+
+// Метод пингует аккаунт ma и возвращает время ответа
+func (h *Checker) ping(ctx context.Context, cfg *config.HealthConfig, ma *models.MerchantAutopayout) (time.Duration, error) {
+	plugin, err := h.plugin.Get(ma.Service)
+	if err != nil {
+		return 0, err
+	}
+
+	p, err := plugin.GetOptionParams(ma.Options)
+	if err != nil {
+		return 0, fmt.Errorf("%s | %s", AppError.ErrFailedToDecodeParams.Error(), err.Error())
+	}
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	started := time.Now()
+	err = plugin.Ping(ctx, p)
+	return time.Since(started), err
+}
+
+// Метод отключает аккаунт ma и уведомляет об этом менеджеров
+func (h *Checker) disable(ma *models.MerchantAutopayout, health *models.AccountHealth) error {
+	if err := h.store.AdminPanel().MerchantAutopayout().SetStatus(ma.ID, false); err != nil {
+		return err
+	}
+	ma.Status = false
+
+	h.log(fmt.Sprintf("account %d disabled after %d failed checks: %s", ma.ID, health.ConsecutiveFailures, health.LastError))
+
+	return h.notifyManagers(fmt.Sprintf("🔴 Аккаунт отключен 🔴\n\n*Аккаунт*: %s\n*Сервис*: %s\n*Неудачных проверок подряд*: %d\n*Ошибка*: `%s`",
+		ma.Name,
+		ma.Service,
+		health.ConsecutiveFailures,
+		health.LastError,
+	))
+}
+
+func (h *Checker) notifyManagers(text string) error {
+	uArr, err := h.store.User().GetAllManagers()
+	if err != nil {
+		return err
+	}
+
+	for _, u := range uArr {
+		payload, err := json.Marshal(map[string]interface{}{
+			"to": map[string]interface{}{
+				"chat_id":  u.ChatID,
+				"username": u.Username,
+			},
+			"message": map[string]interface{}{
+				"type": AppType.QueueEventExchangeError,
+				"text": text,
+			},
+			"created_at": time.Now().UTC().Format(core.DateStandart),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := h.nsq.Publish(AppType.TopicBotMessages, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Checker) log(info string) {
+	h.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  AppType.LogModuleHealth,
+		Info:    info,
+	})
+}
@@ -0,0 +1,159 @@
+package health_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/health"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Аккаунт, не ответивший MaxFailures раз подряд, отключается,
+	менеджеры получают уведомление
+*/
+func Test_Health_Check(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	alive := whitebittest.NewServer()
+	defer alive.Close()
+
+	broken := whitebittest.NewServer()
+	defer broken.Close()
+
+	store := mocksqlstore.Init()
+	nsq := &testNsq{}
+	hc := health.InitChecker(store, nsq, plugins.InitAppPlugins(&cfg.Plugins), utils.InitLogger(store.AdminPanel().Logs()))
+
+	hash := "hash"
+	assert.NoError(t, store.User().Create(&models.User{ChatID: 2, Username: "manager", Hash: &hash}))
+
+	aliveAccount := testAccount(t, store, cfg, AppType.UseAsMerchant, alive.Account("alive-public", "alive-secret"))
+	brokenAccount := testAccount(t, store, cfg, AppType.UseAsAutoPayout, broken.Account("broken-public", "broken-secret"))
+
+	for i := 1; i <= cfg.Health.MaxFailures; i++ {
+		testFail(broken)
+		testCheck(t, ctx, hc, cfg)
+
+		h := &models.AccountHealth{MaID: brokenAccount.ID}
+		assert.NoError(t, store.AdminPanel().AccountHealth().Get(h))
+		assert.False(t, h.Alive)
+		assert.Equal(t, i, h.ConsecutiveFailures)
+		assert.NotEmpty(t, h.LastError)
+		assert.NotNil(t, h.LastErrorAt)
+	}
+
+	// Аккаунт отключен после MaxFailures неудачных проверок подряд
+	ma := &models.MerchantAutopayout{ID: brokenAccount.ID}
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Get(ma))
+	assert.False(t, ma.Status)
+
+	messages := nsq.messages()
+	assert.Len(t, messages, 1)
+	assert.Contains(t, string(messages[0]), "broken-public")
+
+	// Доступный аккаунт проверялся каждый раз
+	h := &models.AccountHealth{MaID: aliveAccount.ID}
+	assert.NoError(t, store.AdminPanel().AccountHealth().Get(h))
+	assert.True(t, h.Alive)
+	assert.Equal(t, cfg.Health.MaxFailures, h.Checks)
+	assert.Zero(t, h.ConsecutiveFailures)
+	assert.NotNil(t, h.LastSuccessAt)
+
+	status, err := hc.Status()
+	assert.NoError(t, err)
+	assert.Len(t, status.Accounts, 2)
+
+	// Менеджер снова включил аккаунт, счетчик неудач начинается заново
+	ma.Status = true
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Update(ma))
+
+	testFail(broken)
+	testCheck(t, ctx, hc, cfg)
+
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Get(ma))
+	assert.True(t, ma.Status)
+
+	h = &models.AccountHealth{MaID: brokenAccount.ID}
+	assert.NoError(t, store.AdminPanel().AccountHealth().Get(h))
+	assert.Equal(t, 1, h.ConsecutiveFailures)
+	assert.Equal(t, cfg.Health.MaxFailures+1, h.Failures)
+	assert.Len(t, nsq.messages(), 1)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Имитация NSQ, сохраняющая все отправленные сообщения
+type testNsq struct {
+	mu  sync.Mutex
+	arr [][]byte
+}
+
+func (n *testNsq) Publish(topic string, payload []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.arr = append(n.arr, payload)
+	return nil
+}
+
+func (n *testNsq) messages() [][]byte {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([][]byte{}, n.arr...)
+}
+
+func testCheck(t *testing.T, ctx context.Context, hc health.CheckerI, cfg *config.Config) {
+	t.Helper()
+
+	assert.NoError(t, hc.Check(ctx, &cfg.Health))
+}
+
+// Запрос баланса повторяется клиентом Whitebit, поэтому
+// ошибкой должны завершиться все попытки
+func testFail(s *whitebittest.Server) {
+	for i := 0; i <= whitebit_plugin.MaxRetries; i++ {
+		s.FailNext(whitebittest.PathBalance, http.StatusServiceUnavailable, "Service unavailable")
+	}
+}
+
+func testAccount(t *testing.T, store db.SQLStoreI, cfg *config.Config, serviceType int, p *models.WhitebitOptionParams) *models.MerchantAutopayout {
+	t.Helper()
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+
+	options, err := AppMath.AesEncrypt(string(b), hex.EncodeToString([]byte(cfg.Plugins.AesKey)))
+	assert.NoError(t, err)
+
+	m := &models.MerchantAutopayout{
+		Name:        p.PublicKey,
+		Service:     AppType.MerchantAutoPayoutWhitebit,
+		ServiceType: serviceType,
+		Options:     options,
+		Status:      true,
+	}
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Create(m))
+
+	return m
+}
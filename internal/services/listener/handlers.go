@@ -1,24 +1,28 @@
 package listener
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 )
 
-/* Обработка событий биржи Whitebit */
+/* Обработка событий из истории транзакций аккаунтов */
 
-// Метод обработки события вывода средств
-func (listener *Listener) handleWhitebitWithdrawAction(rHistory models.WhitebitHistoryRecord, rRequest *models.ExchangeRequest) error {
+// Метод обработки события вывода средств. Вывод относится к заявке,
+// если он отправлен с ключом идемпотентности заявки (ее ID)
+func (listener *Listener) handleWithdrawAction(rHistory *interfaces.HistoryRecord, rRequest *models.ExchangeRequest) error {
 	if rRequest.TransactionHash != nil && rRequest.Status == AppType.ExchangeRequestAwaitingConfirmation {
 
-		if rHistory.Address == rRequest.ClientAddress {
-			rRequest.Status = AppType.ExchangeRequestDone
-			if err := listener.store.AdminPanel().ExchangeRequest().Update(rRequest); err != nil {
+		if rHistory.UniqueID == strconv.Itoa(rRequest.ID) {
+			if err := listener.transition(rRequest, AppType.ExchangeRequestDone, "withdrawal confirmed by provider"); err != nil {
 				fmt.Println(err)
+				return err
 			}
 
 			if err := listener.moneyHasBeenSent(rHistory, rRequest.CreatedBy); err != nil {
@@ -32,54 +36,98 @@ func (listener *Listener) handleWhitebitWithdrawAction(rHistory models.WhitebitH
 	return nil
 }
 
-// Метод обработки события нового депозита
-func (l *Listener) handleWhitebitDepositAction(rHistory models.WhitebitHistoryRecord, rRequest *models.ExchangeRequest) error {
-	if rHistory.Address == rRequest.Address {
+// Метод обработки события нового депозита на аккаунт maID. Депозит
+// засчитывается только заявке, адрес которой выдан этим аккаунтом.
+// Депозит, сделанный до создания заявки, относится к прежней заявке
+// на этот адрес и не засчитывается.
+func (l *Listener) handleDepositAction(maID int, rHistory *interfaces.HistoryRecord, rRequest *models.ExchangeRequest) error {
+	if rRequest.MaID != nil && *rRequest.MaID != maID {
+		return nil
+	}
+
+	createdAt, err := rRequest.CreatedTime()
+	if err != nil {
+		return err
+	}
+
+	if rHistory.CreatedAt < createdAt.Unix() {
+		return nil
+	}
+
+	if rHistory.Address == rRequest.Address && rHistory.Memo == rRequest.Memo {
 		// Проверяю статус операции
-		if rHistory.Status == 3 || rHistory.Status == 7 {
+		if rHistory.Status == interfaces.HistoryStatusSuccess {
 			if rRequest.Status == AppType.ExchangeRequestNew {
-				// Получаю переведенную сумму
-				amount, err := strconv.ParseFloat(rHistory.Amount, 64)
-				if err != nil {
-					return err
-				}
-
 				// Сохраняю сумму полученную от пользователя
-				rRequest.TransferredAmount = amount
+				rRequest.TransferredAmount = rHistory.Amount
 				rRequest.TransactionHash = &rHistory.TransactionHash
 
-				if rRequest.TransferredAmount == rRequest.ExpectedAmount {
+				expected := rRequest.ExpectedAmount
+				if rHistory.Amount.Equal(expected) {
 					// Если полученная сумма совпадает с ожидаемой суммой
-					rRequest.Status = AppType.ExchangeRequestPaid
-
-				} else if rRequest.TransferredAmount < rRequest.ExpectedAmount {
-					// Если полученная сумма меньше ожидаемой суммы
-					rRequest.Status = AppType.ExchangeRequestInvalidAmount
-
-					// Отправка уведомления
-					if err := l.amountLessThanExpected(rRequest.CreatedBy); err != nil {
-						return err
-					}
-				} else {
-					// Если полученная сумма больше ожидаемой суммы
-					rRequest.Status = AppType.ExchangeRequestInvalidAmount
-
-					// Отправка уведомления
-					if err := l.amountMoreThanExpected(rRequest.CreatedBy); err != nil {
-						return err
-					}
+					return l.depositTransition(rRequest, AppType.ExchangeRequestPaid, "deposit received", nil)
+				}
+
+				d, err := l.direction(rRequest)
+				if err != nil {
+					return err
+				}
+
+				diff := rHistory.Amount.Sub(expected)
+				if d.WithinTolerance(expected, rHistory.Amount) {
+					// Расхождение в пределах допустимого, выплата будет
+					// рассчитана от фактически полученной суммы
+					return l.depositTransition(rRequest, AppType.ExchangeRequestPaid,
+						fmt.Sprintf("deposit received within tolerance, difference %s", diff.String()), nil)
 				}
 
-				// Oбновляю запись в БД, отмечаю что деньги были получены
-				if err := l.store.AdminPanel().ExchangeRequest().Update(rRequest); err != nil {
+				// Расхождение больше допустимого, заявку проверяет менеджер
+				if err := l.depositTransition(rRequest, AppType.ExchangeRequestChecked,
+					fmt.Sprintf("received amount differs from expected by %s", diff.String()), l.amountMismatch); err != nil {
 					return err
 				}
 
-				utils.SetSuccessStep("New request processed")
-				return nil
+				return l.amountMismatchToManagers(rRequest, diff)
 			}
 		}
 	}
 
 	return nil
 }
+
+// Метод отмечает, что деньги по заявке были получены,
+// и отправляет пользователю уведомление notify
+func (l *Listener) depositTransition(rRequest *models.ExchangeRequest, to AppType.ExchangeRequestStatus, reason string, notify func(u models.UserFromBotRequest) error) error {
+	if err := l.transition(rRequest, to, reason); err != nil {
+		return err
+	}
+
+	// Отправка уведомления
+	if notify != nil {
+		if err := notify(rRequest.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	utils.SetSuccessStep("New request processed")
+	return nil
+}
+
+// Метод возвращает направление обмена заявки. Если направление
+// не найдено, расхождение сумм не допускается.
+func (l *Listener) direction(rRequest *models.ExchangeRequest) (*models.Direction, error) {
+	d := &models.Direction{
+		ExchangeFrom: rRequest.ExchangeFrom,
+		ExchangeTo:   rRequest.ExchangeTo,
+	}
+
+	if err := l.store.AdminPanel().Directions().GetByPair(d); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.Direction{}, nil
+		}
+
+		return nil, err
+	}
+
+	return d, nil
+}
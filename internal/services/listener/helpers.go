@@ -6,14 +6,26 @@ import (
 	"time"
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 )
 
-func (l *Listener) moneyHasBeenSent(rHistory models.WhitebitHistoryRecord, u models.UserFromBotRequest) error {
+// Метод переводит заявку в статус to от имени слушателя
+func (l *Listener) transition(rRequest *models.ExchangeRequest, to AppType.ExchangeRequestStatus, reason string) error {
+	h, err := rRequest.Transition(to, AppType.ExchangeRequestActorListener, reason)
+	if err != nil {
+		return err
+	}
+
+	return l.store.AdminPanel().ExchangeRequest().Transition(rRequest, h)
+}
+
+func (l *Listener) moneyHasBeenSent(rHistory *interfaces.HistoryRecord, u models.UserFromBotRequest) error {
 	text := fmt.Sprintf("✅Чек операции `%s`✅\n\nСумма перевода: *%s %s*\nАдрес: *%s*\nХеш: `%s`",
-		rHistory.UniqueId,
-		rHistory.Amount,
+		rHistory.UniqueID,
+		rHistory.Amount.String(),
 		rHistory.Ticker,
 		rHistory.Address,
 		rHistory.TransactionHash,
@@ -28,11 +40,11 @@ func (l *Listener) moneyHasBeenSent(rHistory models.WhitebitHistoryRecord, u mod
 	return l.nsq.Publish(AppType.TopicBotMessages, payload)
 }
 
-func (l *Listener) amountLessThanExpected(u models.UserFromBotRequest) error {
+func (l *Listener) amountMismatch(u models.UserFromBotRequest) error {
 	// Подготовка уведомления
 	payload, err := json.Marshal(prepareNotification(
 		u,
-		"❗️Отмена операции обмена❗️\n\nПереведенная сумма меньше ожидаемой.",
+		"❗️Заявка на проверке❗️\n\nПереведенная сумма отличается от ожидаемой. Не переживайте, наши менеджеры скоро свяжутся с вами.",
 	))
 	if err != nil {
 		return err
@@ -42,18 +54,40 @@ func (l *Listener) amountLessThanExpected(u models.UserFromBotRequest) error {
 	return l.nsq.Publish(AppType.TopicBotMessages, payload)
 }
 
-func (l *Listener) amountMoreThanExpected(u models.UserFromBotRequest) error {
-	// Подготовка уведомления
-	payload, err := json.Marshal(prepareNotification(
-		u,
-		"❗️Отмена операции обмена❗️\n\nПереведенная сумма больше ожидаемой. Не переживайте, наши менеджеры скоро свяжутся с вами.",
-	))
+// Метод уведомляет всех менеджеров о заявке, полученная
+// сумма по которой вышла за пределы допустимого отклонения
+func (l *Listener) amountMismatchToManagers(rRequest *models.ExchangeRequest, diff AppMoney.Money) error {
+	uArr, err := l.store.User().GetAllManagers()
 	if err != nil {
 		return err
 	}
 
-	// Отправка уведомления
-	return l.nsq.Publish(AppType.TopicBotMessages, payload)
+	text := fmt.Sprintf("🟠 Заявка на проверке 🟠\n\n*Заявка*: %d\n*Пользователь*: @%s\n*Ожидалось*: %s %s\n*Получено*: %s %s\n*Разница*: %s %s",
+		rRequest.ID,
+		rRequest.CreatedBy.Username,
+		rRequest.ExpectedAmount.String(),
+		rRequest.ExchangeFrom,
+		rRequest.TransferredAmount.String(),
+		rRequest.ExchangeFrom,
+		diff.String(),
+		rRequest.ExchangeFrom,
+	)
+
+	for _, u := range uArr {
+		payload, err := json.Marshal(prepareNotification(models.UserFromBotRequest{
+			ChatID:   u.ChatID,
+			Username: u.Username,
+		}, text))
+		if err != nil {
+			return err
+		}
+
+		if err := l.nsq.Publish(AppType.TopicBotMessages, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func prepareNotification(u models.UserFromBotRequest, text string) map[string]interface{} {
@@ -0,0 +1,255 @@
+package listener
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+)
+
+const (
+	// Максимальное кол-во операций в одном запросе истории Whitebit
+	defaultHistoryPageLimit = 100
+	defaultHistoryMaxPages  = 10
+)
+
+// Новые операции аккаунта и позиция, которую нужно
+// сохранить после их обработки
+type accountHistory struct {
+	records []*interfaces.HistoryRecord
+	cursor  *models.HistoryCursor
+}
+
+// Метод возвращает сохраненную позицию слушателя в истории
+// аккаунта account или пустую позицию для нового аккаунта
+func (listener *Listener) cursor(account *models.ListeningAccount) (*models.HistoryCursor, error) {
+	cursor := &models.HistoryCursor{MaID: account.ID}
+	if err := listener.store.AdminPanel().HistoryCursor().Get(cursor); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		cursor.Seen, cursor.Pending = []string{}, map[string]int64{}
+	}
+
+	return cursor, nil
+}
+
+// Метод возвращает операции аккаунта account, которые получили
+// окончательный статус после предыдущего опроса, от старых к новым.
+// История читается страницами начиная с самых новых операций, пока
+// не будет достигнута позиция слушателя cursor и самая старая
+// из незавершенных операций. Непрочитанный участок истории
+// дочитывается начиная с сохраненного смещения.
+func (listener *Listener) checker(ctx context.Context, cfg *config.ListenerConfig, account *models.ListeningAccount, cursor *models.HistoryCursor) (*accountHistory, error) {
+	plugin, err := listener.plugin.Get(account.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := cursor.CreatedAt
+	for _, createdAt := range cursor.Pending {
+		if createdAt < boundary {
+			boundary = createdAt
+		}
+	}
+
+	limit, maxPages := cfg.HistoryPageLimit, cfg.HistoryMaxPages
+	if limit < 1 || limit > defaultHistoryPageLimit {
+		limit = defaultHistoryPageLimit
+	}
+	if maxPages < 1 {
+		maxPages = defaultHistoryMaxPages
+	}
+
+	offset := 0
+	if cursor.Ceiling > 0 {
+		offset = cursor.Offset
+	}
+
+	// Операции от новых к старым, новые операции на бирже сдвигают
+	// страницы, поэтому одна операция может попасть в две страницы
+	arr := []*interfaces.HistoryRecord{}
+	fetched := map[string]bool{}
+	reached := false
+	for page := 0; page < maxPages && !reached; page++ {
+		history, err := plugin.History(ctx, account.Params, &interfaces.HistoryQuery{
+			Limit:  limit,
+			Offset: offset + page*limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range history.Records {
+			if k := historyKey(r); !fetched[k] {
+				fetched[k] = true
+				arr = append(arr, r)
+			}
+		}
+
+		reached = len(history.Records) < limit ||
+			history.Records[len(history.Records)-1].CreatedAt < boundary
+	}
+
+	h := nextHistory(cursor, arr, reached)
+	if !reached {
+		listener.logger.NewRecord(&models.LogRecord{
+			Service: AppType.LogTypeServer,
+			Module:  AppType.LogModuleListener,
+			Info:    fmt.Sprintf("history of account %d: saved position not reached in %d pages, continue from offset %d", account.ID, maxPages, h.cursor.Offset),
+		})
+	}
+
+	return h, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Функция отбирает из операций arr, упорядоченных от новых к старым,
+// еще не обработанные операции с окончательным статусом и рассчитывает
+// следующую позицию слушателя. reached - история прочитана до позиции
+// cursor, иначе операции со временем самой старой прочитанной и старше
+// остаются непрочитанным участком и дочитываются на следующих опросах.
+func nextHistory(cursor *models.HistoryCursor, arr []*interfaces.HistoryRecord, reached bool) *accountHistory {
+	seen := make(map[string]bool, len(cursor.Seen))
+	for _, k := range cursor.Seen {
+		seen[k] = true
+	}
+
+	// Дочитывание участка: операции новее cursor.Ceiling уже обработаны
+	gap := cursor.Ceiling > 0
+
+	// Операции не старше ceiling в этом опросе не обрабатываются,
+	// прочитанные из них могут быть неполными по времени
+	var ceiling int64
+	if !reached && len(arr) > 0 {
+		ceiling = arr[len(arr)-1].CreatedAt
+	}
+
+	head := cursor.CreatedAt
+	if gap {
+		head = cursor.Head
+	}
+
+	next := &models.HistoryCursor{
+		MaID:      cursor.MaID,
+		CreatedAt: cursor.CreatedAt,
+		Pending:   map[string]int64{},
+	}
+
+	// Операции этого опроса, которые не нужно читать повторно
+	read := []*interfaces.HistoryRecord{}
+	records := []*interfaces.HistoryRecord{}
+	for _, r := range arr {
+		if ceiling > 0 && r.CreatedAt <= ceiling {
+			continue
+		}
+
+		read = append(read, r)
+		if gap && r.CreatedAt > cursor.Ceiling {
+			continue
+		}
+
+		k := historyKey(r)
+		if r.Status == interfaces.HistoryStatusPending {
+			next.Pending[k] = r.CreatedAt
+			continue
+		}
+
+		if _, ok := cursor.Pending[k]; ok || r.CreatedAt > cursor.CreatedAt || (r.CreatedAt == cursor.CreatedAt && !seen[k]) {
+			records = append(records, r)
+		}
+
+		if r.CreatedAt > head {
+			head = r.CreatedAt
+		}
+	}
+
+	for k, createdAt := range cursor.Pending {
+		if _, ok := next.Pending[k]; !ok && ((ceiling > 0 && createdAt <= ceiling) || (gap && createdAt > cursor.Ceiling)) {
+			next.Pending[k] = createdAt
+		}
+	}
+
+	switch {
+	case ceiling > 0:
+		next.Ceiling, next.Head = ceiling, head
+		next.Offset = len(read)
+		if gap {
+			next.Offset += cursor.Offset
+		}
+	default:
+		next.CreatedAt = head
+	}
+
+	// Ключи операций на границах позиции, на следующем опросе
+	// операции с тем же временем будут пропущены только по ключу
+	next.Seen = []string{}
+	if next.CreatedAt == cursor.CreatedAt || gap {
+		next.Seen = append(next.Seen, cursor.Seen...)
+	}
+	for _, r := range read {
+		if gap && r.CreatedAt > cursor.Ceiling {
+			continue
+		}
+
+		onBoundary := r.CreatedAt == next.CreatedAt || (next.Ceiling > 0 && r.CreatedAt == next.Head)
+		if r.Status != interfaces.HistoryStatusPending && onBoundary && !seen[historyKey(r)] {
+			next.Seen = append(next.Seen, historyKey(r))
+		}
+	}
+
+	// Обработка в порядке совершения операций
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	return &accountHistory{records: records, cursor: next}
+}
+
+// Метод оставляет операцию r незавершенной в следующей позиции,
+// чтобы она была обработана повторно на следующем опросе
+func (h *accountHistory) retry(r *interfaces.HistoryRecord) {
+	k := historyKey(r)
+	h.cursor.Pending[k] = r.CreatedAt
+
+	seen := h.cursor.Seen[:0]
+	for _, v := range h.cursor.Seen {
+		if v != k {
+			seen = append(seen, v)
+		}
+	}
+	h.cursor.Seen = seen
+}
+
+// Ключ операции в истории. Хеш вывода появляется только после его
+// отправки, поэтому сначала используется идентификатор операции.
+func historyKey(r *interfaces.HistoryRecord) string {
+	switch {
+	case r.UniqueID != "":
+		return fmt.Sprintf("%d:%s", r.Method, r.UniqueID)
+	case r.TransactionHash != "":
+		return fmt.Sprintf("%d:%s", r.Method, r.TransactionHash)
+	}
+
+	return fmt.Sprintf("%d:%s:%s:%d", r.Method, r.Address, r.Amount.String(), r.CreatedAt)
+}
+
+// Метод записывает в лог ошибку опроса истории аккаунта account
+func (listener *Listener) historyError(account *models.ListeningAccount, err error) {
+	listener.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  AppType.LogModuleListener,
+		Info:    fmt.Sprintf("history of account %d: %s", account.ID, err.Error()),
+	})
+}
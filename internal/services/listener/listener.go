@@ -2,218 +2,326 @@ package listener
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/supervisor"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"golang.org/x/sync/errgroup"
 )
 
 type Listener struct {
-	store  db.SQLStoreI
-	nsq    nsqstore.NsqI
-	plugin *plugins.AppPlugins
-	logger utils.LoggerI
+	store    db.SQLStoreI
+	nsq      nsqstore.NsqI
+	plugin   *plugins.AppPlugins
+	selector accounts.SelectorI
+	logger   utils.LoggerI
+
+	supervisor supervisor.SupervisorI
+
+	mu             sync.RWMutex
+	accountsPolled int
+	failedAccounts []*models.FailedAccount
 }
 
 type ListenerI interface {
 	Listen(ctx context.Context, cfg *config.ListenerConfig) error
+	Supervise(ctx context.Context, cfg *config.ListenerConfig)
+	Status() *models.ListenerStatus
 }
 
-func InitListener(s db.SQLStoreI, q nsqstore.NsqI, p *plugins.AppPlugins, l utils.LoggerI) ListenerI {
+func InitListener(s db.SQLStoreI, q nsqstore.NsqI, p *plugins.AppPlugins, sel accounts.SelectorI, l utils.LoggerI) ListenerI {
 	return &Listener{
-		store:  s,
-		nsq:    q,
-		plugin: p,
-		logger: l,
+		store:    s,
+		nsq:      q,
+		plugin:   p,
+		selector: sel,
+		logger:   l,
 	}
 }
 
+// Метод запускает слушатель под наблюдением супервизора.
+// После любой ошибки слушатель перезапускается с экспоненциальной
+// задержкой. Метод блокирует выполнение до отмены контекста ctx.
+func (listener *Listener) Supervise(ctx context.Context, cfg *config.ListenerConfig) {
+	listener.mu.Lock()
+	listener.supervisor = supervisor.Init(
+		AppType.LogModuleListener,
+		time.Duration(cfg.RestartDelay)*time.Second,
+		time.Duration(cfg.MaxRestartDelay)*time.Second,
+		listener.logger,
+	)
+	listener.mu.Unlock()
+
+	listener.supervisor.Run(ctx, func(ctx context.Context) error {
+		return listener.Listen(ctx, cfg)
+	})
+}
+
+// Метод возвращает текущее состояние слушателя
+func (listener *Listener) Status() *models.ListenerStatus {
+	listener.mu.RLock()
+	defer listener.mu.RUnlock()
+
+	s := &models.ListenerStatus{
+		WorkerState: models.WorkerState{
+			Name: AppType.LogModuleListener,
+		},
+		AccountsPolled: listener.accountsPolled,
+		FailedAccounts: append([]*models.FailedAccount{}, listener.failedAccounts...),
+	}
+
+	if listener.supervisor != nil {
+		s.WorkerState = listener.supervisor.State()
+	}
+
+	return s
+}
+
 func (listener *Listener) Listen(ctx context.Context, cfg *config.ListenerConfig) error {
 	for {
 		t := time.NewTimer(time.Duration(cfg.Interval) * time.Second)
 
-		// Получение актуального списка аккаунтов
-		state, err := listener.snapshot(ctx, cfg)
-		if err != nil {
+		if err := listener.tick(ctx, cfg); err != nil {
+			t.Stop()
 			return err
 		}
 
-		// Канал для массива историй транзакций со всех аккаунтов на Whitebit
-		cWhitebitHistoryArr := make(chan []*models.WhitebitHistory)
-		// Канал для массива всех заявок сохраненных в БД
-		cExchangeRequestsArr := make(chan []*models.ExchangeRequest)
-
-		// Массив транзакций со всех аккаунтов на Whitebit
-		var whitebitHistoryArr []*models.WhitebitHistory
-		// Массив всех заявок из БД
-		var exchangeRequestsArr []*models.ExchangeRequest
-
-		{
-			errs, _ := errgroup.WithContext(ctx)
-
-			// Получение списка новых заявок и заявок по которым
-			// должны отработать автовыплаты
-			errs.Go(func() error {
-				defer close(cExchangeRequestsArr)
-
-				arr, err := listener.store.AdminPanel().ExchangeRequest().GetAllByStatus(
-					AppType.ExchangeRequestNew,                  // новые заявки
-					AppType.ExchangeRequestPaid,                 // заявки по которым должны отработать автовыплаты
-					AppType.ExchangeRequestAwaitingConfirmation, // заявки по которым отработали автовыплаты, ожидают подтверждения от биржи
-				)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Одна итерация работы слушателя
+func (listener *Listener) tick(ctx context.Context, cfg *config.ListenerConfig) error {
+	// Получение актуального списка аккаунтов
+	state, err := listener.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Депозиты приходят на аккаунты мерчантов, а
+	// подтверждения выводов - на аккаунты автовыплат
+	whitebitAccounts := append(
+		state.Merchants(AppType.MerchantAutoPayoutWhitebit),
+		state.Autopayouts(AppType.MerchantAutoPayoutWhitebit)...,
+	)
+
+	listener.mu.Lock()
+	listener.accountsPolled = len(whitebitAccounts)
+	listener.failedAccounts = state.Failed()
+	listener.mu.Unlock()
+
+	// Канал для новых операций со всех аккаунтов на Whitebit
+	cWhitebitHistoryArr := make(chan []*accountHistory)
+	// Канал для массива всех заявок сохраненных в БД
+	cExchangeRequestsArr := make(chan []*models.ExchangeRequest)
+
+	// Новые операции со всех аккаунтов на Whitebit
+	var whitebitHistoryArr []*accountHistory
+	// Массив всех заявок из БД
+	var exchangeRequestsArr []*models.ExchangeRequest
+
+	{
+		errs, _ := errgroup.WithContext(ctx)
+
+		// Получение списка новых заявок и заявок по которым
+		// должны отработать автовыплаты
+		errs.Go(func() error {
+			defer close(cExchangeRequestsArr)
+
+			arr, err := listener.store.AdminPanel().ExchangeRequest().GetAllByStatus(
+				AppType.ExchangeRequestNew,                  // новые заявки
+				AppType.ExchangeRequestPaid,                 // заявки по которым должны отработать автовыплаты
+				AppType.ExchangeRequestAwaitingConfirmation, // заявки по которым отработали автовыплаты, ожидают подтверждения от биржи
+			)
+			if err != nil {
+				return err
+			}
+
+			cExchangeRequestsArr <- arr
+			return nil
+		})
+
+		// Получаю новые операции всех whitebit аккаутов. Аккаунты
+		// опрашиваются параллельно, ошибка одного аккаунта не мешает
+		// обработке операций остальных
+		errs.Go(func() error {
+			defer close(cWhitebitHistoryArr)
+			arr := make([]*accountHistory, len(whitebitAccounts))
+
+			var wg sync.WaitGroup
+			for i, account := range whitebitAccounts {
+				cursor, err := listener.cursor(account)
 				if err != nil {
-					return err
+					listener.historyError(account, err)
+					continue
 				}
 
-				cExchangeRequestsArr <- arr
-				return nil
-			})
-
-			// Получаю истории транзакций всех whitebit аккаутов
-			errs.Go(func() error {
-				defer close(cWhitebitHistoryArr)
-				arr := []*models.WhitebitHistory{}
+				wg.Add(1)
+				go func(i int, account *models.ListeningAccount, cursor *models.HistoryCursor) {
+					defer wg.Done()
 
-				for _, merchant := range state.Merchants.Whitebit {
-					history, err := listener.checker(merchant)
+					history, err := listener.checker(ctx, cfg, account, cursor)
 					if err != nil {
-						return err
+						listener.historyError(account, err)
+						return
 					}
 
-					arr = append(arr, history)
-				}
+					arr[i] = history
+				}(i, account, cursor)
+			}
+			wg.Wait()
 
-				cWhitebitHistoryArr <- arr
-				return nil
-			})
+			cWhitebitHistoryArr <- arr
+			return nil
+		})
 
-			whitebitHistoryArr = <-cWhitebitHistoryArr
-			exchangeRequestsArr = <-cExchangeRequestsArr
+		whitebitHistoryArr = <-cWhitebitHistoryArr
+		exchangeRequestsArr = <-cExchangeRequestsArr
 
-			if errs.Wait() != nil {
-				fmt.Println(errs.Wait())
-			}
+		if errs.Wait() != nil {
+			fmt.Println(errs.Wait())
 		}
+	}
 
-		// Анализ истории транзакций всех аккаунтов
-		{
-			errs, _ := errgroup.WithContext(ctx)
-
-			// Анализ истории всех транзакций со всех аккаунтов на whitebit
-			errs.Go(func() error {
-
-				// Массив заявок по которым должны отработать автовыплаты
-				var forAutopayout []*models.ExchangeRequest
-
-				// rHistory -> Запись из истории транзакций
-				// rRequest -> Запись в таблице заявок
-				for _, account := range whitebitHistoryArr {
-					for _, rHistory := range account.Records {
-						for _, rRequest := range exchangeRequestsArr {
-							// Если заявка ожидает автовыплаты
-							if rRequest.Status == AppType.ExchangeRequestPaid {
-								if len(forAutopayout) > 0 {
-									for _, alreadyAdd := range forAutopayout {
-										if alreadyAdd.ID != rRequest.ID {
-											forAutopayout = append(forAutopayout, rRequest)
-										}
-									}
-								} else {
-									forAutopayout = append(forAutopayout, rRequest)
-								}
-								continue
-							}
-
-							switch rHistory.Method {
-							case 1: // Событие получения средств
-								listener.handleWhitebitDepositAction(rHistory, rRequest)
-								continue
-							case 2: // Событие вывода средств
-								if rHistory.Status == 3 || rHistory.Status == 7 {
-									listener.handleWhitebitWithdrawAction(rHistory, rRequest)
-									continue
-								}
-							default:
-								continue
-							}
-						}
-					}
+	// Анализ истории транзакций всех аккаунтов
+	{
+		errs, _ := errgroup.WithContext(ctx)
+
+		// Анализ новых операций со всех аккаунтов на whitebit
+		errs.Go(func() error {
+			listener.process(whitebitHistoryArr, exchangeRequestsArr)
+
+			// Работа автовыплаты, каждая заявка обрабатывается ровно один раз
+			for _, rRequest := range exchangeRequestsArr {
+				if rRequest.Status != AppType.ExchangeRequestPaid {
+					continue
 				}
 
-				time.Sleep(time.Duration(1 * time.Second))
-
-				// Работа автовыплаты
-				for _, rRequest := range forAutopayout {
-					for _, account := range state.Merchants.Whitebit {
-						b, err := listener.plugin.Whitebit.AutoPayout().Payout(account, map[string]interface{}{
-							"ticker":   rRequest.ExchangeTo,
-							"amount":   fmt.Sprintf("%f", rRequest.ExpectedAmount),
-							"address":  rRequest.ClientAddress,
-							"uniqueId": strconv.Itoa(rRequest.ID),
-							"network":  "TRC20",
-						})
-						if err != nil {
-							fmt.Println(err)
-						}
+				if err := listener.payout(ctx, rRequest); err != nil {
+					listener.logger.NewRecord(&models.LogRecord{
+						Service: AppType.LogTypeServer,
+						Module:  AppType.LogModuleListener,
+						Info:    err.Error(),
+					})
+				}
+			}
 
-						var body interface{}
-						if err := json.Unmarshal(b.([]byte), &body); err != nil {
-							fmt.Println(err)
-							break
-						}
+			return nil
+		})
 
-						// Если получили ошибку и деньги не отправились
-						if reflect.TypeOf(body) == reflect.TypeOf(map[string]interface{}{}) {
-							resp := body.(map[string]interface{})
-							utils.SetSuccessStep(AppType.SprintfStep("Payout done with status %v", resp["code"]))
-							fmt.Println(resp["errors"])
-							continue
-						}
+		if errs.Wait() != nil {
+			fmt.Println(errs.Wait())
+		}
+	}
 
-						// Если деньги ушли
-						rRequest.Status = AppType.ExchangeRequestAwaitingConfirmation
-						if err := listener.store.AdminPanel().ExchangeRequest().Update(rRequest); err != nil {
-							fmt.Println(err)
-						}
+	listener.mu.RLock()
+	if listener.supervisor != nil {
+		listener.supervisor.Tick()
+	}
+	listener.mu.RUnlock()
+
+	return nil
+}
+
+// Метод сопоставляет новые операции аккаунтов с заявками и сохраняет
+// позиции слушателя. Операция, обработка которой завершилась ошибкой,
+// остается незавершенной в позиции и обрабатывается повторно
+// на следующей итерации.
+func (listener *Listener) process(history []*accountHistory, requests []*models.ExchangeRequest) {
+	// Заявки ожидающие депозита по аккаунту и адресу для принятия средств
+	// и заявки ожидающие подтверждения вывода по ключу идемпотентности
+	deposits := map[string][]*models.ExchangeRequest{}
+	withdrawals := map[string][]*models.ExchangeRequest{}
+	for _, rRequest := range requests {
+		switch rRequest.Status {
+		case AppType.ExchangeRequestNew:
+			k := depositKey(rRequest.MaID, rRequest.Address, rRequest.Memo)
+			deposits[k] = append(deposits[k], rRequest)
+		case AppType.ExchangeRequestAwaitingConfirmation:
+			k := strconv.Itoa(rRequest.ID)
+			withdrawals[k] = append(withdrawals[k], rRequest)
+		}
+	}
+
+	// rHistory -> Запись из истории транзакций
+	// rRequest -> Запись в таблице заявок
+	for _, account := range history {
+		if account == nil {
+			continue
+		}
 
+		for _, rHistory := range account.records {
+			var err error
+			switch rHistory.Method {
+			case interfaces.HistoryDeposit: // Событие получения средств
+				for _, k := range []string{
+					depositKey(&account.cursor.MaID, rHistory.Address, rHistory.Memo),
+					depositKey(nil, rHistory.Address, rHistory.Memo),
+				} {
+					for _, rRequest := range deposits[k] {
+						if hErr := listener.handleDepositAction(account.cursor.MaID, rHistory, rRequest); hErr != nil {
+							err = hErr
+						}
 					}
 				}
+			case interfaces.HistoryWithdraw: // Событие вывода средств
+				if rHistory.Status == interfaces.HistoryStatusSuccess {
+					for _, rRequest := range withdrawals[rHistory.UniqueID] {
+						if hErr := listener.handleWithdrawAction(rHistory, rRequest); hErr != nil {
+							err = hErr
+						}
+					}
+				}
+			}
 
-				return nil
-			})
-
-			if errs.Wait() != nil {
-				fmt.Println(errs.Wait())
+			if err != nil {
+				listener.cursorError(account.cursor, err)
+				account.retry(rHistory)
 			}
 		}
 
-		<-t.C
+		// Операции аккаунта обработаны, следующий опрос
+		// начнется с новой позиции
+		if err := listener.store.AdminPanel().HistoryCursor().Save(account.cursor); err != nil {
+			listener.cursorError(account.cursor, err)
+		}
 	}
 }
 
-func (listener *Listener) checker(p *models.WhitebitOptionParams) (*models.WhitebitHistory, error) {
-	time.Sleep(time.Duration(1 * time.Second))
-
-	b, err := listener.plugin.Whitebit.History(p, AppType.BaseWhitebitGetHistoryBody)
-	if err != nil {
-		// TODO: Писать лог что не удалось установить соединение с этим аккаунтом
-		return nil, err
+// Ключ заявки, ожидающей депозита на адрес address с memo аккаунта
+// maID. Для валют с memo один адрес выдается многим заявкам, заявки
+// различаются только memo. Заявки без аккаунта (созданные до привязки
+// или после удаления аккаунта) ожидают депозита на этот адрес
+// любого аккаунта.
+func depositKey(maID *int, address, memo string) string {
+	if maID == nil {
+		return fmt.Sprintf("*:%s:%s", address, memo)
 	}
 
-	var history models.WhitebitHistory
-	if err := json.Unmarshal(b.([]byte), &history); err != nil {
-		return nil, err
-	}
+	return fmt.Sprintf("%d:%s:%s", *maID, address, memo)
+}
 
-	return &history, nil
+// Метод записывает в лог ошибку обработки истории аккаунта позиции cursor
+func (listener *Listener) cursorError(cursor *models.HistoryCursor, err error) {
+	listener.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  AppType.LogModuleListener,
+		Info:    fmt.Sprintf("history of account %d: %s", cursor.MaID, err.Error()),
+	})
 }
@@ -0,0 +1,986 @@
+package listener
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+
+	// Подключение плагинов мерчантов/автовыплат
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+)
+
+/*
+	Полный цикл заявки на имитации биржи Whitebit:
+	депозит -> оплачена -> автовыплата -> выполнена
+*/
+func Test_Listener_DepositPayoutDone(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1000")
+
+	store := mocksqlstore.Init()
+	nsq := &testNsq{}
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, nsq, appPlugins, cfg)
+
+	mParams := wb.Account("merchant-public", "merchant-secret")
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, mParams)
+	autopayout := testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	// Адрес для принятия средств по заявке
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	addr, err := plugin.Merchant().CreateAddress(ctx, mParams, &interfaces.AddressRequest{Currency: testCurrency(t, store, "USDTTRC20")})
+	assert.NoError(t, err)
+
+	er := &models.ExchangeRequest{
+		Status:         AppType.ExchangeRequestNew,
+		ExchangeFrom:   "USDTTRC20",
+		ExchangeTo:     "USDT",
+		Course:         "1",
+		Address:        addr.Address,
+		ClientAddress:  "TClientAddress",
+		ExpectedAmount: AppMoney.NewFromInt(100),
+		MaID:           &merchant.ID,
+		CreatedBy: models.UserFromBotRequest{
+			ChatID:   1,
+			Username: "client",
+		},
+	}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+
+	// Депозит еще не подтвержден биржей
+	hash := wb.Deposit(addr.Address, "USDT", "100", whitebittest.StatusPending)
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestNew, testRequestStatus(t, store, er.ID))
+
+	// Депозит подтвержден, по заявке отправляется автовыплата
+	assert.NoError(t, wb.SetStatus(hash, whitebittest.StatusSuccess))
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, er.ID))
+
+	withdrawals := wb.Withdrawals()
+	assert.Len(t, withdrawals, 1)
+	assert.Equal(t, "TClientAddress", withdrawals[0].Address)
+	assert.Equal(t, "100", withdrawals[0].Amount)
+	assert.Equal(t, AppType.CurrencyNetworkTRC20, withdrawals[0].Network)
+	assert.Equal(t, strconv.Itoa(er.ID), withdrawals[0].UniqueId)
+	assert.Equal(t, "1000", wb.Balance("USDT"))
+
+	payouts, err := store.AdminPanel().Payout().Selection(&models.PayoutSelection{RequestID: er.ID})
+	assert.NoError(t, err)
+	assert.Len(t, payouts, 1)
+	assert.Equal(t, autopayout.ID, payouts[0].MaID)
+	assert.Equal(t, AppType.PayoutSent, payouts[0].Status)
+	assert.Equal(t, "USDT", payouts[0].Ticker)
+	assert.Equal(t, AppType.CurrencyNetworkTRC20, payouts[0].Network)
+
+	// Вывод еще в обработке, повторной выплаты нет
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, er.ID))
+	assert.Len(t, wb.Withdrawals(), 1)
+	assert.Empty(t, nsq.messages())
+
+	// Биржа подтвердила вывод
+	assert.NoError(t, wb.SetStatus(strconv.Itoa(er.ID), whitebittest.StatusSuccess))
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestDone, testRequestStatus(t, store, er.ID))
+
+	// Пользователь получил чек операции
+	messages := nsq.messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, AppType.TopicBotMessages, messages[0].topic)
+	assert.Contains(t, string(messages[0].payload), wb.Withdrawals()[0].TransactionHash)
+
+	// Каждая смена статуса записана в историю
+	history, err := store.AdminPanel().RequestStatusHistory().GetAllByRequest(er.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	expected := []AppType.ExchangeRequestStatus{
+		AppType.ExchangeRequestPaid,
+		AppType.ExchangeRequestAwaitingConfirmation,
+		AppType.ExchangeRequestDone,
+	}
+	for i, h := range history {
+		assert.Equal(t, expected[i], h.StatusTo)
+		assert.Equal(t, AppType.ExchangeRequestActorListener, h.Actor)
+		assert.NotEmpty(t, h.Reason)
+	}
+}
+
+/*
+	Расхождение полученной суммы с ожидаемой: в пределах допустимого
+	отклонения направления заявка оплачивается, иначе уходит на проверку
+*/
+func Test_Listener_DepositTolerance(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1000")
+
+	store := mocksqlstore.Init()
+	nsq := &testNsq{}
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, nsq, appPlugins, cfg)
+
+	mParams := wb.Account("merchant-public", "merchant-secret")
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, mParams)
+	testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	hash := "hash"
+	assert.NoError(t, store.User().Create(&models.User{ChatID: 2, Username: "manager", Hash: &hash}))
+
+	assert.NoError(t, store.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom:  "USDTTRC20",
+		ExchangeTo:    "USDT",
+		ToleranceType: AppType.TolerancePercent,
+		Tolerance:     AppMoney.NewFromInt(1),
+	}))
+
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		transferred string
+		expected    AppType.ExchangeRequestStatus
+	}{
+		{
+			name:        "within tolerance",
+			transferred: "99.5",
+			expected:    AppType.ExchangeRequestAwaitingConfirmation,
+		},
+		{
+			name:        "outside tolerance",
+			transferred: "90",
+			expected:    AppType.ExchangeRequestChecked,
+		},
+	}
+
+	ids := []int{}
+	for _, tc := range testCases {
+		addr, err := plugin.Merchant().CreateAddress(ctx, mParams, &interfaces.AddressRequest{Currency: testCurrency(t, store, "USDTTRC20")})
+		assert.NoError(t, err)
+
+		er := &models.ExchangeRequest{
+			Status:         AppType.ExchangeRequestNew,
+			ExchangeFrom:   "USDTTRC20",
+			ExchangeTo:     "USDT",
+			Course:         "1",
+			Address:        addr.Address,
+			ClientAddress:  "TClientAddress",
+			ExpectedAmount: AppMoney.NewFromInt(100),
+			MaID:           &merchant.ID,
+			CreatedBy: models.UserFromBotRequest{
+				ChatID:   1,
+				Username: "client",
+			},
+		}
+		assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+		ids = append(ids, er.ID)
+
+		wb.Deposit(addr.Address, "USDT", tc.transferred, whitebittest.StatusSuccess)
+	}
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, testRequestStatus(t, store, ids[i]))
+		})
+	}
+
+	// Выплата рассчитана от фактически полученной суммы
+	withdrawals := wb.Withdrawals()
+	assert.Len(t, withdrawals, 1)
+	assert.Equal(t, "99.5", withdrawals[0].Amount)
+
+	// Клиент и менеджер получили уведомления о проверке заявки
+	messages := nsq.messages()
+	assert.Len(t, messages, 2)
+	assert.Contains(t, string(messages[1].payload), "manager")
+	assert.Contains(t, string(messages[1].payload), "-10")
+}
+
+/*
+	Депозит засчитывается заявке только на аккаунте, который выдал
+	ей адрес. Заявка без аккаунта принимает депозит с любого аккаунта
+*/
+func Test_Listener_DepositAccount(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	other := whitebittest.NewServer()
+	defer other.Close()
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, wb.Account("merchant-public", "merchant-secret"))
+	testAccount(t, store, cfg, AppType.UseAsMerchant, other.Account("other-public", "other-secret"))
+
+	newRequest := func(address string, maID *int) *models.ExchangeRequest {
+		er := &models.ExchangeRequest{
+			Status:         AppType.ExchangeRequestNew,
+			ExchangeFrom:   "USDTTRC20",
+			ExchangeTo:     "USDT",
+			Course:         "1",
+			Address:        address,
+			ClientAddress:  "TClientAddress",
+			ExpectedAmount: AppMoney.NewFromInt(100),
+			MaID:           maID,
+		}
+		assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+		return er
+	}
+
+	bound := newRequest("TSharedAddress", &merchant.ID)
+	legacy := newRequest("TLegacyAddress", nil)
+
+	// Депозит на тот же адрес другого аккаунта
+	other.Deposit("TSharedAddress", "USDT", "100", whitebittest.StatusSuccess)
+	other.Deposit("TLegacyAddress", "USDT", "100", whitebittest.StatusSuccess)
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestNew, testRequestStatus(t, store, bound.ID))
+	assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, legacy.ID))
+
+	// Депозит на аккаунт заявки
+	wb.Deposit("TSharedAddress", "USDT", "100", whitebittest.StatusSuccess)
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, bound.ID))
+}
+
+/*
+	Адрес просроченной заявки выдан новой заявке. Запоздавший платеж
+	по прежней заявке, сделанный до создания новой, ей не засчитывается
+*/
+func Test_Listener_DepositBeforeRequest(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, wb.Account("merchant-public", "merchant-secret"))
+
+	wb.Deposit("TReusedAddress", "USDT", "100", whitebittest.StatusSuccess)
+
+	er := &models.ExchangeRequest{
+		Status:         AppType.ExchangeRequestNew,
+		ExchangeFrom:   "USDTTRC20",
+		ExchangeTo:     "USDT",
+		Course:         "1",
+		Address:        "TReusedAddress",
+		ClientAddress:  "TClientAddress",
+		ExpectedAmount: AppMoney.NewFromInt(100),
+		MaID:           &merchant.ID,
+		CreatedAt:      time.Now().UTC().Add(time.Hour).Format(core.DateStandart),
+	}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestNew, testRequestStatus(t, store, er.ID))
+}
+
+/*
+	Для валют с memo адрес один на все заявки. Депозит засчитывается
+	только заявке с memo депозита, выплата отправляется с memo клиента
+*/
+func Test_Listener_DepositMemo(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.RequireMemo("XRP")
+	wb.SetBalance("XRP", "1000")
+
+	store := mocksqlstore.Init()
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, &testNsq{}, appPlugins, cfg)
+
+	xrp := &models.Currency{Code: "XRP", Ticker: "XRP", Decimals: 6, MemoRequired: true}
+	assert.NoError(t, store.AdminPanel().Currency().Create(xrp))
+	xrp.RegisterPrecision()
+
+	mParams := wb.Account("merchant-public", "merchant-secret")
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, mParams)
+	testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	arr := []*models.ExchangeRequest{}
+	for i := 0; i < 2; i++ {
+		addr, err := plugin.Merchant().CreateAddress(ctx, mParams, &interfaces.AddressRequest{Currency: xrp.Plugin()})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, addr.Memo)
+
+		er := &models.ExchangeRequest{
+			Status:         AppType.ExchangeRequestNew,
+			ExchangeFrom:   "XRP",
+			ExchangeTo:     "XRP",
+			Course:         "1",
+			Address:        addr.Address,
+			Memo:           addr.Memo,
+			ClientAddress:  "rClientAddress",
+			ClientMemo:     "client-memo",
+			ExpectedAmount: AppMoney.NewFromInt(100),
+			MaID:           &merchant.ID,
+		}
+		assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+		arr = append(arr, er)
+	}
+	assert.Equal(t, arr[0].Address, arr[1].Address)
+
+	wb.DepositMemo(arr[1].Address, arr[1].Memo, "XRP", "100", whitebittest.StatusSuccess)
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestNew, testRequestStatus(t, store, arr[0].ID))
+	assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, arr[1].ID))
+
+	withdrawals := wb.Withdrawals()
+	assert.Len(t, withdrawals, 1)
+	assert.Equal(t, "client-memo", withdrawals[0].Memo)
+}
+
+/*
+	История аккаунта читается постранично до сохраненной позиции,
+	каждая операция обрабатывается ровно один раз
+*/
+func Test_Listener_HistoryCursor(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+	cfg.Listener.HistoryPageLimit = 2
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, &testNsq{}, appPlugins, cfg)
+
+	mParams := wb.Account("merchant-public", "merchant-secret")
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, mParams)
+
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	addr, err := plugin.Merchant().CreateAddress(ctx, mParams, &interfaces.AddressRequest{Currency: testCurrency(t, store, "USDTTRC20")})
+	assert.NoError(t, err)
+
+	newRequest := func() *models.ExchangeRequest {
+		er := &models.ExchangeRequest{
+			Status:         AppType.ExchangeRequestNew,
+			ExchangeFrom:   "USDTTRC20",
+			ExchangeTo:     "USDT",
+			Course:         "1",
+			Address:        addr.Address,
+			ClientAddress:  "TClientAddress",
+			ExpectedAmount: AppMoney.NewFromInt(100),
+			MaID:           &merchant.ID,
+		}
+		assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+		return er
+	}
+
+	// Депозит по заявке оказался на последней странице истории
+	first := newRequest()
+	wb.Deposit(addr.Address, "USDT", "100", whitebittest.StatusSuccess)
+	for i := 0; i < 4; i++ {
+		wb.Deposit("TOtherAddress", "USDT", "1", whitebittest.StatusSuccess)
+	}
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, first.ID))
+	assert.Equal(t, 3, wb.Requests(whitebittest.PathHistory))
+
+	cursor := &models.HistoryCursor{MaID: merchant.ID}
+	assert.NoError(t, store.AdminPanel().HistoryCursor().Get(cursor))
+	assert.NotZero(t, cursor.CreatedAt)
+	assert.Len(t, cursor.Seen, 1)
+	assert.Empty(t, cursor.Pending)
+
+	// Уже обработанный депозит не засчитывается новой заявке
+	// на тот же адрес, история читается только до позиции
+	second := newRequest()
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestNew, testRequestStatus(t, store, second.ID))
+	assert.Equal(t, 4, wb.Requests(whitebittest.PathHistory))
+
+	wb.Deposit(addr.Address, "USDT", "100", whitebittest.StatusSuccess)
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, second.ID))
+
+	// По каждой заявке ровно одна смена статуса
+	for _, id := range []int{first.ID, second.ID} {
+		history, err := store.AdminPanel().RequestStatusHistory().GetAllByRequest(id)
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	}
+}
+
+/*
+	История, не прочитанная до позиции за один опрос, дочитывается
+	на следующих опросах с места остановки, операции из непрочитанного
+	участка и новые операции обрабатываются ровно один раз
+*/
+func Test_Listener_HistoryGap(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+	cfg.Listener.HistoryPageLimit = 2
+	cfg.Listener.HistoryMaxPages = 2
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, &testNsq{}, appPlugins, cfg)
+
+	mParams := wb.Account("merchant-public", "merchant-secret")
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, mParams)
+
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	newRequest := func() *models.ExchangeRequest {
+		addr, err := plugin.Merchant().CreateAddress(ctx, mParams, &interfaces.AddressRequest{Currency: testCurrency(t, store, "USDTTRC20")})
+		assert.NoError(t, err)
+
+		er := &models.ExchangeRequest{
+			Status:         AppType.ExchangeRequestNew,
+			ExchangeFrom:   "USDTTRC20",
+			ExchangeTo:     "USDT",
+			Course:         "1",
+			Address:        addr.Address,
+			ClientAddress:  "TClientAddress",
+			ExpectedAmount: AppMoney.NewFromInt(100),
+			MaID:           &merchant.ID,
+		}
+		assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+		return er
+	}
+
+	// Депозит по заявке глубже, чем читается за один опрос
+	first := newRequest()
+	wb.Deposit(first.Address, "USDT", "100", whitebittest.StatusSuccess)
+	for i := 0; i < 6; i++ {
+		wb.Deposit("TOtherAddress", "USDT", "1", whitebittest.StatusSuccess)
+	}
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestNew, testRequestStatus(t, store, first.ID))
+
+	cursor := &models.HistoryCursor{MaID: merchant.ID}
+	assert.NoError(t, store.AdminPanel().HistoryCursor().Get(cursor))
+	assert.Zero(t, cursor.CreatedAt)
+	assert.NotZero(t, cursor.Ceiling)
+	assert.Equal(t, 3, cursor.Offset)
+
+	// Новые операции во время дочитывания
+	second := newRequest()
+	wb.Deposit(second.Address, "USDT", "100", whitebittest.StatusSuccess)
+
+	for i := 0; i < 5 && cursor.Ceiling > 0; i++ {
+		assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+		assert.NoError(t, store.AdminPanel().HistoryCursor().Get(cursor))
+	}
+	assert.Zero(t, cursor.Ceiling)
+	assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, first.ID))
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, second.ID))
+
+	// По каждой заявке ровно одна смена статуса
+	for _, id := range []int{first.ID, second.ID} {
+		history, err := store.AdminPanel().RequestStatusHistory().GetAllByRequest(id)
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+	}
+}
+
+/*
+	Депозит, обработка которого завершилась ошибкой, остается
+	незавершенным в позиции и обрабатывается на следующей итерации
+*/
+func Test_Listener_HistoryRetry(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, &testNsq{}, appPlugins, cfg)
+
+	mParams := wb.Account("merchant-public", "merchant-secret")
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, mParams)
+
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	addr, err := plugin.Merchant().CreateAddress(ctx, mParams, &interfaces.AddressRequest{Currency: testCurrency(t, store, "USDTTRC20")})
+	assert.NoError(t, err)
+
+	er := &models.ExchangeRequest{
+		Status:         AppType.ExchangeRequestNew,
+		ExchangeFrom:   "USDTTRC20",
+		ExchangeTo:     "USDT",
+		Course:         "1",
+		Address:        addr.Address,
+		ClientAddress:  "TClientAddress",
+		ExpectedAmount: AppMoney.NewFromInt(100),
+		MaID:           &merchant.ID,
+	}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+	wb.Deposit(addr.Address, "USDT", "100", whitebittest.StatusSuccess)
+
+	requests, err := store.AdminPanel().ExchangeRequest().GetAllByStatus(AppType.ExchangeRequestNew)
+	assert.NoError(t, err)
+
+	// Заявка истекла после того, как слушатель получил список заявок
+	expired := &models.ExchangeRequest{ID: er.ID}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Get(expired))
+	h, err := expired.Transition(AppType.ExchangeRequestDeleted, AppType.ExchangeRequestActorSweeper, "expired")
+	assert.NoError(t, err)
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Transition(expired, h))
+
+	state, err := lsnr.snapshot(ctx)
+	assert.NoError(t, err)
+
+	account := state.Merchants(AppType.MerchantAutoPayoutWhitebit)[0]
+	cursor, err := lsnr.cursor(account)
+	assert.NoError(t, err)
+
+	history, err := lsnr.checker(ctx, &cfg.Listener, account, cursor)
+	assert.NoError(t, err)
+	assert.Len(t, history.records, 1)
+
+	lsnr.process([]*accountHistory{history}, requests)
+
+	// Депозит не потерян, позиция не сдвинута за него
+	saved := &models.HistoryCursor{MaID: merchant.ID}
+	assert.NoError(t, store.AdminPanel().HistoryCursor().Get(saved))
+	assert.Len(t, saved.Pending, 1)
+	assert.Empty(t, saved.Seen)
+
+	// Повторная обработка: заявок на адрес больше нет,
+	// операция обработана и удалена из позиции
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.NoError(t, store.AdminPanel().HistoryCursor().Get(saved))
+	assert.Empty(t, saved.Pending)
+	assert.Len(t, saved.Seen, 1)
+	assert.Equal(t, AppType.ExchangeRequestDeleted, testRequestStatus(t, store, er.ID))
+}
+
+/*
+	Мерчанты и автовыплаты собираются в отдельные наборы, недоступный
+	аккаунт попадает в список неудачных и не прерывает итерацию
+*/
+func Test_Listener_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, wb.Account("merchant-public", "merchant-secret"))
+	autopayout := testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	broken := &models.MerchantAutopayout{
+		Name:        "broken",
+		Service:     AppType.MerchantAutoPayoutWhitebit,
+		ServiceType: AppType.UseAsMerchant,
+		Options:     "broken",
+		Status:      true,
+	}
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Create(broken))
+
+	state, err := lsnr.snapshot(ctx)
+	assert.NoError(t, err)
+
+	merchants := state.Merchants(AppType.MerchantAutoPayoutWhitebit)
+	assert.Len(t, merchants, 1)
+	assert.Equal(t, merchant.ID, merchants[0].ID)
+	assert.Equal(t, merchant.Name, merchants[0].Name)
+
+	autopayouts := state.Autopayouts(AppType.MerchantAutoPayoutWhitebit)
+	assert.Len(t, autopayouts, 1)
+	assert.Equal(t, autopayout.ID, autopayouts[0].ID)
+
+	failed := state.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, broken.ID, failed[0].ID)
+	assert.NotEmpty(t, failed[0].Error)
+
+	// Изменения у вызывающей стороны не попадают в набор
+	merchants[0].ID = 0
+	assert.Equal(t, merchant.ID, state.Merchants(AppType.MerchantAutoPayoutWhitebit)[0].ID)
+	assert.Empty(t, state.Merchants(AppType.MerchantAutoPayoutMine))
+
+	// Итерация опрашивает доступные аккаунты
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+
+	status := lsnr.Status()
+	assert.Equal(t, 2, status.AccountsPolled)
+	assert.Len(t, status.FailedAccounts, 1)
+	assert.Equal(t, broken.ID, status.FailedAccounts[0].ID)
+}
+
+/*
+	Автовыплата выполняется через аккаунт, привязанный к направлению
+	заявки, а без привязок - через первый активный аккаунт автовыплат
+*/
+func Test_Listener_PayoutAccount(t *testing.T) {
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+
+	first := testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("first-public", "first-secret"))
+	bound := testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("bound-public", "bound-secret"))
+
+	d := &models.Direction{ExchangeFrom: "USDTTRC20", ExchangeTo: "USDT", Status: true}
+	assert.NoError(t, store.AdminPanel().Directions().Create(d))
+
+	er := &models.ExchangeRequest{ExchangeFrom: "USDTTRC20", ExchangeTo: "USDT"}
+	usdt := &models.Currency{Code: "USDT", Ticker: "USDT", Network: AppType.CurrencyNetworkTRC20}
+
+	// У направления нет привязок
+	account, err := lsnr.payoutAccount(context.Background(), er, nil, usdt, AppMoney.NewFromInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, account.ID)
+
+	assert.NoError(t, store.AdminPanel().Directions().Ma().Create(&models.DirectionMA{
+		DirectionID: d.ID,
+		MaID:        bound.ID,
+		ServiceType: AppType.UseAsAutoPayout,
+		Status:      true,
+	}))
+
+	account, err = lsnr.payoutAccount(context.Background(), er, nil, usdt, AppMoney.NewFromInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, bound.ID, account.ID)
+
+	// Повтор выплаты через аккаунт предыдущей попытки
+	for _, status := range []AppType.PayoutStatus{AppType.PayoutPending, AppType.PayoutSent, AppType.PayoutFailed} {
+		account, err = lsnr.payoutAccount(context.Background(), er, &models.Payout{MaID: first.ID, Status: status}, usdt, AppMoney.NewFromInt(1))
+		assert.NoError(t, err)
+		assert.Equal(t, first.ID, account.ID)
+	}
+
+	// После отказа аккаунт выбирается заново, даже если
+	// аккаунт отклоненной попытки отключен
+	first.Status = false
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Update(first))
+
+	account, err = lsnr.payoutAccount(context.Background(), er, &models.Payout{MaID: first.ID, Status: AppType.PayoutRejected}, usdt, AppMoney.NewFromInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, bound.ID, account.ID)
+}
+
+/*
+	Ошибка сервера биржи и превышение частоты запросов не означают отказа:
+	заявка остается оплаченной, и выплата повторяется с тем же uniqueId
+*/
+func Test_Listener_PayoutTemporaryError(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1000")
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+	autopayout := testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	er := testPaidRequest(t, store)
+
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		wb.FailNext(whitebittest.PathWithdrawPay, status, `{"message":"Too many requests"}`)
+
+		assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+		assert.Equal(t, AppType.ExchangeRequestPaid, testRequestStatus(t, store, er.ID))
+		assert.Empty(t, wb.Withdrawals())
+
+		last := &models.Payout{RequestID: er.ID}
+		assert.NoError(t, store.AdminPanel().Payout().GetLastByRequest(last))
+		assert.Equal(t, AppType.PayoutFailed, last.Status)
+	}
+
+	// Биржа снова доступна, выплата отправлена через тот же аккаунт
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, er.ID))
+
+	withdrawals := wb.Withdrawals()
+	assert.Len(t, withdrawals, 1)
+	assert.Equal(t, strconv.Itoa(er.ID), withdrawals[0].UniqueId)
+
+	last := &models.Payout{RequestID: er.ID}
+	assert.NoError(t, store.AdminPanel().Payout().GetLastByRequest(last))
+	assert.Equal(t, AppType.PayoutSent, last.Status)
+	assert.Equal(t, autopayout.ID, last.MaID)
+}
+
+/*
+	Биржа приняла вывод, но ответ не дошел до слушателя. Повторный
+	запрос отклоняется из-за занятого uniqueId, вывод находится
+	в истории и заявка ожидает подтверждения
+*/
+func Test_Listener_PayoutDuplicate(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1000")
+
+	store := mocksqlstore.Init()
+	appPlugins := plugins.InitAppPlugins(&cfg.Plugins)
+	lsnr := testListener(t, store, &testNsq{}, appPlugins, cfg)
+
+	pParams := wb.Account("payout-public", "payout-secret")
+	testAccount(t, store, cfg, AppType.UseAsAutoPayout, pParams)
+
+	er := testPaidRequest(t, store)
+
+	plugin, err := appPlugins.Get(AppType.MerchantAutoPayoutWhitebit)
+	assert.NoError(t, err)
+
+	_, err = plugin.AutoPayout().Payout(ctx, pParams, &interfaces.PayoutRequest{
+		Currency: testCurrency(t, store, "USDT"),
+		Address:  er.ClientAddress,
+		Amount:   AppMoney.NewFromInt(100),
+		UniqueID: strconv.Itoa(er.ID),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, er.ID))
+	assert.Len(t, wb.Withdrawals(), 1)
+	assert.Equal(t, "900", wb.Balance("USDT"))
+
+	last := &models.Payout{RequestID: er.ID}
+	assert.NoError(t, store.AdminPanel().Payout().GetLastByRequest(last))
+	assert.Equal(t, AppType.PayoutSent, last.Status)
+}
+
+/*
+	Две заявки с выплатой на один адрес клиента. Подтверждение
+	вывода завершает только заявку, с ключом которой он был отправлен
+*/
+func Test_Listener_WithdrawUniqueID(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1000")
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+	testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	arr := []*models.ExchangeRequest{}
+	for _, hash := range []string{"first-deposit", "second-deposit"} {
+		hash := hash
+		er := &models.ExchangeRequest{
+			Status:            AppType.ExchangeRequestPaid,
+			ExchangeFrom:      "USDTTRC20",
+			ExchangeTo:        "USDT",
+			Course:            "1",
+			Address:           "TDepositAddress",
+			ClientAddress:     "TClientAddress",
+			TransactionHash:   &hash,
+			ExpectedAmount:    AppMoney.NewFromInt(100),
+			TransferredAmount: AppMoney.NewFromInt(100),
+		}
+		assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+		arr = append(arr, er)
+	}
+
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Len(t, wb.Withdrawals(), 2)
+	for _, er := range arr {
+		assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, er.ID))
+	}
+
+	// Биржа подтвердила вывод только по второй заявке
+	assert.NoError(t, wb.SetStatus(strconv.Itoa(arr[1].ID), whitebittest.StatusSuccess))
+	assert.NoError(t, lsnr.tick(ctx, &cfg.Listener))
+	assert.Equal(t, AppType.ExchangeRequestAwaitingConfirmation, testRequestStatus(t, store, arr[0].ID))
+	assert.Equal(t, AppType.ExchangeRequestDone, testRequestStatus(t, store, arr[1].ID))
+}
+
+/*
+	Выплата в валюте, которой нет в каталоге, не отправляется
+	и попытка автовыплаты не фиксируется
+*/
+func Test_Listener_PayoutUnknownCurrency(t *testing.T) {
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1000")
+
+	store := mocksqlstore.Init()
+	lsnr := testListener(t, store, &testNsq{}, plugins.InitAppPlugins(&cfg.Plugins), cfg)
+	testAccount(t, store, cfg, AppType.UseAsAutoPayout, wb.Account("payout-public", "payout-secret"))
+
+	er := &models.ExchangeRequest{
+		Status:            AppType.ExchangeRequestPaid,
+		ExchangeFrom:      "USDTTRC20",
+		ExchangeTo:        "USDTBEP20",
+		Course:            "1",
+		ClientAddress:     "TClientAddress",
+		TransferredAmount: AppMoney.NewFromInt(100),
+	}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+
+	assert.ErrorIs(t, lsnr.payout(context.Background(), er), AppError.ErrCurrencyNotFound)
+	assert.Empty(t, wb.Withdrawals())
+
+	payouts, err := store.AdminPanel().Payout().Selection(&models.PayoutSelection{RequestID: er.ID})
+	assert.NoError(t, err)
+	assert.Empty(t, payouts)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+type testNsqMessage struct {
+	topic   string
+	payload []byte
+}
+
+// Имитация NSQ, сохраняющая все отправленные сообщения
+type testNsq struct {
+	mu  sync.Mutex
+	arr []testNsqMessage
+}
+
+func (n *testNsq) Publish(topic string, payload []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.arr = append(n.arr, testNsqMessage{topic: topic, payload: payload})
+	return nil
+}
+
+func (n *testNsq) messages() []testNsqMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]testNsqMessage{}, n.arr...)
+}
+
+func testListener(t *testing.T, store db.SQLStoreI, nsq *testNsq, p *plugins.AppPlugins, cfg *config.Config) *Listener {
+	t.Helper()
+
+	logger := utils.InitLogger(store.AdminPanel().Logs())
+	return InitListener(store, nsq, p, accounts.InitSelector(store, p, &cfg.Accounts, logger), logger).(*Listener)
+}
+
+func testAccount(t *testing.T, store db.SQLStoreI, cfg *config.Config, serviceType int, p *models.WhitebitOptionParams) *models.MerchantAutopayout {
+	t.Helper()
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+
+	options, err := AppMath.AesEncrypt(string(b), hex.EncodeToString([]byte(cfg.Plugins.AesKey)))
+	assert.NoError(t, err)
+
+	m := &models.MerchantAutopayout{
+		Name:        p.PublicKey,
+		Service:     AppType.MerchantAutoPayoutWhitebit,
+		ServiceType: serviceType,
+		Options:     options,
+		Status:      true,
+	}
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Create(m))
+
+	return m
+}
+
+// Валюта из каталога хранилища в виде, в котором она передается плагинам
+func testCurrency(t *testing.T, store db.SQLStoreI, code string) *interfaces.Currency {
+	t.Helper()
+
+	c := &models.Currency{Code: code}
+	assert.NoError(t, store.AdminPanel().Currency().GetByCode(c))
+
+	return c.Plugin()
+}
+
+// Оплаченная заявка на выплату 100 USDT
+func testPaidRequest(t *testing.T, store db.SQLStoreI) *models.ExchangeRequest {
+	t.Helper()
+
+	er := &models.ExchangeRequest{
+		Status:            AppType.ExchangeRequestPaid,
+		ExchangeFrom:      "USDTTRC20",
+		ExchangeTo:        "USDT",
+		Course:            "1",
+		Address:           "TDepositAddress",
+		ClientAddress:     "TClientAddress",
+		ExpectedAmount:    AppMoney.NewFromInt(100),
+		TransferredAmount: AppMoney.NewFromInt(100),
+	}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+
+	return er
+}
+
+func testRequestStatus(t *testing.T, store db.SQLStoreI, id int) AppType.ExchangeRequestStatus {
+	t.Helper()
+
+	er := &models.ExchangeRequest{ID: id}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Get(er))
+
+	return er.Status
+}
@@ -0,0 +1,260 @@
+package listener
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+/*
+	Движок автовыплат
+
+	По каждой оплаченной заявке выплата производится ровно через один
+	аккаунт автовыплат. Перед обращением к платежной системе в таблице
+	payouts фиксируется попытка, а в качестве ключа идемпотентности
+	(uniqueId) используется ID заявки. Благодаря этому повторная итерация
+	слушателя или его перезапуск не могут отправить деньги дважды.
+*/
+
+// Метод выполняет автовыплату по заявке rRequest
+func (l *Listener) payout(ctx context.Context, rRequest *models.ExchangeRequest) error {
+	last := &models.Payout{RequestID: rRequest.ID}
+	if err := l.store.AdminPanel().Payout().GetLastByRequest(last); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		last = nil
+	}
+
+	// Платежная система уже приняла запрос, но статус
+	// заявки не успел обновиться до перезапуска
+	if last != nil && last.Status == AppType.PayoutSent {
+		return l.transition(rRequest, AppType.ExchangeRequestAwaitingConfirmation, "payout already accepted by provider")
+	}
+
+	amount, err := payoutAmount(rRequest)
+	if err != nil {
+		return err
+	}
+
+	currency := &models.Currency{Code: rRequest.ExchangeTo}
+	if err := l.store.AdminPanel().Currency().GetByCode(currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w | request: %d | currency: %s", AppError.ErrCurrencyNotFound, rRequest.ID, rRequest.ExchangeTo)
+		}
+		return err
+	}
+
+	// Без memo клиента выплата в валюте с memo не дойдет до получателя
+	if currency.MemoRequired && rRequest.ClientMemo == "" {
+		return fmt.Errorf("%w | request: %d | currency: %s", AppError.ErrClientMemoRequired, rRequest.ID, rRequest.ExchangeTo)
+	}
+
+	account, err := l.payoutAccount(ctx, rRequest, last, currency, amount)
+	if err != nil {
+		return err
+	}
+
+	plugin, err := l.plugin.Get(account.Service)
+	if err != nil {
+		return err
+	}
+
+	params, err := plugin.GetOptionParams(account.Options)
+	if err != nil {
+		return fmt.Errorf("%s | ID: %d | %s",
+			AppError.ErrFailedToDecodeParams.Error(),
+			account.ID,
+			err.Error(),
+		)
+	}
+
+	// Фиксирую попытку до обращения к платежной системе
+	pc := currency.Plugin().Provider(account.Service)
+	attempt := &models.Payout{
+		RequestID: rRequest.ID,
+		MaID:      account.ID,
+		Ticker:    pc.Ticker,
+		Network:   pc.Network,
+		Amount:    amount,
+		Status:    AppType.PayoutPending,
+	}
+	if err := l.store.AdminPanel().Payout().Create(attempt); err != nil {
+		return err
+	}
+
+	res, err := plugin.AutoPayout().Payout(ctx, params, &interfaces.PayoutRequest{
+		Currency: currency.Plugin(),
+		Address:  rRequest.ClientAddress,
+		Memo:     rRequest.ClientMemo,
+		Amount:   amount,
+		UniqueID: strconv.Itoa(rRequest.ID),
+	})
+	if err != nil {
+		// Вывод с этим ключом идемпотентности уже был принят
+		// ранее, но ответ платежной системы не был получен
+		if errors.Is(err, AppError.ErrPayoutDuplicate) {
+			return l.payoutDuplicate(ctx, plugin, params, attempt, rRequest, err)
+		}
+
+		pErr, rejected := interfaces.AsProviderError(err)
+		if rejected && pErr.Temporary() {
+			rejected = false
+		}
+
+		if !rejected && !errors.Is(err, AppError.ErrPluginNotSupported) {
+			// Результат неизвестен, заявка остается оплаченной и на следующей
+			// итерации запрос будет повторен с тем же ключом идемпотентности
+			var raw []byte
+			if pErr != nil {
+				raw = pErr.Raw
+			}
+
+			return l.payoutAttempt(attempt, AppType.PayoutFailed, raw, err)
+		}
+
+		// Платежная система отклонила запрос и деньги не отправились
+		var raw []byte
+		if rejected {
+			raw = pErr.Raw
+		}
+
+		return l.payoutRejected(attempt, rRequest, raw, err)
+	}
+
+	// Деньги ушли
+	if err := l.payoutAttempt(attempt, AppType.PayoutSent, res.Raw, nil); err != nil {
+		return err
+	}
+
+	utils.SetSuccessStep(AppType.SprintfStep("Payout for request %d sent", rRequest.ID))
+	return l.transition(rRequest, AppType.ExchangeRequestAwaitingConfirmation, "payout sent")
+}
+
+// Метод отмечает, что платежная система отклонила выплату по заявке
+// rRequest и деньги не отправились. Заявка переводится в статус
+// ошибки автовыплаты для проверки менеджером.
+func (l *Listener) payoutRejected(attempt *models.Payout, rRequest *models.ExchangeRequest, raw []byte, cause error) error {
+	err := fmt.Errorf("%s | request: %d | %w", AppError.ErrAutopayoutRejected.Error(), rRequest.ID, cause)
+	if uErr := l.payoutAttempt(attempt, AppType.PayoutRejected, raw, err); uErr != err {
+		return uErr
+	}
+
+	if uErr := l.transition(rRequest, AppType.ExchangeRequestAutopayoutError, err.Error()); uErr != nil {
+		return uErr
+	}
+
+	return err
+}
+
+// Метод проверяет по истории аккаунта вывод, который платежная система
+// уже приняла с ключом идемпотентности заявки rRequest. Если вывод
+// найден, деньги считаются отправленными. Если вывод не найден или
+// историю получить не удалось, результат остается неизвестным
+// и запрос будет повторен на следующей итерации.
+func (l *Listener) payoutDuplicate(ctx context.Context, plugin interfaces.PluginI, params interfaces.OptionParams, attempt *models.Payout, rRequest *models.ExchangeRequest, cause error) error {
+	uniqueID := strconv.Itoa(rRequest.ID)
+
+	history, err := plugin.History(ctx, params, &interfaces.HistoryQuery{
+		Method:   interfaces.HistoryWithdraw,
+		UniqueID: uniqueID,
+		Limit:    defaultHistoryPageLimit,
+	})
+	if err != nil {
+		return l.payoutAttempt(attempt, AppType.PayoutFailed, nil, fmt.Errorf("%w | history: %s", cause, err.Error()))
+	}
+
+	for _, r := range history.Records {
+		if r.Method != interfaces.HistoryWithdraw || r.UniqueID != uniqueID {
+			continue
+		}
+
+		// Платежная система приняла вывод, но не смогла его выполнить
+		if r.Status == interfaces.HistoryStatusFailed {
+			return l.payoutRejected(attempt, rRequest, nil, cause)
+		}
+
+		if err := l.payoutAttempt(attempt, AppType.PayoutSent, nil, nil); err != nil {
+			return err
+		}
+
+		return l.transition(rRequest, AppType.ExchangeRequestAwaitingConfirmation, "payout already accepted by provider")
+	}
+
+	return l.payoutAttempt(attempt, AppType.PayoutFailed, nil, cause)
+}
+
+// Метод сохраняет результат попытки автовыплаты. Если сохранить
+// результат не удалось, возвращается ошибка записи, иначе cause.
+func (l *Listener) payoutAttempt(attempt *models.Payout, s AppType.PayoutStatus, raw []byte, cause error) error {
+	attempt.Status = s
+
+	if raw != nil {
+		r := string(raw)
+		attempt.RawResponse = &r
+	}
+
+	if cause != nil {
+		e := cause.Error()
+		attempt.Error = &e
+	}
+
+	if err := l.store.AdminPanel().Payout().Update(attempt); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// Сумма выплаты рассчитывается от фактически полученной по заявке
+// суммы по курсу заявки и округляется вниз до точности валюты выплаты
+func payoutAmount(rRequest *models.ExchangeRequest) (AppMoney.Money, error) {
+	course, err := decimal.NewFromString(rRequest.Course)
+	if err != nil {
+		return AppMoney.Zero, fmt.Errorf("%w | request: %d | course: %s", AppError.ErrInvalidPayoutAmount, rRequest.ID, rRequest.Course)
+	}
+
+	amount := rRequest.TransferredAmount.Mul(course).Round(rRequest.ExchangeTo)
+	if !amount.IsPositive() {
+		return AppMoney.Zero, fmt.Errorf("%w | request: %d | amount: %s", AppError.ErrInvalidPayoutAmount, rRequest.ID, amount.String())
+	}
+
+	return amount, nil
+}
+
+// Метод выбирает аккаунт для выплаты суммы amount в валюте currency
+// по заявке rRequest. Если результат предыдущей попытки неизвестен,
+// повтор выполняется строго через тот же аккаунт, иначе аккаунт
+// выбирается способом, заданным в направлении заявки. После отказа
+// платежной системы аккаунт выбирается заново.
+func (l *Listener) payoutAccount(ctx context.Context, rRequest *models.ExchangeRequest, last *models.Payout, currency *models.Currency, amount AppMoney.Money) (*models.MerchantAutopayout, error) {
+	if last != nil && last.Status != AppType.PayoutRejected {
+		account := &models.MerchantAutopayout{ID: last.MaID}
+		if err := l.store.AdminPanel().MerchantAutopayout().Get(account); err != nil {
+			return nil, err
+		}
+
+		if !account.Status {
+			return nil, fmt.Errorf("%s | ID: %d", AppError.ErrNoAutopayoutAccount.Error(), account.ID)
+		}
+
+		return account, nil
+	}
+
+	d, err := l.direction(rRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.selector.Autopayout(ctx, d, currency.Plugin(), amount)
+}
@@ -2,24 +2,27 @@ package listener
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
-	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"golang.org/x/sync/errgroup"
 )
 
-func (listener *Listener) snapshot(ctx context.Context, cfg *config.ListenerConfig) (*models.ListenerState, error) {
-	c := &models.ListenerState{
-		Merchants: &models.ListeningAccounts{
-			Whitebit: []*models.WhitebitOptionParams{},
-			Mine:     []*models.MineOptionParams{},
-		},
-	}
+// Метод собирает набор аккаунтов для итерации слушателя.
+// Мерчанты и автовыплаты собираются в отдельных горутинах, каждая
+// пишет только в свои массивы. Аккаунт, который не удалось
+// расшифровать или пропинговать, попадает в список неудачных
+// и не мешает опросу остальных аккаунтов.
+func (listener *Listener) snapshot(ctx context.Context) (*models.ListenerState, error) {
+	var (
+		merchants, autopayouts             []*models.ListeningAccount
+		failedMerchants, failedAutopayouts []*models.FailedAccount
+	)
+
 	errs, _ := errgroup.WithContext(ctx)
 
 	// Получение списка всех доступных мерчантов
@@ -31,19 +34,7 @@ func (listener *Listener) snapshot(ctx context.Context, cfg *config.ListenerConf
 
 		utils.SetSuccessStep(AppType.ListenerStepGetAllMerachants)
 
-		for _, m := range arr {
-			switch m.Service {
-			case AppType.MerchantAutoPayoutWhitebit:
-				p, err := listener.ping(m)
-				if err != nil {
-					return err
-				}
-
-				utils.SetSuccessStep(AppType.SprintfStep("Ping account %s", m.Name))
-				c.Merchants.Whitebit = append(c.Merchants.Whitebit, p.(*models.WhitebitOptionParams))
-			}
-		}
-
+		merchants, failedMerchants = listener.prepare(ctx, arr)
 		return nil
 	})
 
@@ -56,28 +47,65 @@ func (listener *Listener) snapshot(ctx context.Context, cfg *config.ListenerConf
 
 		utils.SetSuccessStep(AppType.ListenerStepGetAllAutopayouts)
 
-		for _, m := range arr {
-			switch m.Service {
-			case AppType.MerchantAutoPayoutWhitebit:
-				p, err := listener.ping(m)
-				if err != nil {
-					return err
-				}
+		autopayouts, failedAutopayouts = listener.prepare(ctx, arr)
+		return nil
+	})
+
+	if err := errs.Wait(); err != nil {
+		return nil, err
+	}
 
-				utils.SetSuccessStep(AppType.SprintfStep("Ping account %s", m.Name))
-				c.Merchants.Whitebit = append(c.Autopayouts.Whitebit, p.(*models.WhitebitOptionParams))
+	return models.NewListenerState(merchants, autopayouts, append(failedMerchants, failedAutopayouts...)), nil
+}
+
+// Метод расшифровывает параметры и пингует аккаунты arr сервисов,
+// которые умеет опрашивать слушатель
+func (listener *Listener) prepare(ctx context.Context, arr []*models.MerchantAutopayout) ([]*models.ListeningAccount, []*models.FailedAccount) {
+	accounts := []*models.ListeningAccount{}
+	failed := []*models.FailedAccount{}
+
+	for _, m := range arr {
+		switch m.Service {
+		case AppType.MerchantAutoPayoutWhitebit:
+			p, err := listener.ping(ctx, m)
+			if err != nil {
+				listener.logger.NewRecord(&models.LogRecord{
+					Service: AppType.LogTypeServer,
+					Module:  AppType.LogModuleListener,
+					Info:    err.Error(),
+				})
+
+				failed = append(failed, &models.FailedAccount{
+					ID:          m.ID,
+					Name:        m.Name,
+					Service:     m.Service,
+					ServiceType: m.ServiceType,
+					Error:       err.Error(),
+				})
+				continue
 			}
-		}
 
-		return nil
-	})
+			utils.SetSuccessStep(AppType.SprintfStep("Ping account %s", m.Name))
+			accounts = append(accounts, &models.ListeningAccount{
+				ID:      m.ID,
+				Name:    m.Name,
+				Service: m.Service,
+				Params:  p,
+			})
+		}
+	}
 
-	return c, errs.Wait()
+	return accounts, failed
 }
 
-func (listener *Listener) ping(m *models.MerchantAutopayout) (interface{}, error) {
+func (listener *Listener) ping(ctx context.Context, m *models.MerchantAutopayout) (interfaces.OptionParams, error) {
+	plugin, err := listener.plugin.Get(m.Service)
+	if err != nil {
+		return nil, err
+	}
+
 	// Декодирую опциональные параметры
-	p, err := listener.plugin.Whitebit.GetOptionParams(m.Options)
+	p, err := plugin.GetOptionParams(m.Options)
 	if err != nil {
 		return nil, fmt.Errorf("%s | ID: %d | %s",
 			AppError.ErrFailedToDecodeParams.Error(),
@@ -89,19 +117,9 @@ func (listener *Listener) ping(m *models.MerchantAutopayout) (interface{}, error
 	utils.SetSuccessStep(AppType.SprintfStep("%s %s", DecodeParams, m.Name))
 
 	// Пингую аккаунт
-	b, err := listener.plugin.Whitebit.Ping(p)
-	if err != nil {
-		return nil, fmt.Errorf("failed to ping account %s | %s", m.Name, err.Error())
-	}
-
-	var resp map[string]interface{}
-	if err := json.Unmarshal([]byte(b.([]byte)), &resp); err != nil {
+	if err := plugin.Ping(ctx, p); err != nil {
 		return nil, fmt.Errorf("failed to ping account %s | %s", m.Name, err.Error())
 	}
 
-	if resp["code"] != nil {
-		return nil, fmt.Errorf("failed to ping account %s", m.Name)
-	}
-
 	return p, nil
 }
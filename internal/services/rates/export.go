@@ -0,0 +1,129 @@
+package rates
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Публичная выгрузка курсов обменника для мониторингов.
+//
+// В выгрузку попадают только активные направления, для которых
+// есть актуальный курс и хотя бы один активный аккаунт мерчанта.
+// Резерв направления задается менеджером либо рассчитывается по
+// балансам аккаунтов автовыплат. Готовая выгрузка кешируется,
+// чтобы частые запросы мониторингов не нагружали платежные системы.
+type Exporter struct {
+	store   db.SQLStoreI
+	rates   EngineI
+	reserve ReserveI
+	logger  utils.LoggerI
+
+	mu          sync.Mutex
+	cache       *models.OneObmen
+	generatedAt time.Time
+}
+
+type ExporterI interface {
+	Export(ctx context.Context, cfg *config.RatesConfig) (*models.OneObmen, error)
+}
+
+func InitExporter(s db.SQLStoreI, r EngineI, rs ReserveI, l utils.LoggerI) ExporterI {
+	return &Exporter{
+		store:   s,
+		rates:   r,
+		reserve: rs,
+		logger:  l,
+	}
+}
+
+// Метод возвращает выгрузку курсов, пересчитывая ее
+// не чаще одного раза в cfg.ExportCacheTTL секунд
+func (e *Exporter) Export(ctx context.Context, cfg *config.RatesConfig) (*models.OneObmen, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cache != nil && time.Since(e.generatedAt) < time.Duration(cfg.ExportCacheTTL)*time.Second {
+		return e.cache, nil
+	}
+
+	doc, err := e.generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	e.cache, e.generatedAt = doc, time.Now()
+	return doc, nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (e *Exporter) generate(ctx context.Context) (*models.OneObmen, error) {
+	directions, err := e.store.AdminPanel().Directions().GetAllActive()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &models.OneObmen{Rates: []models.OneObmenItem{}}
+	for _, d := range directions {
+		r, err := e.rates.Get(d.ExchangeFrom, d.ExchangeTo)
+		if err != nil {
+			continue
+		}
+
+		merchants, err := e.store.AdminPanel().Directions().Ma().GetActiveAccounts(d.ID, AppType.UseAsMerchant)
+		if err != nil {
+			return nil, err
+		}
+
+		// Прием средств по направлению невозможен
+		if len(merchants) == 0 {
+			continue
+		}
+
+		autopayouts, err := e.store.AdminPanel().Directions().Ma().GetActiveAccounts(d.ID, AppType.UseAsAutoPayout)
+		if err != nil {
+			return nil, err
+		}
+
+		// Выплата по направлению невозможна
+		if len(autopayouts) == 0 {
+			continue
+		}
+
+		reserve, err := e.reserve.Get(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+
+		item := models.OneObmenItem{
+			From:   d.ExchangeFrom,
+			To:     d.ExchangeTo,
+			In:     r.In,
+			Out:    r.Out,
+			Amount: reserve.Round(d.ExchangeTo),
+		}
+
+		if d.MinAmount.IsPositive() {
+			item.MinAmount = d.MinAmount.Round(d.ExchangeFrom).String()
+		}
+
+		if d.MaxAmount.IsPositive() {
+			item.MaxAmount = d.MaxAmount.Round(d.ExchangeFrom).String()
+		}
+
+		doc.Rates = append(doc.Rates, item)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,151 @@
+package rates
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppMath "github.com/gefion-tech/tg-exchanger-server/internal/core/math"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+
+	// Подключение плагинов мерчантов/автовыплат
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+)
+
+/*
+	В выгрузку попадают только активные направления с курсом,
+	активным мерчантом и активным аккаунтом автовыплат, резерв
+	берется с аккаунтов автовыплат
+*/
+func Test_Rates_Export(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	source := testSource(t, `
+		<rates>
+			<item><from>USDTTRC20</from><to>USDT</to><in>1</in><out>1</out></item>
+			<item><from>BTC</from><to>USDT</to><in>1</in><out>40000</out></item>
+			<item><from>ETH</from><to>USDT</to><in>1</in><out>3000</out></item>
+			<item><from>TRX</from><to>USDT</to><in>1</in><out>0.1</out></item>
+		</rates>`)
+	defer source.Close()
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "1500.1234567")
+
+	store := mocksqlstore.Init()
+	logger := utils.InitLogger(store.AdminPanel().Logs())
+	engine := InitEngine(store, &testRedis{rates: map[string]string{}}, &testNsq{}, logger)
+	exporter := InitExporter(store, engine, InitReserve(store, plugins.InitAppPlugins(&cfg.Plugins), logger), logger)
+
+	assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: "source", UrlToParse: source.URL}))
+
+	merchant := testAccount(t, store, cfg, AppType.UseAsMerchant, true, wb.Account("merchant-public", "merchant-secret"))
+	autopayout := testAccount(t, store, cfg, AppType.UseAsAutoPayout, true, wb.Account("payout-public", "payout-secret"))
+	disabled := testAccount(t, store, cfg, AppType.UseAsMerchant, false, wb.Account("disabled-public", "disabled-secret"))
+
+	// Направление с мерчантом и автовыплатой
+	usdt := testDirection(t, store, "USDTTRC20", "USDT")
+	usdt.ReserveType = AppType.ReserveAuto
+	assert.NoError(t, store.AdminPanel().Directions().Update(usdt))
+	testBinding(t, store, usdt, merchant, AppType.UseAsMerchant)
+	testBinding(t, store, usdt, autopayout, AppType.UseAsAutoPayout)
+
+	// Направление без способа задания резерва попадает в выгрузку с нулевым резервом
+	btc := testDirection(t, store, "BTC", "USDT")
+	testBinding(t, store, btc, merchant, AppType.UseAsMerchant)
+	testBinding(t, store, btc, autopayout, AppType.UseAsAutoPayout)
+
+	// Направление без автовыплаты
+	trx := testDirection(t, store, "TRX", "USDT")
+	testBinding(t, store, trx, merchant, AppType.UseAsMerchant)
+
+	// Направление только с отключенным мерчантом
+	eth := testDirection(t, store, "ETH", "USDT")
+	testBinding(t, store, eth, disabled, AppType.UseAsMerchant)
+
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+
+	doc, err := exporter.Export(ctx, &cfg.Rates)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Rates, 2)
+
+	assert.Equal(t, "USDTTRC20", doc.Rates[0].From)
+	assert.Equal(t, "1", doc.Rates[0].Out.String())
+	assert.Equal(t, "1500.123456", doc.Rates[0].Amount.String())
+
+	assert.Equal(t, "BTC", doc.Rates[1].From)
+	assert.Equal(t, "40000", doc.Rates[1].Out.String())
+	assert.True(t, doc.Rates[1].Amount.IsZero())
+
+	// Выгрузка читается в том же формате, что и источники
+	b, err := xml.Marshal(doc)
+	assert.NoError(t, err)
+
+	parsed := models.OneObmen{}
+	assert.NoError(t, xml.Unmarshal(b, &parsed))
+	assert.Len(t, parsed.Rates, 2)
+	assert.Equal(t, "USDT", parsed.Rates[0].To)
+
+	// Повторный запрос в течение срока кеширования не обращается к бирже
+	requests := wb.Requests(whitebittest.PathBalance)
+	_, err = exporter.Export(ctx, &cfg.Rates)
+	assert.NoError(t, err)
+	assert.Equal(t, requests, wb.Requests(whitebittest.PathBalance))
+}
+
+func testAccount(t *testing.T, store db.SQLStoreI, cfg *config.Config, serviceType int, status bool, p *models.WhitebitOptionParams) *models.MerchantAutopayout {
+	t.Helper()
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+
+	options, err := AppMath.AesEncrypt(string(b), hex.EncodeToString([]byte(cfg.Plugins.AesKey)))
+	assert.NoError(t, err)
+
+	m := &models.MerchantAutopayout{
+		Name:        p.PublicKey,
+		Service:     AppType.MerchantAutoPayoutWhitebit,
+		ServiceType: serviceType,
+		Options:     options,
+		Status:      status,
+	}
+	assert.NoError(t, store.AdminPanel().MerchantAutopayout().Create(m))
+
+	return m
+}
+
+func testDirection(t *testing.T, store db.SQLStoreI, from, to string) *models.Direction {
+	t.Helper()
+
+	d := &models.Direction{
+		ExchangeFrom: from,
+		ExchangeTo:   to,
+		Status:       true,
+	}
+	assert.NoError(t, store.AdminPanel().Directions().Create(d))
+
+	return d
+}
+
+func testBinding(t *testing.T, store db.SQLStoreI, d *models.Direction, ma *models.MerchantAutopayout, serviceType int) {
+	t.Helper()
+
+	assert.NoError(t, store.AdminPanel().Directions().Ma().Create(&models.DirectionMA{
+		DirectionID: d.ID,
+		MaID:        ma.ID,
+		ServiceType: serviceType,
+		Status:      true,
+	}))
+}
@@ -0,0 +1,115 @@
+package rates
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Функция возвращает место курса r среди котировок источников.
+// Первое место - самый выгодный для клиента курс, при равенстве
+// курсов место делится.
+func position(r *models.Rate, quotes []*quote) int {
+	p := 1
+	for _, q := range quotes {
+		// q.out/q.in > r.Out/r.In без деления
+		if q.out.Mul(r.In.Decimal).GreaterThan(r.Out.Mul(q.in.Decimal).Decimal) {
+			p++
+		}
+	}
+
+	return p
+}
+
+// Функция подбирает корректировку, при которой курс направления
+// займет место target среди котировок источников. Корректировка
+// отсчитывается от опорной котировки ref и ограничивается границами
+// направления d. Если источников меньше чем target, курс опускается
+// до нижней границы.
+func correction(d *models.Direction, ref *quote, quotes []*quote, target int) int {
+	if target > len(quotes) {
+		return d.CorrectionMin
+	}
+
+	courses := make([]decimal.Decimal, 0, len(quotes))
+	for _, q := range quotes {
+		courses = append(courses, q.out.Div(q.in.Decimal))
+	}
+
+	sort.Slice(courses, func(i, j int) bool { return courses[i].GreaterThan(courses[j]) })
+
+	// Курс должен быть выгоднее котировки, занимающей место target
+	base := ref.out.Div(ref.in.Decimal)
+	cc := courses[target-1].Div(base).Sub(decimal.NewFromInt(1)).Shift(4).Floor().IntPart() + 1
+
+	return d.ClampCorrection(int(cc))
+}
+
+// Метод удерживает направление d на целевом месте, изменяя его
+// корректировку. Новая корректировка сохраняется в БД, только если
+// менеджер не изменил ее после чтения направления. Каждое
+// изменение записывается в логи и отправляется менеджерам.
+func (e *Engine) autoPricing(d *models.Direction, ref *quote, quotes []*quote) {
+	cc := correction(d, ref, quotes, d.TargetPosition)
+	if cc == d.CourseCorrection {
+		return
+	}
+
+	prev := d.CourseCorrection
+	d.CourseCorrection = cc
+	if err := e.store.AdminPanel().Directions().SetCourseCorrection(d, prev); err != nil {
+		d.CourseCorrection = prev
+		e.log(fmt.Sprintf("auto pricing %s: %s", key(d.ExchangeFrom, d.ExchangeTo), err.Error()))
+		return
+	}
+
+	e.log(fmt.Sprintf("auto pricing %s: course correction %d -> %d, target position %d",
+		key(d.ExchangeFrom, d.ExchangeTo), prev, cc, d.TargetPosition,
+	))
+
+	if err := e.notifyManagers(fmt.Sprintf("🟡 Автоматическая корректировка курса 🟡\n\n*Направление*: %s -> %s\n*Целевое место*: %d\n*Корректировка*: %d -> %d",
+		d.ExchangeFrom,
+		d.ExchangeTo,
+		d.TargetPosition,
+		prev,
+		cc,
+	)); err != nil {
+		e.log(fmt.Sprintf("auto pricing %s: %s", key(d.ExchangeFrom, d.ExchangeTo), err.Error()))
+	}
+}
+
+func (e *Engine) notifyManagers(text string) error {
+	uArr, err := e.store.User().GetAllManagers()
+	if err != nil {
+		return err
+	}
+
+	for _, u := range uArr {
+		payload, err := json.Marshal(map[string]interface{}{
+			"to": map[string]interface{}{
+				"chat_id":  u.ChatID,
+				"username": u.Username,
+			},
+			"message": map[string]interface{}{
+				"type": AppType.QueueEventExchangeError,
+				"text": text,
+			},
+			"created_at": time.Now().UTC().Format(core.DateStandart),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := e.nsq.Publish(AppType.TopicBotMessages, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
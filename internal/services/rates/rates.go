@@ -0,0 +1,287 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/supervisor"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Процесс расчета курсов направлений обмена.
+//
+// Периодически опрашивает все источники из таблицы `exchangers`,
+// выбирает опорный курс для каждого активного направления и применяет
+// к нему корректировку направления. Для направлений с автоматической
+// корректировкой она подбирается так, чтобы курс занимал целевое
+// место среди источников. Текущие курсы хранятся в памяти
+// и дублируются в Redis, откуда их может прочитать бот и сервер после
+// перезапуска, пока не завершилось первое обновление.
+type Engine struct {
+	store  db.SQLStoreI
+	redis  redisstore.RatesClientI
+	nsq    nsqstore.NsqI
+	client *http.Client
+	logger utils.LoggerI
+
+	supervisor supervisor.SupervisorI
+
+	mu    sync.RWMutex
+	rates map[string]*entry
+}
+
+type EngineI interface {
+	Refresh(ctx context.Context, cfg *config.RatesConfig) error
+	Supervise(ctx context.Context, cfg *config.RatesConfig)
+	Get(from, to string) (*models.Rate, error)
+	All() []*models.Rate
+}
+
+// Курс направления и время его получения из источника
+type entry struct {
+	rate *models.Rate
+	at   time.Time
+}
+
+func InitEngine(s db.SQLStoreI, r redisstore.RatesClientI, q nsqstore.NsqI, l utils.LoggerI) EngineI {
+	return &Engine{
+		store:  s,
+		redis:  r,
+		nsq:    q,
+		client: &http.Client{},
+		logger: l,
+
+		supervisor: supervisor.Init(AppType.LogModuleRates, 0, 0, l),
+		rates:      map[string]*entry{},
+	}
+}
+
+// Метод запускает периодическое обновление курсов под наблюдением
+// супервизора. Блокирует выполнение до отмены контекста ctx.
+func (e *Engine) Supervise(ctx context.Context, cfg *config.RatesConfig) {
+	e.supervisor.Run(ctx, func(ctx context.Context) error {
+		for {
+			t := time.NewTimer(time.Duration(cfg.Interval) * time.Second)
+
+			if err := e.Refresh(ctx, cfg); err != nil {
+				t.Stop()
+				return err
+			}
+			e.supervisor.Tick()
+
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil
+			case <-t.C:
+			}
+		}
+	})
+}
+
+// Одна итерация обновления курсов. Недоступный источник пропускается,
+// курс направления, для которого не нашлось ни одной котировки,
+// остается прежним пока не истечет его срок актуальности cfg.TTL.
+// Котировки всех источников сохраняются в историю курсов.
+func (e *Engine) Refresh(ctx context.Context, cfg *config.RatesConfig) error {
+	exchangers, err := e.store.AdminPanel().Exchanger().GetAll()
+	if err != nil {
+		return err
+	}
+
+	directions, err := e.store.AdminPanel().Directions().GetAllActive()
+	if err != nil {
+		return err
+	}
+
+	quotes := map[string][]*quote{}
+	for _, ex := range exchangers {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		items, err := e.fetch(ctx, cfg, ex)
+		if err != nil {
+			e.log(fmt.Sprintf("source %s: %s", ex.Name, err.Error()))
+			continue
+		}
+
+		for _, item := range items {
+			if !item.In.IsPositive() || !item.Out.IsPositive() {
+				continue
+			}
+
+			k := key(item.From, item.To)
+			quotes[k] = append(quotes[k], &quote{exchangerID: ex.ID, source: ex.Name, in: item.In, out: item.Out})
+		}
+	}
+
+	now := time.Now().UTC()
+	ttl := time.Duration(cfg.TTL) * time.Second
+
+	e.mu.RLock()
+	prev := e.rates
+	e.mu.RUnlock()
+
+	rates := make(map[string]*entry, len(directions))
+	history := []*models.RateHistory{}
+	for _, d := range directions {
+		k := key(d.ExchangeFrom, d.ExchangeTo)
+
+		q := reference(quotes[k])
+		if q == nil {
+			if p, ok := prev[k]; ok && now.Sub(p.at) < ttl {
+				rates[k] = p
+			}
+			continue
+		}
+
+		if d.AutoPricing {
+			e.autoPricing(d, q, quotes[k])
+		}
+
+		r := &models.Rate{
+			ExchangeFrom: d.ExchangeFrom,
+			ExchangeTo:   d.ExchangeTo,
+			Source:       q.source,
+			SourceIn:     q.in,
+			SourceOut:    q.out,
+			UpdatedAt:    now.Format(core.DateStandart),
+		}
+		r.Correct(d.CourseCorrection)
+		r.Position, r.Competitors = position(r, quotes[k]), len(quotes[k])
+
+		rates[k] = &entry{rate: r, at: now}
+		history = append(history, e.history(d, q, quotes[k])...)
+
+		if err := e.save(r, ttl); err != nil {
+			e.log(fmt.Sprintf("direction %s: %s", k, err.Error()))
+		}
+	}
+
+	e.mu.Lock()
+	e.rates = rates
+	e.mu.Unlock()
+
+	if len(history) > 0 {
+		if err := e.store.AdminPanel().RateHistory().Create(history); err != nil {
+			e.log(fmt.Sprintf("rate history: %s", err.Error()))
+		}
+	}
+
+	if cfg.HistoryRetention > 0 {
+		before := now.AddDate(0, 0, -cfg.HistoryRetention).Format(core.DateStandart)
+		if _, err := e.store.AdminPanel().RateHistory().DeleteBefore(before); err != nil {
+			e.log(fmt.Sprintf("rate history: %s", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// Метод возвращает текущий курс направления from -> to
+func (e *Engine) Get(from, to string) (*models.Rate, error) {
+	e.mu.RLock()
+	p, ok := e.rates[key(from, to)]
+	e.mu.RUnlock()
+
+	if ok {
+		r := *p.rate
+		return &r, nil
+	}
+
+	// Курсы в памяти еще не рассчитаны, например сразу после
+	// запуска сервера, поэтому используется копия из Redis
+	body, err := e.redis.FetchRate(from, to)
+	if err != nil {
+		return nil, AppError.ErrRateNotAvailable
+	}
+
+	r := &models.Rate{}
+	if err := json.Unmarshal([]byte(body), r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Метод возвращает текущие курсы всех направлений
+func (e *Engine) All() []*models.Rate {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	arr := make([]*models.Rate, 0, len(e.rates))
+	for _, p := range e.rates {
+		r := *p.rate
+		arr = append(arr, &r)
+	}
+
+	sort.Slice(arr, func(i, j int) bool {
+		return key(arr[i].ExchangeFrom, arr[i].ExchangeTo) < key(arr[j].ExchangeFrom, arr[j].ExchangeTo)
+	})
+
+	return arr
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Метод формирует записи истории курсов направления d
+// по всем котировкам источников, ref - опорная котировка
+func (e *Engine) history(d *models.Direction, ref *quote, quotes []*quote) []*models.RateHistory {
+	arr := make([]*models.RateHistory, 0, len(quotes))
+	for _, q := range quotes {
+		r := &models.Rate{SourceIn: q.in, SourceOut: q.out}
+		r.Correct(d.CourseCorrection)
+
+		arr = append(arr, &models.RateHistory{
+			DirectionID:      d.ID,
+			ExchangerID:      q.exchangerID,
+			SourceIn:         r.SourceIn,
+			SourceOut:        r.SourceOut,
+			CourseCorrection: r.CourseCorrection,
+			In:               r.In,
+			Out:              r.Out,
+			Reference:        q == ref,
+		})
+	}
+
+	return arr
+}
+
+func (e *Engine) save(r *models.Rate, ttl time.Duration) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return e.redis.SaveRate(r.ExchangeFrom, r.ExchangeTo, body, ttl)
+}
+
+func (e *Engine) log(info string) {
+	e.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  AppType.LogModuleRates,
+		Info:    info,
+	})
+}
+
+func key(from, to string) string {
+	return from + "/" + to
+}
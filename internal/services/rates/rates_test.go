@@ -0,0 +1,349 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Опорный курс выбирается среди всех источников,
+	к нему применяется корректировка направления
+*/
+func Test_Rates_Refresh(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	first := testSource(t, `
+		<rates>
+			<item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out> 75.5 </out><amount>100000</amount></item>
+			<item><from>BTC</from><to>SBERRUB</to><in>1</in><out>3000000</out><amount>100000</amount></item>
+		</rates>`)
+	defer first.Close()
+
+	second := testSource(t, `
+		<rates>
+			<item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out>76</out><amount>100000</amount></item>
+		</rates>`)
+	defer second.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	store := mocksqlstore.Init()
+	redis := &testRedis{rates: map[string]string{}}
+	engine := InitEngine(store, redis, &testNsq{}, utils.InitLogger(store.AdminPanel().Logs())).(*Engine)
+
+	for name, url := range map[string]string{"first": first.URL, "second": second.URL, "broken": broken.URL} {
+		assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: name, UrlToParse: url}))
+	}
+
+	// Корректировка -1%
+	assert.NoError(t, store.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom:     "USDTTRC20",
+		ExchangeTo:       "SBERRUB",
+		CourseCorrection: -100,
+		Status:           true,
+	}))
+	// Котировок по направлению нет ни в одном источнике
+	assert.NoError(t, store.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom:     "ETH",
+		ExchangeTo:       "SBERRUB",
+		CourseCorrection: 100,
+		Status:           true,
+	}))
+	// Неактивное направление
+	assert.NoError(t, store.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom:     "BTC",
+		ExchangeTo:       "SBERRUB",
+		CourseCorrection: 100,
+		Status:           false,
+	}))
+
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+
+	r, err := engine.Get("USDTTRC20", "SBERRUB")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", r.Source)
+	assert.Equal(t, "76", r.SourceOut.String())
+	assert.Equal(t, "75.24", r.Out.String())
+	assert.Equal(t, "75.24", r.Course.String())
+	assert.Equal(t, 3, r.Position)
+	assert.Equal(t, 2, r.Competitors)
+
+	_, err = engine.Get("ETH", "SBERRUB")
+	assert.ErrorIs(t, err, AppError.ErrRateNotAvailable)
+
+	_, err = engine.Get("BTC", "SBERRUB")
+	assert.ErrorIs(t, err, AppError.ErrRateNotAvailable)
+
+	assert.Len(t, engine.All(), 1)
+	assert.Contains(t, redis.get("USDTTRC20", "SBERRUB"), `"course":75.24`)
+
+	// Источники недоступны, курс остается в течение срока актуальности
+	first.Close()
+	second.Close()
+
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+	assert.Len(t, engine.All(), 1)
+
+	cfg.Rates.TTL = 0
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+	assert.Empty(t, engine.All())
+}
+
+/*
+	Котировки всех источников сохраняются в историю курсов,
+	опорная котировка помечается отдельно
+*/
+func Test_Rates_History(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	first := testSource(t, `<rates><item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out>75</out><amount>1</amount></item></rates>`)
+	defer first.Close()
+
+	second := testSource(t, `<rates><item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out>76</out><amount>1</amount></item></rates>`)
+	defer second.Close()
+
+	store := mocksqlstore.Init()
+	engine := InitEngine(store, &testRedis{rates: map[string]string{}}, &testNsq{}, utils.InitLogger(store.AdminPanel().Logs()))
+
+	assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: "first", UrlToParse: first.URL}))
+	assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: "second", UrlToParse: second.URL}))
+
+	d := &models.Direction{ExchangeFrom: "USDTTRC20", ExchangeTo: "SBERRUB", Status: true}
+	assert.NoError(t, store.AdminPanel().Directions().Create(d))
+
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+
+	now := time.Now().UTC()
+	q := &models.RateHistorySelection{
+		DirectionID: d.ID,
+		Interval:    AppType.RateHistoryHour,
+		DateFrom:    now.Add(-time.Hour).Format(core.DateStandart),
+		DateTo:      now.Add(time.Hour).Format(core.DateStandart),
+	}
+
+	// Только опорные котировки
+	s, err := store.AdminPanel().RateHistory().Summary(q)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Count)
+	assert.Equal(t, "76", s.Course.Min.String())
+
+	// Все котировки одного источника
+	q.ExchangerID = 1
+	s, err = store.AdminPanel().RateHistory().Summary(q)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Count)
+	assert.Equal(t, "75", s.Source.Max.String())
+
+	points, err := store.AdminPanel().RateHistory().Series(q)
+	assert.NoError(t, err)
+	assert.Len(t, points, 1)
+
+	// Удаление устаревших записей
+	c, err := store.AdminPanel().RateHistory().DeleteBefore(now.Add(time.Hour).Format(core.DateStandart))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, c)
+}
+
+/*
+	Автоматическая корректировка удерживает направление на
+	целевом месте в пределах границ, заданных менеджером
+*/
+func Test_Rates_AutoPricing(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	arr := []*httptest.Server{}
+	for _, out := range []string{"100", "99", "98"} {
+		s := testSource(t, `<rates><item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out>`+out+`</out><amount>1</amount></item></rates>`)
+		defer s.Close()
+		arr = append(arr, s)
+	}
+
+	store := mocksqlstore.Init()
+	nsq := &testNsq{}
+	engine := InitEngine(store, &testRedis{rates: map[string]string{}}, nsq, utils.InitLogger(store.AdminPanel().Logs()))
+
+	for i, s := range arr {
+		assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: fmt.Sprintf("source-%d", i), UrlToParse: s.URL}))
+	}
+
+	hash := "hash"
+	assert.NoError(t, store.User().Create(&models.User{ChatID: 1, Username: "manager", Hash: &hash}))
+
+	d := &models.Direction{
+		ExchangeFrom:     "USDTTRC20",
+		ExchangeTo:       "SBERRUB",
+		CourseCorrection: -500,
+		Status:           true,
+		AutoPricing:      true,
+		TargetPosition:   2,
+		CorrectionMin:    -300,
+		CorrectionMax:    100,
+	}
+	assert.NoError(t, store.AdminPanel().Directions().Create(d))
+
+	// Курс должен быть выгоднее 99, но не выгоднее 100
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+
+	r, err := engine.Get("USDTTRC20", "SBERRUB")
+	assert.NoError(t, err)
+	assert.Equal(t, -99, r.CourseCorrection)
+	assert.Equal(t, "99.01", r.Out.String())
+	assert.Equal(t, 2, r.Position)
+
+	assert.NoError(t, store.AdminPanel().Directions().Get(d))
+	assert.Equal(t, -99, d.CourseCorrection)
+	assert.Len(t, nsq.messages(), 1)
+
+	// Корректировка не изменилась, уведомления не отправляются
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+	assert.Len(t, nsq.messages(), 1)
+
+	// Место недостижимо в пределах границ
+	d.TargetPosition, d.CorrectionMin = 4, -100
+	assert.NoError(t, store.AdminPanel().Directions().Update(d))
+	assert.NoError(t, engine.Refresh(ctx, &cfg.Rates))
+
+	r, err = engine.Get("USDTTRC20", "SBERRUB")
+	assert.NoError(t, err)
+	assert.Equal(t, -100, r.CourseCorrection)
+	assert.Equal(t, 2, r.Position)
+	assert.Len(t, nsq.messages(), 2)
+
+	// Менеджер изменил корректировку после чтения направления,
+	// автоматическая корректировка ее не перезаписывает
+	assert.NoError(t, store.AdminPanel().Directions().Get(d))
+	stale := *d
+	stale.CorrectionMin = -200
+	d.CourseCorrection = 50
+	assert.NoError(t, store.AdminPanel().Directions().Update(d))
+
+	ref := &quote{in: AppMoney.NewFromInt(1), out: AppMoney.NewFromInt(100)}
+	engine.(*Engine).autoPricing(&stale, ref, []*quote{ref})
+	assert.Equal(t, -100, stale.CourseCorrection)
+
+	assert.NoError(t, store.AdminPanel().Directions().Get(d))
+	assert.Equal(t, 50, d.CourseCorrection)
+	assert.Len(t, nsq.messages(), 2)
+
+	// Автоматическая корректировка может быть нулевой, направление
+	// с ней проходит проверку при сохранении менеджером
+	d.CorrectionMin, d.CorrectionMax = 0, 0
+	assert.NoError(t, store.AdminPanel().Directions().Update(d))
+
+	engine.(*Engine).autoPricing(d, ref, []*quote{ref})
+	assert.NoError(t, store.AdminPanel().Directions().Get(d))
+	assert.Zero(t, d.CourseCorrection)
+	assert.NoError(t, d.Validation())
+	assert.NoError(t, store.AdminPanel().Directions().Update(d))
+}
+
+/*
+	До завершения первого обновления курс берется из Redis
+*/
+func Test_Rates_GetFromRedis(t *testing.T) {
+	store := mocksqlstore.Init()
+	redis := &testRedis{rates: map[string]string{}}
+	engine := InitEngine(store, redis, &testNsq{}, utils.InitLogger(store.AdminPanel().Logs()))
+
+	assert.NoError(t, redis.SaveRate("USDTTRC20", "SBERRUB", []byte(`{"exchange_from":"USDTTRC20","exchange_to":"SBERRUB","course":75.24}`), time.Minute))
+
+	r, err := engine.Get("USDTTRC20", "SBERRUB")
+	assert.NoError(t, err)
+	assert.Equal(t, "75.24", r.Course.String())
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func testSource(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, body)
+	}))
+}
+
+type testNsqMessage struct {
+	topic   string
+	payload []byte
+}
+
+// Имитация NSQ, сохраняющая все отправленные сообщения
+type testNsq struct {
+	mu  sync.Mutex
+	arr []testNsqMessage
+}
+
+func (n *testNsq) Publish(topic string, payload []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.arr = append(n.arr, testNsqMessage{topic: topic, payload: payload})
+	return nil
+}
+
+func (n *testNsq) messages() []testNsqMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]testNsqMessage{}, n.arr...)
+}
+
+// Имитация Redis хранилища курсов
+type testRedis struct {
+	mu    sync.Mutex
+	rates map[string]string
+}
+
+func (r *testRedis) SaveRate(from, to string, body []byte, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rates[key(from, to)] = string(body)
+	return nil
+}
+
+func (r *testRedis) FetchRate(from, to string) (string, error) {
+	if body := r.get(from, to); body != "" {
+		return body, nil
+	}
+
+	return "", fmt.Errorf("redis: nil")
+}
+
+func (r *testRedis) Close() error { return nil }
+
+func (r *testRedis) Clear() {}
+
+func (r *testRedis) get(from, to string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rates[key(from, to)]
+}
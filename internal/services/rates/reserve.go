@@ -0,0 +1,130 @@
+package rates
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Расчет резерва направлений обмена.
+//
+// Резерв задается менеджером вручную либо рассчитывается
+// как доступный баланс привязанных к направлению аккаунтов
+// автовыплат в валюте ExchangeTo.
+type Reserve struct {
+	store  db.SQLStoreI
+	plugin *plugins.AppPlugins
+	logger utils.LoggerI
+}
+
+type ReserveI interface {
+	Get(ctx context.Context, d *models.Direction) (AppMoney.Money, error)
+	Fill(ctx context.Context, d *models.Direction) error
+}
+
+func InitReserve(s db.SQLStoreI, p *plugins.AppPlugins, l utils.LoggerI) ReserveI {
+	return &Reserve{
+		store:  s,
+		plugin: p,
+		logger: l,
+	}
+}
+
+// Метод возвращает резерв направления d. Если резерв задан
+// вручную, возвращается значение менеджера, иначе резерв
+// рассчитывается по балансам аккаунтов автовыплат. Недоступный
+// аккаунт автовыплат не учитывается в резерве. Если способ
+// задания резерва не выбран, к бирже метод не обращается.
+func (r *Reserve) Get(ctx context.Context, d *models.Direction) (AppMoney.Money, error) {
+	switch d.ReserveType {
+	case "":
+		return AppMoney.Zero, nil
+	case AppType.ReserveManual:
+		return d.Reserve, nil
+	}
+
+	currency := &models.Currency{Code: d.ExchangeTo}
+	if err := r.store.AdminPanel().Currency().GetByCode(currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AppMoney.Zero, fmt.Errorf("%w | currency: %s", AppError.ErrCurrencyNotFound, d.ExchangeTo)
+		}
+		return AppMoney.Zero, err
+	}
+
+	accounts, err := r.store.AdminPanel().Directions().Ma().GetActiveAccounts(d.ID, AppType.UseAsAutoPayout)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	reserve := AppMoney.Zero
+	for _, ma := range accounts {
+		// Баланс запрашивается по тикеру валюты у платежной системы
+		available, err := r.balance(ctx, ma, currency.Plugin().Provider(ma.Service).Ticker)
+		if err != nil {
+			r.logger.NewRecord(&models.LogRecord{
+				Service: AppType.LogTypeServer,
+				Module:  AppType.LogModuleRates,
+				Info:    fmt.Sprintf("reserve %s/%s | account %d: %s", d.ExchangeFrom, d.ExchangeTo, ma.ID, err.Error()),
+			})
+			continue
+		}
+
+		reserve = reserve.Add(available)
+	}
+
+	return reserve, nil
+}
+
+// Метод записывает в d.Reserve текущий резерв направления
+func (r *Reserve) Fill(ctx context.Context, d *models.Direction) error {
+	if d.ReserveType == "" {
+		return nil
+	}
+
+	reserve, err := r.Get(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	d.Reserve = reserve.Round(d.ExchangeTo)
+	return nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (r *Reserve) balance(ctx context.Context, ma *models.MerchantAutopayout, ticker string) (AppMoney.Money, error) {
+	plugin, err := r.plugin.Get(ma.Service)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	params, err := plugin.GetOptionParams(ma.Options)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	balances, err := plugin.Balance(ctx, params, ticker)
+	if err != nil {
+		return AppMoney.Zero, err
+	}
+
+	available := AppMoney.Zero
+	for _, b := range balances {
+		available = available.Add(b.Available)
+	}
+
+	return available, nil
+}
@@ -0,0 +1,69 @@
+package rates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit/whitebittest"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Резерв задается вручную либо берется с аккаунтов автовыплат,
+	заявки проверяются по границам суммы и резерву направления
+*/
+func Test_Rates_Reserve(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	wb := whitebittest.NewServer()
+	defer wb.Close()
+	wb.SetBalance("USDT", "250.5")
+
+	store := mocksqlstore.Init()
+	logger := utils.InitLogger(store.AdminPanel().Logs())
+	reserve := InitReserve(store, plugins.InitAppPlugins(&cfg.Plugins), logger)
+
+	autopayout := testAccount(t, store, cfg, AppType.UseAsAutoPayout, true, wb.Account("payout-public", "payout-secret"))
+
+	// Код валюты каталога отличается от тикера на бирже
+	d := testDirection(t, store, "BTC", "USDTTRC20")
+	testBinding(t, store, d, autopayout, AppType.UseAsAutoPayout)
+
+	// Без способа задания резерв не рассчитывается и не ограничивает заявки
+	assert.NoError(t, reserve.Fill(ctx, d))
+	assert.Equal(t, 0, wb.Requests(whitebittest.PathBalance))
+	assert.NoError(t, d.CheckReserve(AppMoney.RequireFromString("1000000")))
+
+	// Резерв по балансу аккаунта автовыплат
+	d.ReserveType = AppType.ReserveAuto
+	assert.NoError(t, reserve.Fill(ctx, d))
+	assert.Equal(t, "250.5", d.Reserve.String())
+	assert.NoError(t, d.CheckReserve(AppMoney.RequireFromString("250.5")))
+	assert.ErrorIs(t, d.CheckReserve(AppMoney.RequireFromString("250.6")), AppError.ErrNotEnoughReserve)
+
+	// Ручной резерв не обращается к бирже
+	requests := wb.Requests(whitebittest.PathBalance)
+	d.ReserveType, d.Reserve = AppType.ReserveManual, AppMoney.RequireFromString("100")
+	assert.NoError(t, reserve.Fill(ctx, d))
+	assert.Equal(t, "100", d.Reserve.String())
+	assert.Equal(t, requests, wb.Requests(whitebittest.PathBalance))
+
+	// Границы суммы заявки
+	d.CourseCorrection = 100
+	d.MinAmount, d.MaxAmount = AppMoney.RequireFromString("0.001"), AppMoney.RequireFromString("1")
+	assert.NoError(t, d.Validation())
+	assert.NoError(t, d.CheckAmount(AppMoney.RequireFromString("0.001")))
+	assert.ErrorIs(t, d.CheckAmount(AppMoney.RequireFromString("0.0009")), AppError.ErrAmountBelowMinimum)
+	assert.ErrorIs(t, d.CheckAmount(AppMoney.RequireFromString("1.1")), AppError.ErrAmountAboveMaximum)
+
+	d.MinAmount = AppMoney.RequireFromString("2")
+	assert.Error(t, d.Validation())
+}
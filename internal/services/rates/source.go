@@ -0,0 +1,44 @@
+package rates
+
+import (
+	"context"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/feeds"
+)
+
+// Котировка направления в одном источнике
+type quote struct {
+	exchangerID int
+	source      string
+	in          AppMoney.Money
+	out         AppMoney.Money
+}
+
+// Метод загружает и разбирает выгрузку курсов источника ex
+func (e *Engine) fetch(ctx context.Context, cfg *config.RatesConfig, ex *models.Exchanger) ([]models.OneObmenItem, error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	return feeds.FetchExchanger(ctx, e.client, ex)
+}
+
+// Функция выбирает опорную котировку - самую выгодную для
+// клиента, то есть с наибольшей выдачей за единицу отдаваемой валюты
+func reference(quotes []*quote) *quote {
+	var best *quote
+	for _, q := range quotes {
+		// out/in > best.out/best.in без деления
+		if best == nil || q.out.Mul(best.in.Decimal).GreaterThan(best.out.Mul(q.in.Decimal).Decimal) {
+			best = q
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,31 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/feeds"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Отчет по выгрузке курсов источника из таблицы `exchangers`
+func (r *Reports) exchangerRates(ctx context.Context, rj *models.ReportJob, f string) error {
+	p := &models.ExchangerRatesReportParams{}
+	if err := json.Unmarshal(rj.Params, p); err != nil {
+		return err
+	}
+
+	ex := &models.Exchanger{Name: p.Exchanger}
+	if err := r.store.AdminPanel().Exchanger().GetByName(ex); err != nil {
+		return fmt.Errorf("exchanger %s: %w", p.Exchanger, err)
+	}
+
+	arr, err := feeds.FetchExchanger(ctx, r.client, ex)
+	if err != nil {
+		return err
+	}
+
+	return utils.OneObmenDocumentGenerate(&models.OneObmen{Rates: arr}, f)
+}
@@ -0,0 +1,267 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/supervisor"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Максимальное кол-во задач, ожидающих формирования
+const queueSize = 100
+
+// Процесс формирования отчетов.
+//
+// Задачи сохраняются в таблицу `report_jobs` и формируются в фоне
+// несколькими обработчиками, готовые файлы складываются в каталог
+// dir и удаляются по истечении срока хранения.
+type Reports struct {
+	store  db.SQLStoreI
+	client *http.Client
+	logger utils.LoggerI
+	dir    string
+
+	queue      chan int
+	generators map[string]generator
+
+	supervisor supervisor.SupervisorI
+}
+
+type ReportsI interface {
+	Enqueue(rj *models.ReportJob) error
+	Supervise(ctx context.Context, cfg *config.ReportsConfig)
+	Process(ctx context.Context, cfg *config.ReportsConfig, id int) error
+	Cleanup(cfg *config.ReportsConfig) error
+}
+
+// Функция формирует отчет задачи rj в файле f
+type generator func(ctx context.Context, rj *models.ReportJob, f string) error
+
+func InitReports(s db.SQLStoreI, dir string, l utils.LoggerI) ReportsI {
+	r := &Reports{
+		store:  s,
+		client: &http.Client{},
+		logger: l,
+		dir:    dir,
+
+		queue:      make(chan int, queueSize),
+		supervisor: supervisor.Init(AppType.LogModuleReports, 0, 0, l),
+	}
+
+	r.generators = map[string]generator{
+		AppType.ReportTypeExchangerRates: r.exchangerRates,
+	}
+
+	return r
+}
+
+// Метод создает задачу и ставит ее в очередь на формирование
+func (r *Reports) Enqueue(rj *models.ReportJob) error {
+	if err := r.store.AdminPanel().ReportJob().Create(rj); err != nil {
+		return err
+	}
+
+	select {
+	case r.queue <- rj.ID:
+		return nil
+	default:
+		rj.Status, rj.Error = AppType.ReportStatusFailed, AppError.ErrReportQueueFull.Error()
+		if err := r.store.AdminPanel().ReportJob().Update(rj); err != nil {
+			return err
+		}
+
+		return AppError.ErrReportQueueFull
+	}
+}
+
+// Метод запускает обработчиков очереди и периодическую очистку
+// устаревших отчетов под наблюдением супервизора. Задачи, не
+// завершенные до перезапуска сервера, ставятся в очередь заново.
+// Блокирует выполнение до отмены контекста ctx.
+func (r *Reports) Supervise(ctx context.Context, cfg *config.ReportsConfig) {
+	r.restore()
+
+	r.supervisor.Run(ctx, func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		workers := cfg.Workers
+		if workers < 1 {
+			workers = 1
+		}
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.work(ctx, cfg)
+			}()
+		}
+
+		for {
+			t := time.NewTimer(time.Duration(cfg.CleanupInterval) * time.Second)
+
+			if err := r.Cleanup(cfg); err != nil {
+				t.Stop()
+				return err
+			}
+			r.supervisor.Tick()
+
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil
+			case <-t.C:
+			}
+		}
+	})
+}
+
+// Формирование отчета по задаче id. Ошибка формирования
+// сохраняется в задаче, наружу возвращаются только ошибки БД.
+func (r *Reports) Process(ctx context.Context, cfg *config.ReportsConfig, id int) error {
+	rj := &models.ReportJob{ID: id}
+	if err := r.store.AdminPanel().ReportJob().Get(rj); err != nil {
+		return err
+	}
+
+	if rj.Status != AppType.ReportStatusPending {
+		return nil
+	}
+
+	rj.Status = AppType.ReportStatusRunning
+	if err := r.store.AdminPanel().ReportJob().Update(rj); err != nil {
+		return err
+	}
+
+	gctx := ctx
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		gctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	f := filepath.Join(r.dir, fmt.Sprintf("%d.xlsx", rj.ID))
+	if err := r.generate(gctx, rj, f); err != nil {
+		os.Remove(f)
+
+		// Формирование прервано остановкой сервера, задача
+		// останется в статусе ReportStatusRunning и будет
+		// поставлена в очередь заново при следующем запуске
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		rj.Status, rj.Error = AppType.ReportStatusFailed, err.Error()
+	} else {
+		rj.Status, rj.File = AppType.ReportStatusDone, f
+	}
+
+	return r.store.AdminPanel().ReportJob().Update(rj)
+}
+
+// Удаление файлов отчетов, срок хранения которых истек
+func (r *Reports) Cleanup(cfg *config.ReportsConfig) error {
+	before := time.Now().UTC().Add(-time.Duration(cfg.Retention) * time.Hour).Format(core.DateStandart)
+
+	arr, err := r.store.AdminPanel().ReportJob().GetAllExpired(before)
+	if err != nil {
+		return err
+	}
+
+	for _, rj := range arr {
+		if err := os.Remove(rj.File); err != nil && !os.IsNotExist(err) {
+			r.log(fmt.Sprintf("report %d: %s", rj.ID, err.Error()))
+			continue
+		}
+
+		rj.Status, rj.File = AppType.ReportStatusExpired, ""
+		if err := r.store.AdminPanel().ReportJob().Update(rj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Обработчик очереди задач
+func (r *Reports) work(ctx context.Context, cfg *config.ReportsConfig) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-r.queue:
+			if err := r.Process(ctx, cfg, id); err != nil {
+				r.log(fmt.Sprintf("report %d: %s", id, err.Error()))
+			}
+		}
+	}
+}
+
+func (r *Reports) generate(ctx context.Context, rj *models.ReportJob, f string) error {
+	g, ok := r.generators[rj.Type]
+	if !ok {
+		return fmt.Errorf("unknown report type %s", rj.Type)
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+
+	return g(ctx, rj, f)
+}
+
+// Метод возвращает в очередь задачи, формирование
+// которых было прервано остановкой сервера
+func (r *Reports) restore() {
+	arr, err := r.store.AdminPanel().ReportJob().GetAllUnfinished()
+	if err != nil {
+		r.log(err.Error())
+		return
+	}
+
+	for _, rj := range arr {
+		if rj.Status == AppType.ReportStatusRunning {
+			rj.Status = AppType.ReportStatusPending
+			if err := r.store.AdminPanel().ReportJob().Update(rj); err != nil {
+				r.log(fmt.Sprintf("report %d: %s", rj.ID, err.Error()))
+				continue
+			}
+		}
+
+		select {
+		case r.queue <- rj.ID:
+		default:
+			return
+		}
+	}
+}
+
+func (r *Reports) log(info string) {
+	r.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  AppType.LogModuleReports,
+		Info:    info,
+	})
+}
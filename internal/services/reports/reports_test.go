@@ -0,0 +1,155 @@
+package reports_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Задача формируется в фоне, готовый файл удаляется
+	по истечении срока хранения, метаданные задачи остаются
+*/
+func Test_Reports_Process(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.InitTestConfig(t)
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<rates><item><from>USDTTRC20</from><to>SBERRUB</to><in>1</in><out>75.5</out><amount>100000</amount></item></rates>`)
+	}))
+	defer source.Close()
+
+	store := mocksqlstore.Init()
+	assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: "1obmen", UrlToParse: source.URL}))
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	rp := reports.InitReports(store, dir, utils.InitLogger(store.AdminPanel().Logs()))
+
+	rj := testJob(t, "1obmen")
+	assert.NoError(t, rp.Enqueue(rj))
+	assert.Equal(t, AppType.ReportStatusPending, rj.Status)
+
+	assert.NoError(t, rp.Process(ctx, &cfg.Reports, rj.ID))
+	assert.NoError(t, store.AdminPanel().ReportJob().Get(rj))
+	assert.Equal(t, AppType.ReportStatusDone, rj.Status)
+	assert.Empty(t, rj.Error)
+	assert.FileExists(t, rj.File)
+
+	// Повторная обработка готовой задачи ничего не меняет
+	assert.NoError(t, rp.Process(ctx, &cfg.Reports, rj.ID))
+
+	// Срок хранения еще не истек
+	assert.NoError(t, rp.Cleanup(&cfg.Reports))
+	assert.FileExists(t, rj.File)
+
+	cfg.Reports.Retention = -1
+	assert.NoError(t, rp.Cleanup(&cfg.Reports))
+
+	f := rj.File
+	assert.NoError(t, store.AdminPanel().ReportJob().Get(rj))
+	assert.Equal(t, AppType.ReportStatusExpired, rj.Status)
+	assert.Empty(t, rj.File)
+
+	_, err := os.Stat(f)
+	assert.True(t, os.IsNotExist(err))
+}
+
+/*
+	Ошибка формирования сохраняется в задаче
+*/
+func Test_Reports_Failed(t *testing.T) {
+	cfg := config.InitTestConfig(t)
+
+	store := mocksqlstore.Init()
+	rp := reports.InitReports(store, t.TempDir(), utils.InitLogger(store.AdminPanel().Logs()))
+
+	rj := testJob(t, "undefined")
+	assert.NoError(t, rp.Enqueue(rj))
+	assert.NoError(t, rp.Process(context.Background(), &cfg.Reports, rj.ID))
+
+	assert.NoError(t, store.AdminPanel().ReportJob().Get(rj))
+	assert.Equal(t, AppType.ReportStatusFailed, rj.Status)
+	assert.Contains(t, rj.Error, "undefined")
+	assert.Empty(t, rj.File)
+}
+
+/*
+	Задачи из очереди обрабатываются запущенными обработчиками,
+	прерванные остановкой сервера задачи ставятся в очередь заново
+*/
+func Test_Reports_Supervise(t *testing.T) {
+	cfg := config.InitTestConfig(t)
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rates></rates>`)
+	}))
+	defer source.Close()
+
+	store := mocksqlstore.Init()
+	assert.NoError(t, store.AdminPanel().Exchanger().Create(&models.Exchanger{Name: "1obmen", UrlToParse: source.URL}))
+
+	// Задача, формирование которой прервал перезапуск
+	interrupted := testJob(t, "1obmen")
+	assert.NoError(t, store.AdminPanel().ReportJob().Create(interrupted))
+	interrupted.Status = AppType.ReportStatusRunning
+	assert.NoError(t, store.AdminPanel().ReportJob().Update(interrupted))
+
+	rp := reports.InitReports(store, t.TempDir(), utils.InitLogger(store.AdminPanel().Logs()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rp.Supervise(ctx, &cfg.Reports)
+	}()
+
+	rj := testJob(t, "1obmen")
+	assert.NoError(t, rp.Enqueue(rj))
+
+	for _, id := range []int{interrupted.ID, rj.ID} {
+		assert.Eventually(t, func() bool {
+			j := &models.ReportJob{ID: id}
+			return store.AdminPanel().ReportJob().Get(j) == nil && j.Ready()
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
+
+/*
+	Параметры отчета проверяются до постановки в очередь
+*/
+func Test_Reports_Validation(t *testing.T) {
+	assert.NoError(t, testJob(t, "1obmen").Validation())
+	assert.Error(t, testJob(t, "").Validation())
+	assert.Error(t, (&models.ReportJob{Type: "undefined"}).Validation())
+
+	rj := &models.ReportJob{Type: AppType.ReportTypeExchangerRates, Params: []byte(`[]`)}
+	assert.Contains(t, rj.Validation().Error(), AppError.ErrValidationInvalidReportParams.Error())
+}
+
+func testJob(t *testing.T, exchanger string) *models.ReportJob {
+	t.Helper()
+
+	params, err := json.Marshal(&models.ExchangerRatesReportParams{Exchanger: exchanger})
+	assert.NoError(t, err)
+
+	return &models.ReportJob{Type: AppType.ReportTypeExchangerRates, Params: params}
+}
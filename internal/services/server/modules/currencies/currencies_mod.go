@@ -0,0 +1,35 @@
+package currencies
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModCurrencies struct {
+	repository db.CurrencyRepository
+	cfg        *config.Config
+
+	responser utils.ResponserI
+}
+
+type ModCurrenciesI interface {
+	CreateCurrencyHandler(c *gin.Context)
+	UpdateCurrencyHandler(c *gin.Context)
+	DeleteCurrencyHandler(c *gin.Context)
+	GetCurrencyHandler(c *gin.Context)
+	GetCurrenciesSelectionHandler(c *gin.Context)
+}
+
+func InitModCurrencies(
+	r db.CurrencyRepository,
+	cfg *config.Config,
+	responser utils.ResponserI,
+) ModCurrenciesI {
+	return &ModCurrencies{
+		repository: r,
+		cfg:        cfg,
+		responser:  responser,
+	}
+}
@@ -0,0 +1,76 @@
+package currencies
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method POST
+	@Path admin/currency
+	@Type PRIVATE
+	@Documentation
+
+	Создать запись в таблице `currencies`
+
+	# TESTED
+*/
+func (m *ModCurrencies) CreateCurrencyHandler(c *gin.Context) {
+	m.CurrencyHandler(c)
+}
+
+/*
+	@Method PUT
+	@Path admin/currency/:id
+	@Type PRIVATE
+	@Documentation
+
+	Обновить запись в таблице `currencies`. Код валюты не меняется
+
+	# TESTED
+*/
+func (m *ModCurrencies) UpdateCurrencyHandler(c *gin.Context) {
+	m.CurrencyHandler(c)
+}
+
+/*
+	@Method DELETE
+	@Path admin/currency/:id
+	@Type PRIVATE
+	@Documentation
+
+	Удалить запись из таблицы `currencies`
+
+	# TESTED
+*/
+func (m *ModCurrencies) DeleteCurrencyHandler(c *gin.Context) {
+	m.CurrencyHandler(c)
+}
+
+/*
+	@Method GET
+	@Path admin/currency/:id
+	@Type PRIVATE
+	@Documentation
+
+	Получить запись из таблицы `currencies`
+
+	# TESTED
+*/
+func (m *ModCurrencies) GetCurrencyHandler(c *gin.Context) {
+	m.CurrencyHandler(c)
+}
+
+/*
+	@Method GET
+	@Path admin/currencies
+	@Type PRIVATE
+	@Documentation
+
+	Получение лимитированного объема записей из таблицы `currencies`
+
+	# TESTED
+*/
+func (m *ModCurrencies) GetCurrenciesSelectionHandler(c *gin.Context) {
+	m.responser.SelectionResponse(c, m.repository, &models.CurrencySelection{})
+}
@@ -0,0 +1,120 @@
+package currencies_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Server_CurrencyHandlers(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "invalid code",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/currency",
+			body:         `{"code":"usdt-bep20","ticker":"USDT","decimals":6}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "unknown provider",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/currency",
+			body:         `{"code":"USDTBEP20","ticker":"USDT","decimals":6,"provider_codes":{"undefined":{"network":"BSC"}}}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "minimum exceeds decimals",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/currency",
+			body:         `{"code":"USDTBEP20","ticker":"USDT","decimals":2,"min_deposit":"0.001"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "valid",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/currency",
+			body:         `{"code":"USDTBEP20","ticker":"USDT","network":"BEP20","decimals":6,"min_deposit":"10","provider_codes":{"whitebit":{"network":"BSC"}}}`,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "already exists",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/currency",
+			body:         `{"code":"USDTBEP20","ticker":"USDT","decimals":6}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "update",
+			method:       http.MethodPut,
+			path:         "/api/v1/admin/currency/9",
+			body:         `{"code":"USDTBEP20","ticker":"USDT","network":"BEP20","decimals":8}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/currency/9",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "selection",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/currencies?page=1&limit=5",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "delete",
+			method:       http.MethodDelete,
+			path:         "/api/v1/admin/currency/9",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "not found",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/currency/9",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid id",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/currency/id",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			// Точность сумм в валюте задается каталогом
+			if tc.name == "update" {
+				assert.Equal(t, int32(8), AppMoney.Precision("USDTBEP20"))
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package directions
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"reflect"
+	"strconv"
 
 	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
@@ -12,6 +15,12 @@ import (
 func (m *ModDirections) DirectionHandler(c *gin.Context) {
 	var r models.Direction
 	if c.Request.ContentLength > 0 {
+		// При обновлении тело запроса накладывается на сохраненное
+		// направление, поля, которых нет в теле, не сбрасываются
+		if c.Request.Method == http.MethodPut && !m.storedDirection(c, &r) {
+			return
+		}
+
 		if err := c.ShouldBindJSON(&r); err != nil {
 			m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidBody)
 			return
@@ -21,6 +30,19 @@ func (m *ModDirections) DirectionHandler(c *gin.Context) {
 			m.responser.Error(c, http.StatusUnprocessableEntity, err)
 			return
 		}
+
+		// Обе валюты направления должны быть в каталоге
+		for _, code := range []string{r.ExchangeFrom, r.ExchangeTo} {
+			if err := m.repository.Currency().GetByCode(&models.Currency{Code: code}); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrCurrencyNotFound)
+					return
+				}
+
+				m.responser.Error(c, http.StatusInternalServerError, err)
+				return
+			}
+		}
 	}
 
 	if obj := m.responser.RecordHandler(c, &r); obj != nil {
@@ -33,7 +55,14 @@ func (m *ModDirections) DirectionHandler(c *gin.Context) {
 			m.responser.CreateRecordResponse(c, m.repository.Directions(), obj)
 			return
 		case http.MethodGet:
-			m.responser.GetRecordResponse(c, m.repository.Directions(), obj)
+			// Резерв направления рассчитывается в момент запроса
+			d := obj.(*models.Direction)
+			err := m.repository.Directions().Get(d)
+			if err == nil {
+				err = m.reserve.Fill(c.Request.Context(), d)
+			}
+
+			m.responser.RecordResponse(c, d, err)
 			return
 		case http.MethodPut:
 			m.responser.UpdateRecordResponse(c, m.repository.Directions(), obj)
@@ -47,6 +76,24 @@ func (m *ModDirections) DirectionHandler(c *gin.Context) {
 	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
 }
 
+// Метод загружает направление из пути запроса в d. При ошибке
+// отправляет ответ и возвращает false.
+func (m *ModDirections) storedDirection(c *gin.Context, d *models.Direction) bool {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidPathParams)
+		return false
+	}
+
+	d.ID = id
+	if err := m.repository.Directions().Get(d); err != nil {
+		m.responser.RecordResponse(c, nil, err)
+		return false
+	}
+
+	return true
+}
+
 func (m *ModDirections) DirectionMaHandler(c *gin.Context) {
 	var r models.DirectionMA
 	if c.Request.ContentLength > 0 {
@@ -3,12 +3,14 @@ package directions
 import (
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type ModDirections struct {
 	repository db.AdminPanelRepository
+	reserve    rates.ReserveI
 	cfg        *config.Config
 
 	responser utils.ResponserI
@@ -26,15 +28,19 @@ type ModDirectionsI interface {
 	DeleteDirectionMaHandler(c *gin.Context)
 	GetDirectionMaHandler(c *gin.Context)
 	DirectionMaSelectionHandler(c *gin.Context)
+
+	GetDirectionRateHistoryHandler(c *gin.Context)
 }
 
 func InitModDirections(
 	r db.AdminPanelRepository,
+	rs rates.ReserveI,
 	cfg *config.Config,
 	responser utils.ResponserI,
 ) ModDirectionsI {
 	return &ModDirections{
 		repository: r,
+		reserve:    rs,
 		cfg:        cfg,
 		responser:  responser,
 	}
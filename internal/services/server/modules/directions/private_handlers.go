@@ -1,10 +1,16 @@
 package directions
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gin-gonic/gin"
 )
@@ -66,3 +72,73 @@ func (m *ModDirections) DirectionMaSelectionHandler(c *gin.Context) {
 	fmt.Println(111)
 	m.responser.SelectionResponse(c, m.repository.Directions().Ma(), s)
 }
+
+/*
+	@Method GET
+	@Path admin/direction/rate-history/:id
+	@Type PRIVATE
+	@Documentation
+
+	История курса направления: временной ряд с шагом interval
+	(minute, hour, day) и сводка min/max/avg за период [from, to).
+	По умолчанию возвращается опорный курс за последние сутки
+	с шагом в час, параметр exchanger задает конкретный источник.
+
+	# TESTED
+*/
+func (m *ModDirections) GetDirectionRateHistoryHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidPathParams)
+		return
+	}
+
+	now := time.Now().UTC()
+	s := &models.RateHistorySelection{
+		DirectionID: id,
+		Interval:    c.DefaultQuery("interval", AppType.RateHistoryHour),
+		DateFrom:    c.DefaultQuery("from", now.Add(-24*time.Hour).Format(core.DateStandart)),
+		DateTo:      c.DefaultQuery("to", now.Format(core.DateStandart)),
+	}
+
+	if c.Query("exchanger") != "" {
+		exchangerID, err := strconv.Atoi(c.Query("exchanger"))
+		if err != nil {
+			m.responser.Error(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		s.ExchangerID = exchangerID
+	}
+
+	if err := s.Validation(); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if err := m.repository.Directions().Get(&models.Direction{ID: id}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
+			return
+		}
+
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	points, err := m.repository.RateHistory().Series(s)
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	summary, err := m.repository.RateHistory().Summary(s)
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"points":  points,
+		"summary": summary,
+	})
+}
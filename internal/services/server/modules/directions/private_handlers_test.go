@@ -0,0 +1,214 @@
+package directions_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Обновление направления изменяет только поля из тела запроса,
+	остальные настройки направления сохраняются
+*/
+func Test_Server_UpdateDirectionHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "create",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/direction",
+			body:         `{"exchange_from":"USDTTRC20","exchange_to":"SBERRUB","course_correction":10,"status":true,"tolerance_type":"percent","tolerance":"1.5","min_amount":"10","max_amount":"1000","reserve_type":"manual","reserve":"5000","merchant_policy":"weighted","autopayout_policy":"balance"}`,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "partial update",
+			method:       http.MethodPut,
+			path:         "/api/v1/admin/direction/1",
+			body:         `{"course_correction":0}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid update",
+			method:       http.MethodPut,
+			path:         "/api/v1/admin/direction/1",
+			body:         `{"tolerance":"-1"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "not found",
+			method:       http.MethodPut,
+			path:         "/api/v1/admin/direction/2",
+			body:         `{"course_correction":0}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/direction/1",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.name == "get" {
+				d := &models.Direction{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(d))
+				assert.Equal(t, 0, d.CourseCorrection)
+				assert.Equal(t, "percent", d.ToleranceType)
+				assert.Equal(t, "1.5", d.Tolerance.String())
+				assert.Equal(t, "10", d.MinAmount.String())
+				assert.Equal(t, "1000", d.MaxAmount.String())
+				assert.Equal(t, "manual", d.ReserveType)
+				assert.Equal(t, "5000", d.Reserve.String())
+				assert.Equal(t, "weighted", d.MerchantPolicy)
+				assert.Equal(t, "balance", d.AutopayoutPolicy)
+			}
+		})
+	}
+}
+
+/*
+	История курса направления прореживается по шагу interval
+	в пределах периода, слишком частый шаг и неизвестное
+	направление отклоняются
+*/
+func Test_Server_GetDirectionRateHistoryHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/direction", bytes.NewBufferString(`{"exchange_from":"USDTTRC20","exchange_to":"SBERRUB","status":true}`))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+	s.Router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	arr := []*models.RateHistory{}
+	for i, v := range []struct {
+		createdAt   string
+		exchangerID int
+		reference   bool
+	}{
+		{"2022-03-01T10:05:00.00000000", 1, true},
+		{"2022-03-01T10:40:00.00000000", 1, true},
+		{"2022-03-01T12:00:00.00000000", 1, true},
+		{"2022-03-01T12:00:00.00000000", 2, false},
+	} {
+		arr = append(arr, &models.RateHistory{
+			DirectionID: 1,
+			ExchangerID: v.exchangerID,
+			SourceIn:    AppMoney.NewFromInt(1),
+			SourceOut:   AppMoney.NewFromInt(int64(100 + i)),
+			In:          AppMoney.NewFromInt(1),
+			Out:         AppMoney.NewFromInt(int64(100 + i)),
+			Reference:   v.reference,
+			CreatedAt:   v.createdAt,
+		})
+	}
+	assert.NoError(t, server.TestRateHistory(t, s, arr))
+
+	period := "from=2022-03-01T00:00:00.00000000&to=2022-03-02T00:00:00.00000000"
+	testCases := []struct {
+		name           string
+		path           string
+		expectedCode   int
+		expectedPoints int
+		expectedCount  int
+	}{
+		{
+			name:           "hour",
+			path:           "/api/v1/admin/direction/rate-history/1?interval=hour&" + period,
+			expectedCode:   http.StatusOK,
+			expectedPoints: 2,
+			expectedCount:  3,
+		},
+		{
+			name:           "day",
+			path:           "/api/v1/admin/direction/rate-history/1?interval=day&" + period,
+			expectedCode:   http.StatusOK,
+			expectedPoints: 1,
+			expectedCount:  3,
+		},
+		{
+			name:           "exchanger",
+			path:           "/api/v1/admin/direction/rate-history/1?interval=hour&exchanger=2&" + period,
+			expectedCode:   http.StatusOK,
+			expectedPoints: 1,
+			expectedCount:  1,
+		},
+		{
+			name:         "too many points",
+			path:         "/api/v1/admin/direction/rate-history/1?interval=minute&from=2022-03-01T00:00:00.00000000&to=2022-03-03T00:00:00.00000000",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "empty period",
+			path:         "/api/v1/admin/direction/rate-history/1?interval=hour&from=2022-03-02T00:00:00.00000000&to=2022-03-01T00:00:00.00000000",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "unknown direction",
+			path:         "/api/v1/admin/direction/rate-history/2?interval=hour&" + period,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid id",
+			path:         "/api/v1/admin/direction/rate-history/id",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var body struct {
+					Points  []*models.RateHistoryPoint `json:"points"`
+					Summary *models.RateHistoryStats   `json:"summary"`
+				}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Len(t, body.Points, tc.expectedPoints)
+				assert.Equal(t, tc.expectedCount, body.Summary.Count)
+			}
+		})
+	}
+}
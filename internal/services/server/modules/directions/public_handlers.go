@@ -2,6 +2,16 @@ package directions
 
 import "github.com/gin-gonic/gin"
 
+/*
+	@Method GET
+	@Path admin/direction/:id
+	@Type PUBLIC
+	@Documentation
+
+	Направление обмена с границами суммы заявки и текущим
+	резервом. Для резерва, рассчитываемого по балансам аккаунтов
+	автовыплат, значение получается в момент запроса.
+*/
 func (m *ModDirections) GetDirectionHandler(c *gin.Context) {
 	m.DirectionHandler(c)
 }
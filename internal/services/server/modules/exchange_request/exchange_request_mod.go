@@ -0,0 +1,34 @@
+package exchange_request
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModExchangeRequest struct {
+	repository db.AdminPanelRepository
+	cfg        *config.Config
+
+	responser utils.ResponserI
+}
+
+type ModExchangeRequestI interface {
+	GetExchangeRequestHandler(c *gin.Context)
+	GetExchangeRequestsSelectionHandler(c *gin.Context)
+	UpdateExchangeRequestStatusHandler(c *gin.Context)
+	CancelExchangeRequestHandler(c *gin.Context)
+}
+
+func InitModExchangeRequest(
+	r db.AdminPanelRepository,
+	cfg *config.Config,
+	responser utils.ResponserI,
+) ModExchangeRequestI {
+	return &ModExchangeRequest{
+		repository: r,
+		cfg:        cfg,
+		responser:  responser,
+	}
+}
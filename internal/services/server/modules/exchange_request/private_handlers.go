@@ -0,0 +1,182 @@
+package exchange_request
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/guard"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method GET
+	@Path admin/exchange-request/:id
+	@Type PRIVATE
+	@Documentation
+
+	Получить заявку вместе с историей смены ее статусов
+*/
+func (m *ModExchangeRequest) GetExchangeRequestHandler(c *gin.Context) {
+	if obj := m.responser.RecordHandler(c, &models.ExchangeRequest{}); obj != nil {
+		if reflect.TypeOf(obj) != reflect.TypeOf(&models.ExchangeRequest{}) {
+			return
+		}
+
+		m.requestResponse(c, obj.(*models.ExchangeRequest))
+		return
+	}
+
+	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
+}
+
+/*
+	@Method GET
+	@Path admin/exchange-requests
+	@Type PRIVATE
+	@Documentation
+
+	Получение лимитированного объема записей из таблицы `request`.
+	Доступные фильтры: status, direction (ID направления обмена),
+	user (имя пользователя), chat_id, from и to (период создания).
+*/
+func (m *ModExchangeRequest) GetExchangeRequestsSelectionHandler(c *gin.Context) {
+	s := &models.ExchangeRequestSelection{
+		Username: c.Query("user"),
+		DateFrom: c.Query("from"),
+		DateTo:   c.Query("to"),
+	}
+
+	if c.Query("status") != "" {
+		status, err := strconv.Atoi(c.Query("status"))
+		if err != nil {
+			m.responser.Error(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		s.Status = AppType.ExchangeRequestStatus(status)
+	}
+
+	if c.Query("chat_id") != "" {
+		chatID, err := strconv.ParseInt(c.Query("chat_id"), 10, 64)
+		if err != nil {
+			m.responser.Error(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		s.ChatID = chatID
+	}
+
+	// Заявки хранят пару валют, поэтому направление
+	// обмена раскрывается в фильтр по этой паре
+	if c.Query("direction") != "" {
+		id, err := strconv.Atoi(c.Query("direction"))
+		if err != nil {
+			m.responser.Error(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		d := &models.Direction{ID: id}
+		if err := m.repository.Directions().Get(d); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
+				return
+			}
+
+			m.responser.Error(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.ExchangeFrom = d.ExchangeFrom
+		s.ExchangeTo = d.ExchangeTo
+	}
+
+	m.responser.SelectionResponse(c, m.repository.ExchangeRequest(), s)
+}
+
+/*
+	@Method PUT
+	@Path admin/exchange-request/status/:id
+	@Type PRIVATE
+	@Documentation
+
+	Ручная смена статуса заявки менеджером. Допустимы только
+	переходы разрешенные жизненным циклом заявки, переход
+	записывается в историю статусов от имени менеджера.
+*/
+func (m *ModExchangeRequest) UpdateExchangeRequestStatusHandler(c *gin.Context) {
+	var t models.ExchangeRequestTransition
+	if err := c.ShouldBindJSON(&t); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidBody)
+		return
+	}
+
+	if err := t.Validation(); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if obj := m.responser.RecordHandler(c, &models.ExchangeRequest{}); obj != nil {
+		if reflect.TypeOf(obj) != reflect.TypeOf(&models.ExchangeRequest{}) {
+			return
+		}
+
+		er := obj.(*models.ExchangeRequest)
+		if err := m.repository.ExchangeRequest().Get(er); err != nil {
+			m.responser.RecordResponse(c, nil, err)
+			return
+		}
+
+		// Извлекаю метаданные JWT
+		ctxToken := c.Request.Context().Value(guard.CtxKeyToken).(*models.AccessDetails)
+
+		h, err := er.Transition(t.Status, ctxToken.Username, t.Reason)
+		if err != nil {
+			m.responser.Error(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := m.repository.ExchangeRequest().Transition(er, h); err != nil {
+			if errors.Is(err, AppError.ErrInvalidStatusTransition) {
+				m.responser.Error(c, http.StatusUnprocessableEntity, err)
+				return
+			}
+
+			m.responser.Error(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		m.requestResponse(c, er)
+		return
+	}
+
+	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Ответ с заявкой и историей смены ее статусов
+func (m *ModExchangeRequest) requestResponse(c *gin.Context, er *models.ExchangeRequest) {
+	if err := m.repository.ExchangeRequest().Get(er); err != nil {
+		m.responser.RecordResponse(c, nil, err)
+		return
+	}
+
+	history, err := m.repository.RequestStatusHistory().GetAllByRequest(er.ID)
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"request": er,
+		"history": history,
+	})
+}
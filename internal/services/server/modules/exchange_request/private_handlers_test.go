@@ -0,0 +1,229 @@
+package exchange_request_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/mocks"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type requestBody struct {
+	Request *models.ExchangeRequest        `json:"request"`
+	History []*models.RequestStatusHistory `json:"history"`
+}
+
+func Test_Server_GetExchangeRequestHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	er, err := server.TestExchangeRequest(t, s, AppType.ExchangeRequestNew)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "invalid id",
+			id:           "invalid",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "not found",
+			id:           "100",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "valid",
+			id:           fmt.Sprint(er.ID),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/admin/exchange-request/%s", tc.id), nil)
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var body requestBody
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, er.ID, body.Request.ID)
+				assert.NotNil(t, body.History)
+			}
+		})
+	}
+}
+
+func Test_Server_GetExchangeRequestsSelectionHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	_, err = server.TestExchangeRequest(t, s, AppType.ExchangeRequestNew)
+	assert.NoError(t, err)
+	_, err = server.TestExchangeRequest(t, s, AppType.ExchangeRequestChecked)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedCount int
+	}{
+		{
+			name:          "all",
+			query:         "",
+			expectedCode:  http.StatusOK,
+			expectedCount: 2,
+		},
+		{
+			name:          "by status",
+			query:         fmt.Sprintf("?status=%d", AppType.ExchangeRequestChecked),
+			expectedCode:  http.StatusOK,
+			expectedCount: 1,
+		},
+		{
+			name:          "by user",
+			query:         fmt.Sprintf("?user=%s", mocks.USER_IN_BOT_REGISTRATION_REQ["username"]),
+			expectedCode:  http.StatusOK,
+			expectedCount: 2,
+		},
+		{
+			name:         "invalid status",
+			query:        "?status=1",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "invalid date",
+			query:        "?from=yesterday",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "invalid direction",
+			query:        "?direction=invalid",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/exchange-requests"+tc.query, nil)
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var body map[string]interface{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, float64(tc.expectedCount), body["total"])
+			}
+		})
+	}
+}
+
+func Test_Server_UpdateExchangeRequestStatusHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	er, err := server.TestExchangeRequest(t, s, AppType.ExchangeRequestChecked)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		id           string
+		payload      interface{}
+		expectedCode int
+	}{
+		{
+			name:         "invalid body",
+			id:           fmt.Sprint(er.ID),
+			payload:      "invalid",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "without reason",
+			id:   fmt.Sprint(er.ID),
+			payload: map[string]interface{}{
+				"request_status": AppType.ExchangeRequestCanceled,
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "not found",
+			id:   "100",
+			payload: map[string]interface{}{
+				"request_status": AppType.ExchangeRequestCanceled,
+				"reason":         "client asked to cancel",
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "illegal transition",
+			id:   fmt.Sprint(er.ID),
+			payload: map[string]interface{}{
+				"request_status": AppType.ExchangeRequestAwaitingConfirmation,
+				"reason":         "payout sent manually",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "valid",
+			id:   fmt.Sprint(er.ID),
+			payload: map[string]interface{}{
+				"request_status": AppType.ExchangeRequestPaid,
+				"reason":         "amount confirmed by manager",
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.payload)
+
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/admin/exchange-request/status/%s", tc.id), b)
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var body requestBody
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, AppType.ExchangeRequestPaid, body.Request.Status)
+				assert.Len(t, body.History, 1)
+				assert.Equal(t, AppType.ExchangeRequestChecked, body.History[0].StatusFrom)
+				assert.Equal(t, mocks.MANAGER_IN_ADMIN_REQ["username"], body.History[0].Actor)
+			}
+		})
+	}
+}
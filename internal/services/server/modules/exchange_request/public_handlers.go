@@ -0,0 +1,72 @@
+package exchange_request
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method PUT
+	@Path /bot/user/:chat_id/exchange-request/cancel/:id
+	@Type PUBLIC
+	@Documentation
+
+	Отмена заявки клиентом из бота. Заявку может отменить только
+	пользователь, который ее создал, переход записывается
+	в историю статусов от имени бота.
+
+	# TESTED
+*/
+func (m *ModExchangeRequest) CancelExchangeRequestHandler(c *gin.Context) {
+	chatID, err := strconv.Atoi(c.Param("chat_id"))
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidPathParams)
+		return
+	}
+
+	if obj := m.responser.RecordHandler(c, &models.ExchangeRequest{}); obj != nil {
+		if reflect.TypeOf(obj) != reflect.TypeOf(&models.ExchangeRequest{}) {
+			return
+		}
+
+		er := obj.(*models.ExchangeRequest)
+		if err := m.repository.ExchangeRequest().Get(er); err != nil {
+			m.responser.RecordResponse(c, nil, err)
+			return
+		}
+
+		// Чужая заявка для пользователя не существует
+		if er.CreatedBy.ChatID != int64(chatID) {
+			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
+			return
+		}
+
+		h, err := er.Transition(AppType.ExchangeRequestCanceled, AppType.ExchangeRequestActorBot, "canceled by user")
+		if err != nil {
+			m.responser.Error(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		if err := m.repository.ExchangeRequest().Transition(er, h); err != nil {
+			if errors.Is(err, AppError.ErrInvalidStatusTransition) {
+				m.responser.Error(c, http.StatusUnprocessableEntity, err)
+				return
+			}
+
+			m.responser.Error(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		m.requestResponse(c, er)
+		return
+	}
+
+	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
+}
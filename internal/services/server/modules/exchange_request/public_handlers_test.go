@@ -0,0 +1,77 @@
+package exchange_request_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Server_CancelExchangeRequestHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	er, err := server.TestExchangeRequest(t, s, AppType.ExchangeRequestNew)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		chatID       string
+		id           string
+		expectedCode int
+	}{
+		{
+			name:         "invalid chat id",
+			chatID:       "invalid",
+			id:           fmt.Sprint(er.ID),
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "not found",
+			chatID:       fmt.Sprint(er.CreatedBy.ChatID),
+			id:           "100",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "other user",
+			chatID:       fmt.Sprint(er.CreatedBy.ChatID + 1),
+			id:           fmt.Sprint(er.ID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "valid",
+			chatID:       fmt.Sprint(er.CreatedBy.ChatID),
+			id:           fmt.Sprint(er.ID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "already canceled",
+			chatID:       fmt.Sprint(er.CreatedBy.ChatID),
+			id:           fmt.Sprint(er.ID),
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/bot/user/%s/exchange-request/cancel/%s", tc.chatID, tc.id), nil)
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var body requestBody
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, AppType.ExchangeRequestCanceled, body.Request.Status)
+				assert.Len(t, body.History, 1)
+				assert.Equal(t, AppType.ExchangeRequestNew, body.History[0].StatusFrom)
+				assert.Equal(t, AppType.ExchangeRequestActorBot, body.History[0].Actor)
+			}
+		})
+	}
+}
@@ -1,20 +1,31 @@
 package exchanger
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// Время ожидания загрузки выгрузки, если оно не задано в конфигурации
+const defaultFeedTimeout = 10 * time.Second
+
 type ModExchanger struct {
 	store db.SQLStoreI
 	redis *redisstore.AppRedisDictionaries
 	nsq   nsqstore.NsqI
+	rp    reports.ReportsI
 	cfg   *config.Config
 
+	// Клиент для загрузки выгрузок по адресу из запроса
+	client *http.Client
+
 	responser utils.ResponserI
 	logger    utils.LoggerI
 }
@@ -26,12 +37,14 @@ type ModExchangerI interface {
 	GetExchangerByNameHandler(c *gin.Context)
 	GetExchangersSelectionHandler(c *gin.Context)
 	GetExchangerDocumentHandler(c *gin.Context)
+	TestParseExchangerHandler(c *gin.Context)
 }
 
 func InitModExchanger(
 	store db.SQLStoreI,
 	redis *redisstore.AppRedisDictionaries,
 	nsq nsqstore.NsqI,
+	rp reports.ReportsI,
 	cfg *config.Config,
 	responser utils.ResponserI,
 	l utils.LoggerI,
@@ -40,9 +53,21 @@ func InitModExchanger(
 		store: store,
 		redis: redis,
 		nsq:   nsq,
+		rp:    rp,
 		cfg:   cfg,
 
+		client: &http.Client{Timeout: feedTimeout(cfg)},
+
 		responser: responser,
 		logger:    l,
 	}
 }
+
+// Функция возвращает время ожидания загрузки выгрузки источника
+func feedTimeout(cfg *config.Config) time.Duration {
+	if cfg.Rates.Timeout > 0 {
+		return time.Duration(cfg.Rates.Timeout) * time.Second
+	}
+
+	return defaultFeedTimeout
+}
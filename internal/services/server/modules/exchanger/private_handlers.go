@@ -7,6 +7,7 @@ import (
 
 	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/feeds"
 	"github.com/gin-gonic/gin"
 )
 
@@ -128,3 +129,45 @@ func (m *ModExchanger) CreateExchangerHandler(c *gin.Context) {
 
 	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
 }
+
+/*
+	@Method POST
+	@Path admin/exchanger/test-parse
+	@Type PRIVATE
+	@Documentation
+
+	Загрузить выгрузку по адресу url и разобрать ее в формате
+	format с расположением полей mapping без сохранения источника.
+	Возвращает котировки в формате 1obmen.
+
+	# TESTED
+*/
+func (m *ModExchanger) TestParseExchangerHandler(c *gin.Context) {
+	r := &models.FeedTestParse{}
+	if err := c.ShouldBindJSON(r); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidBody)
+		return
+	}
+
+	if err := r.Validation(); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	p, err := feeds.InitExchangerParser(&models.Exchanger{Format: r.Format, Mapping: r.Mapping})
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	items, err := feeds.Fetch(c.Request.Context(), m.client, r.UrlToParse, p)
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count": len(items),
+		"items": items,
+	})
+}
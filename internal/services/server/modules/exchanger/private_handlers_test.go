@@ -327,3 +327,79 @@ func Test_Server_GetExchangersSelectionHandler(t *testing.T) {
 		})
 	}
 }
+
+func Test_Server_TestParseExchangerHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "from;to;in;out;amount\nUSDTTRC20;SBERRUB;1;75.5;100000\n")
+	}))
+	defer source.Close()
+
+	testCases := []struct {
+		name         string
+		req          map[string]interface{}
+		expectedCode int
+	}{
+		{
+			name: "invalid format",
+			req: map[string]interface{}{
+				"url":    source.URL,
+				"format": "yaml",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid mapping",
+			req: map[string]interface{}{
+				"url":     source.URL,
+				"format":  "csv",
+				"mapping": map[string]interface{}{"delimiter": ";;"},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "parse error",
+			req: map[string]interface{}{
+				"url":    source.URL,
+				"format": "xml",
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "valid",
+			req: map[string]interface{}{
+				"url":     source.URL,
+				"format":  "csv",
+				"mapping": map[string]interface{}{"delimiter": ";"},
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			json.NewEncoder(b).Encode(tc.req)
+
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/exchanger/test-parse", b)
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if rec.Code == http.StatusOK {
+				var body map[string]interface{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, float64(1), body["count"])
+			}
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package exchanger
 
 import (
-	"encoding/xml"
+	"encoding/json"
+	"errors"
 	"net/http"
 
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
-	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/valyala/fasthttp"
 )
 
 /*
@@ -25,24 +26,42 @@ func (m *ModExchanger) GetExchangerByNameHandler(c *gin.Context) {
 	m.responser.RecordResponse(c, r, m.store.AdminPanel().Exchanger().GetByName(r))
 }
 
+/*
+	@Method GET
+	@Path admin/exchanger/document
+	@Type PRIVATE
+	@Documentation
+
+	Создать задачу формирования xlsx отчета по выгрузке курсов
+	источника name (по умолчанию 1obmen). Готовый файл скачивается
+	через admin/report/:id/file
+
+	# TESTED
+*/
 func (m *ModExchanger) GetExchangerDocumentHandler(c *gin.Context) {
-	req := fasthttp.AcquireRequest()
-	req.Header.SetMethod("GET")
-	req.SetRequestURI("https://1obmen.net/request-exportxml.xml")
-	res := fasthttp.AcquireResponse()
-	if err := fasthttp.Do(req, res); err != nil {
+	params, err := json.Marshal(&models.ExchangerRatesReportParams{
+		Exchanger: c.DefaultQuery("name", "1obmen"),
+	})
+	if err != nil {
 		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
 	}
 
-	defer fasthttp.ReleaseRequest(req)
-	defer fasthttp.ReleaseResponse(res)
+	r := &models.ReportJob{Type: AppType.ReportTypeExchangerRates, Params: params}
+	if err := r.Validation(); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if err := m.rp.Enqueue(r); err != nil {
+		if errors.Is(err, AppError.ErrReportQueueFull) {
+			m.responser.Error(c, http.StatusServiceUnavailable, err)
+			return
+		}
 
-	data := models.OneObmen{}
-	if err := xml.Unmarshal(res.Body(), &data); err != nil {
 		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"file": utils.OneObmenDocumentGenerate(&data, m.cfg.Services.Server.Tmp),
-	})
+	c.JSON(http.StatusAccepted, r)
 }
@@ -1,6 +1,7 @@
 package ma
 
 import (
+	"errors"
 	"net/http"
 	"reflect"
 
@@ -40,7 +41,14 @@ func (m *ModMerchantAutoPayout) MerchantAutopayoutHandler(c *gin.Context) {
 			m.responser.UpdateRecordResponse(c, m.repository.MerchantAutopayout(), obj)
 			return
 		case http.MethodDelete:
-			m.responser.DeleteRecordResponse(c, m.repository.MerchantAutopayout(), obj)
+			// Аккаунт, через который проходили выплаты, не удаляется
+			err := m.repository.MerchantAutopayout().Delete(obj.(*models.MerchantAutopayout))
+			if errors.Is(err, AppError.ErrRecordInUse) {
+				m.responser.Error(c, http.StatusConflict, err)
+				return
+			}
+
+			m.responser.RecordResponse(c, obj, err)
 			return
 		}
 	}
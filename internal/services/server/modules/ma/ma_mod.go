@@ -3,9 +3,11 @@ package ma
 import (
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +18,9 @@ type ModMerchantAutoPayout struct {
 	nsq        nsqstore.NsqI
 	cfg        *config.Config
 	pl         *plugins.AppPlugins
+	selector   accounts.SelectorI
+	rates      rates.EngineI
+	reserve    rates.ReserveI
 
 	responser utils.ResponserI
 	logger    utils.LoggerI
@@ -40,6 +45,9 @@ func InitModMerchantAutoPayout(
 	nsq nsqstore.NsqI,
 	cfg *config.Config,
 	pl *plugins.AppPlugins,
+	sel accounts.SelectorI,
+	rt rates.EngineI,
+	rs rates.ReserveI,
 	responser utils.ResponserI,
 	l utils.LoggerI,
 ) ModMerchantAutoPayoutI {
@@ -49,7 +57,10 @@ func InitModMerchantAutoPayout(
 		nsq:        nsq,
 		cfg:        cfg,
 
-		pl: pl,
+		pl:       pl,
+		selector: sel,
+		rates:    rt,
+		reserve:  rs,
 
 		responser: responser,
 		logger:    l,
@@ -1,12 +1,12 @@
 package ma
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"reflect"
 
 	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
-	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gin-gonic/gin"
 )
@@ -85,59 +85,28 @@ func (m *ModMerchantAutoPayout) GetMerchantAutopayoutSelectionHandler(c *gin.Con
 	m.responser.SelectionResponse(c, m.repository.MerchantAutopayout(), s)
 }
 
+/*
+	@Method GET
+	@Path admin/merchant-autopayout/balance/:id
+	@Type PRIVATE
+	@Documentation
+
+	Получить баланс аккаунта
+*/
 func (m *ModMerchantAutoPayout) GetBalanceMerchantAutopayoutHandler(c *gin.Context) {
-	var r models.MerchantAutopayout
+	plugin, p, ok := m.accountPlugin(c)
+	if !ok {
+		return
+	}
 
-	if obj := m.responser.RecordHandler(c, &r); obj != nil {
-		if reflect.TypeOf(obj) != reflect.TypeOf(&models.MerchantAutopayout{}) {
-			return
-		}
-
-		// Достаю из БД нужную запись
-		if m.repository.MerchantAutopayout().Get(obj.(*models.MerchantAutopayout)) != nil {
-			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
-			return
-		}
-
-		// Декодирую опциональные параметры
-		switch obj.(*models.MerchantAutopayout).Service {
-		case AppType.MerchantAutoPayoutWhitebit:
-			p, err := m.pl.Whitebit.GetOptionParams(obj.(*models.MerchantAutopayout).Options)
-			if err != nil {
-				m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrMerchantAutopatoutOptionalParams)
-				return
-			}
-
-			// Делаю запрос на сервис мерчанта/автовыплаты
-			b, err := m.pl.Whitebit.Balance(p, map[string]interface{}{
-				"ticker": c.Query("ticker"),
-			})
-			if err != nil {
-				m.responser.Error(c, http.StatusInternalServerError, err)
-				return
-			}
-
-			var resp map[string]interface{}
-
-			if err := json.Unmarshal([]byte(b.([]byte)), &resp); err != nil {
-				m.responser.Error(c, http.StatusInternalServerError, err)
-				return
-			}
-
-			if resp["code"] != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":     AppError.ErrConnectionFailed.Error(),
-					"meta_data": resp,
-				})
-				return
-			}
-
-			c.JSON(http.StatusOK, resp)
-			return
-		}
+	// Делаю запрос на сервис мерчанта/автовыплаты
+	balances, err := plugin.Balance(c.Request.Context(), p, c.Query("ticker"))
+	if err != nil {
+		m.providerError(c, err)
+		return
 	}
 
-	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
+	c.JSON(http.StatusOK, balances)
 }
 
 /*
@@ -149,60 +118,22 @@ func (m *ModMerchantAutoPayout) GetBalanceMerchantAutopayoutHandler(c *gin.Conte
 	Получить историю транзакций аккаунта
 */
 func (m *ModMerchantAutoPayout) GetHistoryMerchantAutopayoutHandler(c *gin.Context) {
-	var r models.MerchantAutopayout
+	plugin, p, ok := m.accountPlugin(c)
+	if !ok {
+		return
+	}
 
-	if obj := m.responser.RecordHandler(c, &r); obj != nil {
-		if reflect.TypeOf(obj) != reflect.TypeOf(&models.MerchantAutopayout{}) {
-			return
-		}
-
-		// Достаю из БД нужную запись
-		if m.repository.MerchantAutopayout().Get(obj.(*models.MerchantAutopayout)) != nil {
-			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
-			return
-		}
-
-		// Декодирую опциональные параметры
-		switch obj.(*models.MerchantAutopayout).Service {
-		case AppType.MerchantAutoPayoutWhitebit:
-			p, err := m.pl.Whitebit.GetOptionParams(obj.(*models.MerchantAutopayout).Options)
-			if err != nil {
-				m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrMerchantAutopatoutOptionalParams)
-				return
-			}
-
-			// Делаю запрос на сервис мерчанта/автовыплаты
-			b, err := m.pl.Whitebit.History(p, map[string]interface{}{
-				// "transactionMethod": data.TransactionMethod,
-				"limit":  100,
-				"offset": 0,
-			})
-			if err != nil {
-				m.responser.Error(c, http.StatusInternalServerError, err)
-				return
-			}
-
-			var resp map[string]interface{}
-
-			if err := json.Unmarshal([]byte(b.([]byte)), &resp); err != nil {
-				m.responser.Error(c, http.StatusInternalServerError, err)
-				return
-			}
-
-			if resp["code"] != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":     AppError.ErrConnectionFailed.Error(),
-					"meta_data": resp,
-				})
-				return
-			}
-
-			c.JSON(http.StatusOK, resp)
-			return
-		}
+	// Делаю запрос на сервис мерчанта/автовыплаты
+	history, err := plugin.History(c.Request.Context(), p, &interfaces.HistoryQuery{
+		Limit:  100,
+		Offset: 0,
+	})
+	if err != nil {
+		m.providerError(c, err)
+		return
 	}
 
-	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
+	c.JSON(http.StatusOK, history)
 }
 
 /*
@@ -214,53 +145,73 @@ func (m *ModMerchantAutoPayout) GetHistoryMerchantAutopayoutHandler(c *gin.Conte
 	Проверить доступность аккаунта
 */
 func (m *ModMerchantAutoPayout) PingMerchantAutopayoutHandler(c *gin.Context) {
+	plugin, p, ok := m.accountPlugin(c)
+	if !ok {
+		return
+	}
+
+	// Делаю запрос на сервис мерчанта/автовыплаты
+	if err := plugin.Ping(c.Request.Context(), p); err != nil {
+		m.providerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Метод достает из БД аккаунт по ID из пути запроса, находит плагин
+// его сервиса и декодирует опциональные параметры аккаунта.
+// Если что-то пошло не так, HTTP ответ уже отправлен и ok == false.
+func (m *ModMerchantAutoPayout) accountPlugin(c *gin.Context) (plugin interfaces.PluginI, params interfaces.OptionParams, ok bool) {
 	var r models.MerchantAutopayout
-	if obj := m.responser.RecordHandler(c, &r); obj != nil {
-		if reflect.TypeOf(obj) != reflect.TypeOf(&models.MerchantAutopayout{}) {
-			return
-		}
-
-		// Достаю из БД нужную запись
-		if m.repository.MerchantAutopayout().Get(obj.(*models.MerchantAutopayout)) != nil {
-			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
-			return
-		}
-
-		// Декодирую опциональные параметры
-		switch obj.(*models.MerchantAutopayout).Service {
-		case AppType.MerchantAutoPayoutWhitebit:
-			p, err := m.pl.Whitebit.GetOptionParams(obj.(*models.MerchantAutopayout).Options)
-			if err != nil {
-				m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrMerchantAutopatoutOptionalParams)
-				return
-			}
-
-			// Делаю запрос на сервис мерчанта/автовыплаты
-			b, err := m.pl.Whitebit.Ping(p)
-			if err != nil {
-				m.responser.Error(c, http.StatusInternalServerError, err)
-				return
-			}
-
-			var resp map[string]interface{}
-
-			if err := json.Unmarshal([]byte(b.([]byte)), &resp); err != nil {
-				m.responser.Error(c, http.StatusInternalServerError, err)
-				return
-			}
-
-			if resp["code"] != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":     AppError.ErrConnectionFailed.Error(),
-					"meta_data": resp,
-				})
-				return
-			}
-
-			c.JSON(http.StatusOK, resp)
-			return
-		}
+
+	obj := m.responser.RecordHandler(c, &r)
+	if reflect.TypeOf(obj) != reflect.TypeOf(&models.MerchantAutopayout{}) {
+		return nil, nil, false
+	}
+
+	// Достаю из БД нужную запись
+	if m.repository.MerchantAutopayout().Get(&r) != nil {
+		m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
+		return nil, nil, false
+	}
+
+	plugin, err := m.pl.Get(r.Service)
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return nil, nil, false
+	}
+
+	// Декодирую опциональные параметры
+	params, err = plugin.GetOptionParams(r.Options)
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrMerchantAutopatoutOptionalParams)
+		return nil, nil, false
+	}
+
+	return plugin, params, true
+}
+
+// Метод отдает клиенту ошибку сервиса мерчанта/автовыплаты
+func (m *ModMerchantAutoPayout) providerError(c *gin.Context, err error) {
+	if pErr, ok := interfaces.AsProviderError(err); ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":     AppError.ErrConnectionFailed.Error(),
+			"meta_data": pErr,
+		})
+		return
+	}
+
+	if errors.Is(err, AppError.ErrPluginNotSupported) {
+		m.responser.Error(c, http.StatusNotImplemented, err)
+		return
 	}
 
-	m.responser.Error(c, http.StatusInternalServerError, AppError.ErrFailedToInitializeStruct)
+	m.responser.Error(c, http.StatusInternalServerError, err)
 }
@@ -195,6 +195,53 @@ func Test_Server_DeleteMerchantAutopayoutHandler(t *testing.T) {
 	}
 }
 
+/*
+	Аккаунт, через который проходили выплаты, не удаляется,
+	история выплат продолжает ссылаться на него
+*/
+func Test_Server_DeleteMerchantAutopayoutWithPayoutsHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	assert.NoError(t, server.TestMerchantAutopayout(t, s, tokens))
+
+	_, err = server.TestPayout(t, s, 1)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		method       string
+		expectedCode int
+	}{
+		{
+			name:         "account with payouts",
+			method:       http.MethodDelete,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "account kept",
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, "/api/v1/admin/merchant-autopayout/1", nil)
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
 func Test_Server_UpdateMerchantAutopayoutHandler(t *testing.T) {
 	s, redis, teardown := server.TestServer(t)
 	defer teardown(redis)
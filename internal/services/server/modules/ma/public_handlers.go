@@ -2,13 +2,15 @@ package ma
 
 import (
 	"database/sql"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 
 	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core/interfaces"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	AppValidation "github.com/gefion-tech/tg-exchanger-server/internal/core/validation"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
 	"github.com/gin-gonic/gin"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"golang.org/x/sync/errgroup"
@@ -24,6 +26,21 @@ func (m *ModMerchantAutoPayout) CreateNewAdressHandler(c *gin.Context) {
 		return
 	}
 
+	// Курс заявки всегда берется из расчета курсов,
+	// а не из переданного клиентом значения
+	rate, err := m.rates.Get(r.ExchangeFrom, r.ExchangeTo)
+	if err != nil {
+		switch err {
+		case AppError.ErrRateNotAvailable:
+			m.responser.Error(c, http.StatusNotFound, err)
+			return
+		default:
+			m.responser.Error(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	r.Course = rate.Course.String()
+
 	{
 		errs, _ := errgroup.WithContext(c)
 
@@ -35,10 +52,7 @@ func (m *ModMerchantAutoPayout) CreateNewAdressHandler(c *gin.Context) {
 			return validation.Validate(
 				c.Param("service"),
 				validation.Required,
-				validation.In(
-					AppType.MerchantAutoPayoutWhitebit,
-					AppType.MerchantAutoPayoutMine,
-				),
+				validation.In(AppValidation.StringsToInterfaces(plugins.Services())...),
 			)
 		})
 
@@ -48,56 +62,171 @@ func (m *ModMerchantAutoPayout) CreateNewAdressHandler(c *gin.Context) {
 		}
 	}
 
-	// Поиск доступного аккаунта
-	ma, err := m.repository.MerchantAutopayout().GetFistIfActive(c.Param("service"))
+	d, ok := m.direction(c, r)
+	if !ok {
+		return
+	}
+
+	currency, ok := m.currency(c, r.ExchangeFrom)
+	if !ok {
+		return
+	}
+
+	// Выплата в валюте с memo без memo клиента не дойдет до получателя
+	payoutCurrency, ok := m.currency(c, r.ExchangeTo)
+	if !ok {
+		return
+	}
+
+	if payoutCurrency.MemoRequired && r.ClientMemo == "" {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrClientMemoRequired)
+		return
+	}
+
+	if !m.checkLimits(c, d, r, rate) {
+		return
+	}
+
+	// Аккаунт мерчанта, привязанный к направлению заявки
+	ma, ok := m.merchantAccount(c, d, c.Param("service"))
+	if !ok {
+		return
+	}
+
+	plugin, err := m.pl.Get(ma.Service)
 	if err != nil {
-		switch err {
-		case sql.ErrNoRows:
-			m.responser.Error(c, http.StatusNotFound, AppError.ErrNoMerchantAutopatout)
-			return
-		default:
-			m.responser.Error(c, http.StatusInternalServerError, err)
-			return
-		}
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
 	}
 
 	// Прасинг опциональных параметров
-	p, err := m.pl.Whitebit.GetOptionParams(ma.Options)
+	p, err := plugin.GetOptionParams(ma.Options)
 	if err != nil {
 		m.responser.Error(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	var resp map[string]interface{}
+	// Повторное использование адреса освободившегося после
+	// истечения срока жизни заявки, иначе создание нового.
+	// Для валют с memo адрес всегда создается заново,
+	// в пуле memo не хранится
+	addr := &interfaces.Address{}
+	pooled := &models.PoolAddress{MaID: ma.ID, Currency: r.ExchangeFrom}
+	err = sql.ErrNoRows
+	if !currency.MemoRequired {
+		err = m.repository.AddressPool().Acquire(pooled, m.cfg.Sweeper.AddressCooldown)
+	}
 
-	switch c.Param("service") {
-	case AppType.MerchantAutoPayoutWhitebit:
-		// Получение данных адреса
-		b, err := m.pl.Whitebit.Merchant().CreateAdress(r, p.(*models.WhitebitOptionParams))
+	switch err {
+	case nil:
+		addr.Address = pooled.Address
+	case sql.ErrNoRows:
+		addr, err = plugin.Merchant().CreateAddress(c.Request.Context(), p, &interfaces.AddressRequest{
+			Currency: currency.Plugin(),
+		})
 		if err != nil {
-			m.responser.Error(c, http.StatusInternalServerError, err)
+			m.providerError(c, err)
 			return
 		}
+	default:
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
 
-		if err := json.Unmarshal(b.([]byte), &resp); err != nil {
-			m.responser.Error(c, http.StatusInternalServerError, err)
-			return
+	r.Address = addr.Address
+	r.Memo = addr.Memo
+	r.MaID = &ma.ID
+
+	// Создание заявки
+	if err := m.repository.ExchangeRequest().Create(r); err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"account": addr,
+	})
+}
+
+// Метод возвращает валюту code из каталога. Если валюты нет
+// в каталоге, HTTP ответ уже отправлен и ok == false.
+func (m *ModMerchantAutoPayout) currency(c *gin.Context, code string) (*models.Currency, bool) {
+	currency := &models.Currency{Code: code}
+	if err := m.repository.Currency().GetByCode(currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrCurrencyNotFound)
+			return nil, false
 		}
 
-		if resp["message"] != nil {
-			fmt.Println(resp["errors"])
-			c.JSON(http.StatusInternalServerError, resp)
-			return
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	return currency, true
+}
+
+// Метод возвращает активное направление заявки r. Если направление
+// не найдено или отключено, HTTP ответ уже отправлен и ok == false.
+func (m *ModMerchantAutoPayout) direction(c *gin.Context, r *models.ExchangeRequest) (*models.Direction, bool) {
+	d := &models.Direction{ExchangeFrom: r.ExchangeFrom, ExchangeTo: r.ExchangeTo}
+	if err := m.repository.Directions().GetByPair(d); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
+			return nil, false
 		}
 
-		r.Address = resp["account"].(map[string]interface{})["address"].(string)
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
 
-		// Создание заявки
-		if err := m.repository.ExchangeRequest().Create(r); err != nil {
-			m.responser.Error(c, http.StatusInternalServerError, err)
-			return
+	if !d.Status {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrDirectionDisabled)
+		return nil, false
+	}
+
+	return d, true
+}
+
+// Метод выбирает аккаунт мерчанта сервиса service среди активных
+// привязок направления d способом, заданным в направлении. Если
+// подходящего аккаунта нет, HTTP ответ уже отправлен и ok == false.
+func (m *ModMerchantAutoPayout) merchantAccount(c *gin.Context, d *models.Direction, service string) (*models.MerchantAutopayout, bool) {
+	ma, err := m.selector.Merchant(c.Request.Context(), d, service)
+	if err != nil {
+		if errors.Is(err, AppError.ErrNoMerchantAutopatout) {
+			m.responser.Error(c, http.StatusNotFound, err)
+			return nil, false
 		}
+
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	return ma, true
+}
+
+// Метод проверяет, что заявка r укладывается в границы суммы
+// и резерв направления d по курсу rate. Если заявка отклонена,
+// HTTP ответ уже отправлен и метод вернет false.
+func (m *ModMerchantAutoPayout) checkLimits(c *gin.Context, d *models.Direction, r *models.ExchangeRequest, rate *models.Rate) bool {
+	if err := d.CheckAmount(r.ExpectedAmount); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return false
+	}
+
+	if d.ReserveType == "" {
+		return true
+	}
+
+	if err := m.reserve.Fill(c.Request.Context(), d); err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return false
+	}
+
+	if err := d.CheckReserve(r.ExpectedAmount.Mul(rate.Course.Decimal)); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return false
 	}
 
-	c.JSON(http.StatusOK, resp)
+	return true
 }
@@ -4,21 +4,30 @@ import (
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
-	mine_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/mine"
-	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/health"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/listener"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/guard"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/middleware"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/bills"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/currencies"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/directions"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/exchange_request"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/exchanger"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/logs"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/ma"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/message"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/notification"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/payouts"
+	rates_mod "github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/rates"
+	reports_mod "github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/reports"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/user"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules/workers"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -32,6 +41,12 @@ type ServerModules struct {
 	logsMod      logs.ModLogsI
 	maMod        ma.ModMerchantAutoPayoutI
 	directionMod directions.ModDirectionsI
+	workersMod   workers.ModWorkersI
+	payoutsMod   payouts.ModPayoutsI
+	requestMod   exchange_request.ModExchangeRequestI
+	ratesMod     rates_mod.ModRatesI
+	reportsMod   reports_mod.ModReportsI
+	currencyMod  currencies.ModCurrenciesI
 }
 
 type ServerModulesI interface {
@@ -42,15 +57,24 @@ func InitServerModules(
 	store db.SQLStoreI,
 	redis *redisstore.AppRedisDictionaries,
 	nsq nsqstore.NsqI,
+	pl *plugins.AppPlugins,
+	sel accounts.SelectorI,
+	lsnr listener.ListenerI,
+	hc health.CheckerI,
+	rt rates.EngineI,
+	rp reports.ReportsI,
 	cfg *config.Config,
 	logger utils.LoggerI,
 	responser utils.ResponserI,
 ) ServerModulesI {
+	reserve := rates.InitReserve(store, pl, logger)
+
 	return &ServerModules{
 		exMod: exchanger.InitModExchanger(
 			store,
 			redis,
 			nsq,
+			rp,
 			cfg,
 			responser,
 			logger,
@@ -103,15 +127,22 @@ func InitServerModules(
 			redis,
 			nsq,
 			cfg,
-			plugins.InitAppPlugins(
-				mine_plugin.InitMinePlugin(),
-				whitebit_plugin.InitWhitebitPlugin(&cfg.Plugins),
-			),
+			pl,
+			sel,
+			rt,
+			reserve,
 			responser,
 			logger,
 		),
 
-		directionMod: directions.InitModDirections(store.AdminPanel(), cfg, responser),
+		directionMod: directions.InitModDirections(store.AdminPanel(), reserve, cfg, responser),
+
+		workersMod:  workers.InitModWorkers(lsnr, hc, cfg, responser),
+		payoutsMod:  payouts.InitModPayouts(store.AdminPanel().Payout(), cfg, responser),
+		requestMod:  exchange_request.InitModExchangeRequest(store.AdminPanel(), cfg, responser),
+		ratesMod:    rates_mod.InitModRates(rt, rates.InitExporter(store, rt, reserve, logger), cfg, responser),
+		reportsMod:  reports_mod.InitModReports(store.AdminPanel(), rp, cfg, responser),
+		currencyMod: currencies.InitModCurrencies(store.AdminPanel().Currency(), cfg, responser),
 	}
 }
 
@@ -124,6 +155,38 @@ func (m *ServerModules) ModulesConfigure(router *gin.RouterGroup, g guard.GuardI
 		)
 	}
 
+	// bot rates
+	{
+		router.GET(
+			"/bot/rates",
+			m.ratesMod.GetRatesHandler,
+		)
+		router.GET(
+			"/bot/rate/:from/:to",
+			m.ratesMod.GetRateHandler,
+		)
+	}
+
+	// public rates export
+	{
+		router.GET(
+			"/export/rates.xml",
+			m.ratesMod.ExportRatesXMLHandler,
+		)
+		router.GET(
+			"/export/rates.json",
+			m.ratesMod.ExportRatesJSONHandler,
+		)
+	}
+
+	// bot exchange request
+	{
+		router.PUT(
+			"/bot/user/:chat_id/exchange-request/cancel/:id",
+			m.requestMod.CancelExchangeRequestHandler,
+		)
+	}
+
 	// bot bill
 	{
 		router.GET(
@@ -285,6 +348,12 @@ func (m *ServerModules) ModulesConfigure(router *gin.RouterGroup, g guard.GuardI
 			g.IsAuth(),
 			m.exMod.GetExchangerDocumentHandler,
 		)
+		router.POST(
+			"/admin/exchanger/test-parse",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.exMod.TestParseExchangerHandler,
+		)
 		router.GET(
 			"/admin/exchangers",
 			g.AuthTokenValidation(),
@@ -293,6 +362,66 @@ func (m *ServerModules) ModulesConfigure(router *gin.RouterGroup, g guard.GuardI
 		)
 	}
 
+	// reports
+	{
+		router.POST(
+			"/admin/report",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			g.Logger(AppType.ResourceReport, AppType.ResourceCreate),
+			m.reportsMod.CreateReportHandler,
+		)
+		router.GET(
+			"/admin/report/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.reportsMod.GetReportHandler,
+		)
+		router.GET(
+			"/admin/report/:id/file",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.reportsMod.DownloadReportHandler,
+		)
+	}
+
+	// currencies
+	{
+		router.POST(
+			"/admin/currency",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			g.Logger(AppType.ResourceCurrency, AppType.ResourceCreate),
+			m.currencyMod.CreateCurrencyHandler,
+		)
+		router.PUT(
+			"/admin/currency/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			g.Logger(AppType.ResourceCurrency, AppType.ResourceUpdate),
+			m.currencyMod.UpdateCurrencyHandler,
+		)
+		router.DELETE(
+			"/admin/currency/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			g.Logger(AppType.ResourceCurrency, AppType.ResourceDelete),
+			m.currencyMod.DeleteCurrencyHandler,
+		)
+		router.GET(
+			"/admin/currency/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.currencyMod.GetCurrencyHandler,
+		)
+		router.GET(
+			"/admin/currencies",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.currencyMod.GetCurrenciesSelectionHandler,
+		)
+	}
+
 	// merchant/autopayout
 	{
 		router.POST(
@@ -417,6 +546,71 @@ func (m *ServerModules) ModulesConfigure(router *gin.RouterGroup, g guard.GuardI
 				m.directionMod.DirectionMaSelectionHandler,
 			)
 		}
+
+		// Rate history for direction
+		{
+			router.GET(
+				"/admin/direction/rate-history/:id",
+				g.AuthTokenValidation(),
+				g.IsAuth(),
+				m.directionMod.GetDirectionRateHistoryHandler,
+			)
+		}
+	}
+
+	// exchange requests
+	{
+		router.GET(
+			"/admin/exchange-request/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.requestMod.GetExchangeRequestHandler,
+		)
+		router.PUT(
+			"/admin/exchange-request/status/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			g.Logger(AppType.ResourceExchangeRequest, AppType.ResourceUpdate),
+			m.requestMod.UpdateExchangeRequestStatusHandler,
+		)
+		router.GET(
+			"/admin/exchange-requests",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.requestMod.GetExchangeRequestsSelectionHandler,
+		)
+	}
+
+	// payouts
+	{
+		router.GET(
+			"/admin/exchange-request/payouts/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.payoutsMod.GetRequestPayoutsHandler,
+		)
+		router.GET(
+			"admin/merchant-autopayout/payouts/:id",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.payoutsMod.GetMerchantAutopayoutPayoutsHandler,
+		)
+	}
+
+	// workers
+	{
+		router.GET(
+			"/admin/listener/state",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.workersMod.GetListenerStateHandler,
+		)
+		router.GET(
+			"/admin/accounts/health",
+			g.AuthTokenValidation(),
+			g.IsAuth(),
+			m.workersMod.GetAccountsHealthHandler,
+		)
 	}
 
 	// log
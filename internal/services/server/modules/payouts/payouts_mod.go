@@ -0,0 +1,32 @@
+package payouts
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModPayouts struct {
+	repository db.PayoutRepository
+	cfg        *config.Config
+
+	responser utils.ResponserI
+}
+
+type ModPayoutsI interface {
+	GetRequestPayoutsHandler(c *gin.Context)
+	GetMerchantAutopayoutPayoutsHandler(c *gin.Context)
+}
+
+func InitModPayouts(
+	r db.PayoutRepository,
+	cfg *config.Config,
+	responser utils.ResponserI,
+) ModPayoutsI {
+	return &ModPayouts{
+		repository: r,
+		cfg:        cfg,
+		responser:  responser,
+	}
+}
@@ -0,0 +1,46 @@
+package payouts
+
+import (
+	"net/http"
+	"strconv"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method GET
+	@Path admin/exchange-request/payouts/:id
+	@Type PRIVATE
+	@Documentation
+
+	Получить выборку попыток автовыплат по заявке
+*/
+func (m *ModPayouts) GetRequestPayoutsHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidPathParams)
+		return
+	}
+
+	m.responser.SelectionResponse(c, m.repository, &models.PayoutSelection{RequestID: id})
+}
+
+/*
+	@Method GET
+	@Path admin/merchant-autopayout/payouts/:id
+	@Type PRIVATE
+	@Documentation
+
+	Получить выборку попыток автовыплат отправленных через аккаунт
+*/
+func (m *ModPayouts) GetMerchantAutopayoutPayoutsHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidPathParams)
+		return
+	}
+
+	m.responser.SelectionResponse(c, m.repository, &models.PayoutSelection{MaID: id})
+}
@@ -0,0 +1,96 @@
+package rates
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method GET
+	@Path bot/rates
+	@Type PUBLIC
+	@Documentation
+
+	Получить текущие курсы всех активных направлений обмена
+
+	# TESTED
+*/
+func (m *ModRates) GetRatesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, m.rates.All())
+}
+
+/*
+	@Method GET
+	@Path bot/rate/:from/:to
+	@Type PUBLIC
+	@Documentation
+
+	Получить текущий курс направления обмена from -> to
+
+	# TESTED
+*/
+func (m *ModRates) GetRateHandler(c *gin.Context) {
+	r, err := m.rates.Get(c.Param("from"), c.Param("to"))
+	if err != nil {
+		switch err {
+		case AppError.ErrRateNotAvailable:
+			m.responser.Error(c, http.StatusNotFound, err)
+			return
+		default:
+			m.responser.Error(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, r)
+}
+
+/*
+	@Method GET
+	@Path export/rates.xml
+	@Type PUBLIC
+	@Documentation
+
+	Выгрузка курсов обменника для мониторингов в XML формате
+	<rates><item>...</item></rates>
+
+	# TESTED
+*/
+func (m *ModRates) ExportRatesXMLHandler(c *gin.Context) {
+	doc, err := m.exporter.Export(c.Request.Context(), &m.cfg.Rates)
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), b...))
+}
+
+/*
+	@Method GET
+	@Path export/rates.json
+	@Type PUBLIC
+	@Documentation
+
+	Выгрузка курсов обменника для мониторингов в JSON формате
+
+	# TESTED
+*/
+func (m *ModRates) ExportRatesJSONHandler(c *gin.Context) {
+	doc, err := m.exporter.Export(c.Request.Context(), &m.cfg.Rates)
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, doc)
+}
@@ -0,0 +1,38 @@
+package rates
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModRates struct {
+	rates    rates.EngineI
+	exporter rates.ExporterI
+	cfg      *config.Config
+
+	responser utils.ResponserI
+}
+
+type ModRatesI interface {
+	GetRatesHandler(c *gin.Context)
+	GetRateHandler(c *gin.Context)
+
+	ExportRatesXMLHandler(c *gin.Context)
+	ExportRatesJSONHandler(c *gin.Context)
+}
+
+func InitModRates(
+	r rates.EngineI,
+	e rates.ExporterI,
+	cfg *config.Config,
+	responser utils.ResponserI,
+) ModRatesI {
+	return &ModRates{
+		rates:     r,
+		exporter:  e,
+		cfg:       cfg,
+		responser: responser,
+	}
+}
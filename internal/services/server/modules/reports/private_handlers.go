@@ -0,0 +1,139 @@
+package reports
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/guard"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method POST
+	@Path admin/report
+	@Type PRIVATE
+	@Documentation
+
+	Создать задачу формирования отчета. Отчет формируется
+	в фоне, статус задачи доступен по admin/report/:id.
+	Автор задачи берется из JWT менеджера.
+
+	# TESTED
+*/
+func (m *ModReports) CreateReportHandler(c *gin.Context) {
+	r := &models.ReportJob{}
+	if err := c.ShouldBindJSON(r); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidBody)
+		return
+	}
+
+	// Извлекаю метаданные JWT
+	ctxToken := c.Request.Context().Value(guard.CtxKeyToken).(*models.AccessDetails)
+	r.CreatedBy = ctxToken.Username
+
+	if err := r.Validation(); err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if err := m.reports.Enqueue(r); err != nil {
+		if errors.Is(err, AppError.ErrReportQueueFull) {
+			m.responser.Error(c, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, r)
+}
+
+/*
+	@Method GET
+	@Path admin/report/:id
+	@Type PRIVATE
+	@Documentation
+
+	Получить статус задачи формирования отчета
+
+	# TESTED
+*/
+func (m *ModReports) GetReportHandler(c *gin.Context) {
+	r, ok := m.report(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
+
+/*
+	@Method GET
+	@Path admin/report/:id/file
+	@Type PRIVATE
+	@Documentation
+
+	Скачать готовый отчет
+
+	# TESTED
+*/
+func (m *ModReports) DownloadReportHandler(c *gin.Context) {
+	r, ok := m.report(c)
+	if !ok {
+		return
+	}
+
+	switch r.Status {
+	case AppType.ReportStatusDone:
+	case AppType.ReportStatusExpired:
+		m.responser.Error(c, http.StatusGone, AppError.ErrReportExpired)
+		return
+	default:
+		m.responser.Error(c, http.StatusConflict, AppError.ErrReportNotReady)
+		return
+	}
+
+	if _, err := os.Stat(r.File); err != nil {
+		m.responser.Error(c, http.StatusGone, AppError.ErrReportExpired)
+		return
+	}
+
+	c.FileAttachment(r.File, fmt.Sprintf("%s_%d.xlsx", r.Type, r.ID))
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Метод получает задачу по id из пути запроса,
+// при ошибке ответ клиенту уже отправлен
+func (m *ModReports) report(c *gin.Context) (*models.ReportJob, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		m.responser.Error(c, http.StatusUnprocessableEntity, AppError.ErrInvalidPathParams)
+		return nil, false
+	}
+
+	r := &models.ReportJob{ID: id}
+	if err := m.repository.ReportJob().Get(r); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			m.responser.Error(c, http.StatusNotFound, AppError.ErrRecordNotFound)
+			return nil, false
+		}
+
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	return r, true
+}
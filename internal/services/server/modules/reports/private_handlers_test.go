@@ -0,0 +1,99 @@
+package reports_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/mocks"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Server_ReportHandlers(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "invalid type",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/report",
+			body:         `{"type":"undefined"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "missing params",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/report",
+			body:         `{"type":"exchanger_rates","params":{}}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "valid",
+			method:       http.MethodPost,
+			path:         "/api/v1/admin/report",
+			body:         `{"type":"exchanger_rates","params":{"exchanger":"1obmen"},"created_by":"someone_else"}`,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/report/1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "not ready",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/report/1/file",
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "invalid id",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/report/id",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "not found",
+			method:       http.MethodGet,
+			path:         "/api/v1/admin/report/100",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokens["access_token"]))
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if rec.Code == http.StatusAccepted {
+				var body models.ReportJob
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, "pending", body.Status)
+
+				// Автор задачи берется из токена, а не из тела запроса
+				assert.Equal(t, mocks.MANAGER_IN_ADMIN_REQ["username"], body.CreatedBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package reports
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModReports struct {
+	repository db.AdminPanelRepository
+	reports    reports.ReportsI
+	cfg        *config.Config
+
+	responser utils.ResponserI
+}
+
+type ModReportsI interface {
+	CreateReportHandler(c *gin.Context)
+	GetReportHandler(c *gin.Context)
+	DownloadReportHandler(c *gin.Context)
+}
+
+func InitModReports(
+	repository db.AdminPanelRepository,
+	r reports.ReportsI,
+	cfg *config.Config,
+	responser utils.ResponserI,
+) ModReportsI {
+	return &ModReports{
+		repository: repository,
+		reports:    r,
+		cfg:        cfg,
+		responser:  responser,
+	}
+}
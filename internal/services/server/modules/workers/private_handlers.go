@@ -0,0 +1,41 @@
+package workers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	@Method GET
+	@Path admin/listener/state
+	@Type PRIVATE
+	@Documentation
+
+	Получить состояние слушателя транзакций: время последней
+	итерации, последнюю ошибку, кол-во опрашиваемых аккаунтов
+	и аккаунты, которые не удалось пропинговать
+*/
+func (m *ModWorkers) GetListenerStateHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, m.listener.Status())
+}
+
+/*
+	@Method GET
+	@Path admin/accounts/health
+	@Type PRIVATE
+	@Documentation
+
+	Получить состояние плановой проверки аккаунтов мерчантов/автовыплат
+	и результаты последних проверок: время ответа, последнюю ошибку
+	и кол-во неудачных проверок подряд
+*/
+func (m *ModWorkers) GetAccountsHealthHandler(c *gin.Context) {
+	s, err := m.health.Status()
+	if err != nil {
+		m.responser.Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
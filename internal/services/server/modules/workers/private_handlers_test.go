@@ -0,0 +1,103 @@
+package workers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Server_GetListenerStateHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		token        string
+		expectedCode int
+	}{
+		{
+			name:         "without token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "valid",
+			token:        tokens["access_token"].(string),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/listener/state", nil)
+			if tc.token != "" {
+				req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tc.token))
+			}
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var body models.ListenerStatus
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.False(t, body.Running)
+				assert.Nil(t, body.LastTick)
+			}
+		})
+	}
+}
+
+func Test_Server_GetAccountsHealthHandler(t *testing.T) {
+	s, redis, teardown := server.TestServer(t)
+	defer teardown(redis)
+
+	// Регистрирую менеджера в админке
+	tokens, err := server.TestManager(t, s)
+	assert.NotNil(t, tokens)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		token        string
+		expectedCode int
+	}{
+		{
+			name:         "without token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "valid",
+			token:        tokens["access_token"].(string),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/accounts/health", nil)
+			if tc.token != "" {
+				req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tc.token))
+			}
+			s.Router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var body models.AccountsHealthStatus
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.False(t, body.Running)
+				assert.Empty(t, body.Accounts)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package workers
+
+import (
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/health"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/listener"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ModWorkers struct {
+	listener listener.ListenerI
+	health   health.CheckerI
+	cfg      *config.Config
+
+	responser utils.ResponserI
+}
+
+type ModWorkersI interface {
+	GetListenerStateHandler(c *gin.Context)
+	GetAccountsHealthHandler(c *gin.Context)
+}
+
+func InitModWorkers(
+	l listener.ListenerI,
+	hc health.CheckerI,
+	cfg *config.Config,
+	responser utils.ResponserI,
+) ModWorkersI {
+	return &ModWorkers{
+		listener:  l,
+		health:    hc,
+		cfg:       cfg,
+		responser: responser,
+	}
+}
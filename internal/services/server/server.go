@@ -5,9 +5,14 @@ import (
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
 	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/health"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/listener"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/guard"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/middleware"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/server/modules"
@@ -30,8 +35,8 @@ type ServerI interface {
 	Create() *http.Server
 }
 
-func Init(s db.SQLStoreI, nsq nsqstore.NsqI, r *redisstore.AppRedisDictionaries, p *plugins.AppPlugins, l utils.LoggerI, c *config.Config) ServerI {
-	return root(s, nsq, r, p, l, c)
+func Init(s db.SQLStoreI, nsq nsqstore.NsqI, r *redisstore.AppRedisDictionaries, p *plugins.AppPlugins, sel accounts.SelectorI, lsnr listener.ListenerI, hc health.CheckerI, rt rates.EngineI, rp reports.ReportsI, l utils.LoggerI, c *config.Config) ServerI {
+	return root(s, nsq, r, p, sel, lsnr, hc, rt, rp, l, c)
 }
 
 func (s *Server) Create() *http.Server {
@@ -41,7 +46,7 @@ func (s *Server) Create() *http.Server {
 	}
 }
 
-func root(s db.SQLStoreI, nsq nsqstore.NsqI, r *redisstore.AppRedisDictionaries, p *plugins.AppPlugins, l utils.LoggerI, c *config.Config) *Server {
+func root(s db.SQLStoreI, nsq nsqstore.NsqI, r *redisstore.AppRedisDictionaries, p *plugins.AppPlugins, sel accounts.SelectorI, lsnr listener.ListenerI, hc health.CheckerI, rt rates.EngineI, rp reports.ReportsI, l utils.LoggerI, c *config.Config) *Server {
 	// Инициализация роутера
 	router := gin.New()
 	responser := utils.InitResponser(l)
@@ -57,7 +62,7 @@ func root(s db.SQLStoreI, nsq nsqstore.NsqI, r *redisstore.AppRedisDictionaries,
 		config:     c,
 		guard:      guard,
 		middleware: m,
-		mods:       modules.InitServerModules(s, r, nsq, c, l, responser),
+		mods:       modules.InitServerModules(s, r, nsq, p, sel, lsnr, hc, rt, rp, c, l, responser),
 	}
 
 	gin.ForceConsoleColor()
@@ -7,19 +7,30 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
 	"github.com/gefion-tech/tg-exchanger-server/internal/mocks"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 	"github.com/gefion-tech/tg-exchanger-server/internal/plugins"
-	mine_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/mine"
-	whitebit_plugin "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/accounts"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
 	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/redisstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/health"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/listener"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/rates"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/reports"
 	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
 	"github.com/stretchr/testify/assert"
+
+	// Подключение плагинов мерчантов/автовыплат
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/mine"
+	_ "github.com/gefion-tech/tg-exchanger-server/internal/plugins/whitebit"
 )
 
 /*
@@ -40,27 +51,41 @@ func TestServer(t *testing.T) (*Server, *redisstore.AppRedisDictionaries, func(*
 	rAuth, err := db.InitRedis(&config.Services.Redis, 2)
 	assert.NoError(t, err)
 
+	// Создание redis хранилища для хранения текущих курсов
+	rRates, err := db.InitRedis(&config.Services.Redis, 3)
+	assert.NoError(t, err)
+
 	AppRedis := &redisstore.AppRedisDictionaries{
 		Registration: redisstore.InitRegistrationClient(rRegistration),
 		Auth:         redisstore.InitAuthClient(rAuth),
+		Rates:        redisstore.InitRatesClient(rRates),
 	}
 
 	// Инициализация соединения с NSQ
 	producer, err := db.InitNSQ(&config.Services.NSQ)
 	assert.NoError(t, err)
 
-	logger := utils.InitLogger(mocksqlstore.Init().AdminPanel().Logs())
-	plugins := plugins.InitAppPlugins(
-		mine_plugin.InitMinePlugin(),
-		whitebit_plugin.InitWhitebitPlugin(&config.Plugins),
-	)
+	store := mocksqlstore.Init()
+	nsq := nsqstore.Init(producer)
+
+	logger := utils.InitLogger(store.AdminPanel().Logs())
+	plugins := plugins.InitAppPlugins(&config.Plugins)
 
-	return root(mocksqlstore.Init(), nsqstore.Init(producer), AppRedis, plugins, logger, config), AppRedis, func(appRedis *redisstore.AppRedisDictionaries) {
+	sel := accounts.InitSelector(store, plugins, &config.Accounts, logger)
+	lsnr := listener.InitListener(store, nsq, plugins, sel, logger)
+	hc := health.InitChecker(store, nsq, plugins, logger)
+	rt := rates.InitEngine(store, AppRedis.Rates, nsq, logger)
+	rp := reports.InitReports(store, filepath.Join(t.TempDir(), "reports"), logger)
+
+	return root(store, nsq, AppRedis, plugins, sel, lsnr, hc, rt, rp, logger, config), AppRedis, func(appRedis *redisstore.AppRedisDictionaries) {
 		appRedis.Registration.Clear()
 		appRedis.Registration.Close()
 
 		appRedis.Auth.Clear()
 		appRedis.Auth.Close()
+
+		appRedis.Rates.Clear()
+		appRedis.Rates.Close()
 	}
 }
 
@@ -115,6 +140,53 @@ func TestMerchantAutopayout(t *testing.T, s *Server, tokens map[string]interface
 	return nil
 }
 
+func TestExchangeRequest(t *testing.T, s *Server, status AppType.ExchangeRequestStatus) (*models.ExchangeRequest, error) {
+	t.Helper()
+
+	er := &models.ExchangeRequest{
+		Status:         status,
+		ExchangeFrom:   "USDTTRC20",
+		ExchangeTo:     "BTC",
+		Course:         "1",
+		Address:        "address",
+		ClientAddress:  "client_address",
+		ExpectedAmount: AppMoney.NewFromInt(100),
+		CreatedBy: models.UserFromBotRequest{
+			ChatID:   int64(mocks.USER_IN_BOT_REGISTRATION_REQ["chat_id"].(int)),
+			Username: mocks.USER_IN_BOT_REGISTRATION_REQ["username"].(string),
+		},
+	}
+
+	if err := s.store.AdminPanel().ExchangeRequest().Create(er); err != nil {
+		return nil, err
+	}
+
+	return er, nil
+}
+
+func TestPayout(t *testing.T, s *Server, maID int) (*models.Payout, error) {
+	t.Helper()
+
+	er, err := TestExchangeRequest(t, s, AppType.ExchangeRequestPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &models.Payout{
+		RequestID: er.ID,
+		MaID:      maID,
+		Ticker:    "BTC",
+		Amount:    er.ExpectedAmount,
+		Status:    AppType.PayoutSent,
+	}
+
+	if err := s.store.AdminPanel().Payout().Create(p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
 func TestLogRecord(t *testing.T, s *Server) error {
 	t.Helper()
 
@@ -322,3 +394,9 @@ func TestUserBill(t *testing.T, s *Server, tokens map[string]interface{}) error
 	s.Router.ServeHTTP(rec, req)
 	return nil
 }
+
+func TestRateHistory(t *testing.T, s *Server, arr []*models.RateHistory) error {
+	t.Helper()
+
+	return s.store.AdminPanel().RateHistory().Create(arr)
+}
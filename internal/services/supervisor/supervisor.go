@@ -0,0 +1,158 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Задержки перезапуска по умолчанию
+const (
+	DefaultRestartDelay    = time.Second
+	DefaultMaxRestartDelay = time.Minute
+)
+
+type Supervisor struct {
+	mu    sync.RWMutex
+	state models.WorkerState
+
+	restartDelay    time.Duration
+	maxRestartDelay time.Duration
+
+	logger utils.LoggerI
+}
+
+type SupervisorI interface {
+	Run(ctx context.Context, fn func(ctx context.Context) error)
+	Tick()
+	State() models.WorkerState
+}
+
+func Init(name string, restartDelay, maxRestartDelay time.Duration, l utils.LoggerI) SupervisorI {
+	if restartDelay <= 0 {
+		restartDelay = DefaultRestartDelay
+	}
+
+	if maxRestartDelay < restartDelay {
+		maxRestartDelay = DefaultMaxRestartDelay
+	}
+
+	return &Supervisor{
+		state: models.WorkerState{
+			Name: name,
+		},
+		restartDelay:    restartDelay,
+		maxRestartDelay: maxRestartDelay,
+		logger:          l,
+	}
+}
+
+// Метод запускает fn и перезапускает его с экспоненциальной задержкой
+// после каждой ошибки или паники. Возвращает управление только после
+// отмены контекста ctx.
+func (s *Supervisor) Run(ctx context.Context, fn func(ctx context.Context) error) {
+	delay := s.restartDelay
+
+	for {
+		started := time.Now()
+
+		s.setRunning(true)
+		err := s.call(ctx, fn)
+		s.setRunning(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			err = AppError.ErrWorkerStopped
+		}
+
+		s.fail(err)
+
+		// Если процесс проработал дольше максимальной задержки,
+		// считаю его восстановившимся и сбрасываю задержку
+		if time.Since(started) > s.maxRestartDelay {
+			delay = s.restartDelay
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		delay *= 2
+		if delay > s.maxRestartDelay {
+			delay = s.maxRestartDelay
+		}
+
+		s.mu.Lock()
+		s.state.Restarts++
+		s.mu.Unlock()
+	}
+}
+
+// Отметка об успешно завершенной итерации процесса
+func (s *Supervisor) Tick() {
+	t := time.Now().UTC().Format(core.DateStandart)
+
+	s.mu.Lock()
+	s.state.LastTick = &t
+	s.mu.Unlock()
+}
+
+// Метод возвращает копию текущего состояния процесса
+func (s *Supervisor) State() models.WorkerState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.state
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (s *Supervisor) call(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+func (s *Supervisor) setRunning(running bool) {
+	s.mu.Lock()
+	s.state.Running = running
+	s.mu.Unlock()
+}
+
+func (s *Supervisor) fail(err error) {
+	t := time.Now().UTC().Format(core.DateStandart)
+	e := err.Error()
+
+	s.mu.Lock()
+	s.state.LastError = &e
+	s.state.LastErrorAt = &t
+	s.mu.Unlock()
+
+	s.logger.NewRecord(&models.LogRecord{
+		Service: AppType.LogTypeServer,
+		Module:  s.state.Name,
+		Info:    fmt.Sprintf("worker restart after error: %s", e),
+	})
+}
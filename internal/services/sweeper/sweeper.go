@@ -0,0 +1,147 @@
+package sweeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppError "github.com/gefion-tech/tg-exchanger-server/internal/core/errors"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/nsqstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/supervisor"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+)
+
+// Процесс переводящий брошенные клиентами заявки в статус
+// ExchangeRequestDeleted по истечении их срока жизни
+type Sweeper struct {
+	store  db.SQLStoreI
+	nsq    nsqstore.NsqI
+	logger utils.LoggerI
+
+	supervisor supervisor.SupervisorI
+}
+
+type SweeperI interface {
+	Sweep(ctx context.Context, cfg *config.SweeperConfig) error
+	Supervise(ctx context.Context, cfg *config.SweeperConfig)
+}
+
+func InitSweeper(s db.SQLStoreI, q nsqstore.NsqI, l utils.LoggerI) SweeperI {
+	return &Sweeper{
+		store:  s,
+		nsq:    q,
+		logger: l,
+
+		supervisor: supervisor.Init(AppType.LogModuleSweeper, 0, 0, l),
+	}
+}
+
+// Метод запускает периодическую проверку заявок под наблюдением
+// супервизора. Блокирует выполнение до отмены контекста ctx.
+func (s *Sweeper) Supervise(ctx context.Context, cfg *config.SweeperConfig) {
+	s.supervisor.Run(ctx, func(ctx context.Context) error {
+		for {
+			t := time.NewTimer(time.Duration(cfg.Interval) * time.Second)
+
+			if err := s.Sweep(ctx, cfg); err != nil {
+				t.Stop()
+				return err
+			}
+			s.supervisor.Tick()
+
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil
+			case <-t.C:
+			}
+		}
+	})
+}
+
+// Одна итерация проверки. Каждая просроченная заявка переводится
+// в статус ExchangeRequestDeleted, клиент получает уведомление,
+// а адрес для приема средств возвращается в пул.
+func (s *Sweeper) Sweep(ctx context.Context, cfg *config.SweeperConfig) error {
+	arr, err := s.store.AdminPanel().ExchangeRequest().GetAllExpired(cfg.RequestTTL)
+	if err != nil {
+		return err
+	}
+
+	for _, er := range arr {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := s.expire(er); err != nil {
+			s.logger.NewRecord(&models.LogRecord{
+				Service: AppType.LogTypeServer,
+				Module:  AppType.LogModuleSweeper,
+				Info:    fmt.Sprintf("request %d: %s", er.ID, err.Error()),
+			})
+		}
+	}
+
+	return nil
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+func (s *Sweeper) expire(er *models.ExchangeRequest) error {
+	h, err := er.Transition(AppType.ExchangeRequestDeleted, AppType.ExchangeRequestActorSweeper, "request ttl expired")
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.AdminPanel().ExchangeRequest().Transition(er, h); err != nil {
+		// Статус заявки успел измениться, например поступила оплата
+		if errors.Is(err, AppError.ErrInvalidStatusTransition) {
+			return nil
+		}
+
+		return err
+	}
+
+	// Адрес свободен и может быть выдан по следующей заявке
+	if er.MaID != nil {
+		if err := s.store.AdminPanel().AddressPool().Release(&models.PoolAddress{
+			MaID:     *er.MaID,
+			Currency: er.ExchangeFrom,
+			Address:  er.Address,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return s.notify(er.CreatedBy)
+}
+
+func (s *Sweeper) notify(u models.UserFromBotRequest) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"to": map[string]interface{}{
+			"chat_id":  u.ChatID,
+			"username": u.Username,
+		},
+		"message": map[string]interface{}{
+			"type": AppType.QueueEventExchangeError,
+			"text": "❗️Отмена операции обмена❗️\n\nСрок оплаты заявки истек. Если вы все еще хотите совершить обмен, создайте новую заявку.",
+		},
+		"created_at": time.Now().UTC().Format(core.DateStandart),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.nsq.Publish(AppType.TopicBotMessages, payload)
+}
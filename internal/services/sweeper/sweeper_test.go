@@ -0,0 +1,145 @@
+package sweeper_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gefion-tech/tg-exchanger-server/internal/config"
+	"github.com/gefion-tech/tg-exchanger-server/internal/core"
+	AppMoney "github.com/gefion-tech/tg-exchanger-server/internal/core/money"
+	AppType "github.com/gefion-tech/tg-exchanger-server/internal/core/types"
+	"github.com/gefion-tech/tg-exchanger-server/internal/models"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/db/mocksqlstore"
+	"github.com/gefion-tech/tg-exchanger-server/internal/services/sweeper"
+	"github.com/gefion-tech/tg-exchanger-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Sweeper_Sweep(t *testing.T) {
+	cfg := config.InitTestConfig(t)
+	cfg.Sweeper.RequestTTL = 20
+
+	store := mocksqlstore.Init()
+	nsq := &testNsq{}
+	swpr := sweeper.InitSweeper(store, nsq, utils.InitLogger(store.AdminPanel().Logs()))
+
+	// Направление с коротким и с длинным сроком жизни заявок
+	assert.NoError(t, store.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom: "USDTTRC20",
+		ExchangeTo:   "SBERRUB",
+		RequestTTL:   10,
+	}))
+	assert.NoError(t, store.AdminPanel().Directions().Create(&models.Direction{
+		ExchangeFrom: "BTC",
+		ExchangeTo:   "SBERRUB",
+		RequestTTL:   60,
+	}))
+
+	maID := 1
+	expired := testRequest(t, store, "USDTTRC20", "SBERRUB", "address-1", &maID, AppType.ExchangeRequestNew, 30*time.Minute)
+	alive := testRequest(t, store, "BTC", "SBERRUB", "address-2", &maID, AppType.ExchangeRequestNew, 30*time.Minute)
+	byDefault := testRequest(t, store, "ETH", "SBERRUB", "address-3", nil, AppType.ExchangeRequestNew, 30*time.Minute)
+	fresh := testRequest(t, store, "ETH", "SBERRUB", "address-4", nil, AppType.ExchangeRequestNew, time.Minute)
+	paid := testRequest(t, store, "USDTTRC20", "SBERRUB", "address-5", &maID, AppType.ExchangeRequestPaid, 30*time.Minute)
+
+	assert.NoError(t, swpr.Sweep(context.Background(), &cfg.Sweeper))
+
+	testCases := []struct {
+		name     string
+		id       int
+		expected AppType.ExchangeRequestStatus
+	}{
+		{name: "direction ttl expired", id: expired, expected: AppType.ExchangeRequestDeleted},
+		{name: "direction ttl not expired", id: alive, expected: AppType.ExchangeRequestNew},
+		{name: "default ttl expired", id: byDefault, expected: AppType.ExchangeRequestDeleted},
+		{name: "default ttl not expired", id: fresh, expected: AppType.ExchangeRequestNew},
+		{name: "already paid", id: paid, expected: AppType.ExchangeRequestPaid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			er := &models.ExchangeRequest{ID: tc.id}
+			assert.NoError(t, store.AdminPanel().ExchangeRequest().Get(er))
+			assert.Equal(t, tc.expected, er.Status)
+
+			history, err := store.AdminPanel().RequestStatusHistory().GetAllByRequest(tc.id)
+			assert.NoError(t, err)
+			if tc.expected == AppType.ExchangeRequestDeleted {
+				assert.Len(t, history, 1)
+				assert.Equal(t, AppType.ExchangeRequestActorSweeper, history[0].Actor)
+			} else {
+				assert.Empty(t, history)
+			}
+		})
+	}
+
+	// Клиенты получили уведомления об отмене заявок
+	assert.Len(t, nsq.messages(), 2)
+
+	// Адрес просроченной заявки не выдается повторно до
+	// окончания времени ожидания запоздавших платежей
+	a := &models.PoolAddress{MaID: maID, Currency: "USDTTRC20"}
+	assert.Equal(t, sql.ErrNoRows, store.AdminPanel().AddressPool().Acquire(a, cfg.Sweeper.AddressCooldown))
+
+	// Адрес просроченной заявки доступен для повторной выдачи
+	assert.NoError(t, store.AdminPanel().AddressPool().Acquire(a, 0))
+	assert.Equal(t, "address-1", a.Address)
+
+	// Повторный проход ничего не меняет
+	assert.NoError(t, swpr.Sweep(context.Background(), &cfg.Sweeper))
+	assert.Len(t, nsq.messages(), 2)
+}
+
+/*
+	==========================================================================================
+	ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ
+	==========================================================================================
+*/
+
+// Имитация NSQ, сохраняющая все отправленные сообщения
+type testNsq struct {
+	mu  sync.Mutex
+	arr [][]byte
+}
+
+func (n *testNsq) Publish(topic string, payload []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.arr = append(n.arr, payload)
+	return nil
+}
+
+func (n *testNsq) messages() [][]byte {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([][]byte{}, n.arr...)
+}
+
+func testRequest(t *testing.T, store db.SQLStoreI, from, to, address string, maID *int, status AppType.ExchangeRequestStatus, age time.Duration) int {
+	t.Helper()
+
+	er := &models.ExchangeRequest{
+		Status:         status,
+		ExchangeFrom:   from,
+		ExchangeTo:     to,
+		Course:         "1",
+		Address:        address,
+		ClientAddress:  "client_address",
+		ExpectedAmount: AppMoney.NewFromInt(100),
+		MaID:           maID,
+		CreatedBy: models.UserFromBotRequest{
+			ChatID:   1,
+			Username: "client",
+		},
+		CreatedAt: time.Now().UTC().Add(-age).Format(core.DateStandart),
+	}
+	assert.NoError(t, store.AdminPanel().ExchangeRequest().Create(er))
+
+	return er.ID
+}
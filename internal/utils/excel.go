@@ -1,16 +1,14 @@
 package utils
 
 import (
-	"fmt"
 	"strconv"
-	"time"
 
 	excel "github.com/I0HuKc/go-excel"
-	"github.com/gefion-tech/tg-exchanger-server/internal/core"
 	"github.com/gefion-tech/tg-exchanger-server/internal/models"
 )
 
-func OneObmenDocumentGenerate(data *models.OneObmen, path string) string {
+// Функция формирует xlsx документ с курсами data в файле f
+func OneObmenDocumentGenerate(data *models.OneObmen, f string) error {
 	tableStartFromLine := 1
 	headValues := []string{"№", "From", "To", "In", "Out", "Amount", "MinAmount", "MaxAmount"}
 
@@ -47,18 +45,19 @@ func OneObmenDocumentGenerate(data *models.OneObmen, path string) string {
 			i + 1,
 			v.From,
 			v.To,
-			v.In,
-			v.Out,
-			v.Amount,
+			v.In.InexactFloat64(),
+			v.Out.InexactFloat64(),
+			v.Amount.InexactFloat64(),
 			v.MinAmount,
 			v.MaxAmount,
 		})
 	}
 
-	f := fmt.Sprintf("%s/file/%s.xlsx", path, time.Now().UTC().Format(core.DateStandart))
-	excel.NewFile(f)
+	if err := excel.NewFile(f); err != nil {
+		return err
+	}
 
-	excel.CreateDefaultTable(excel.DefaultTable{
+	return excel.CreateDefaultTable(excel.DefaultTable{
 		PathName:         f,
 		TableHeader:      tableHeader,
 		Data:             tableValue, // Data array written to the table
@@ -66,6 +65,4 @@ func OneObmenDocumentGenerate(data *models.OneObmen, path string) string {
 		ContentRowHeight: 18,
 		ContentLineStart: tableStartFromLine + 1,
 	})
-
-	return f
 }
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS payouts(
+    id BIGSERIAL PRIMARY KEY,
+    request_id BIGINT REFERENCES request(id) ON DELETE CASCADE NOT NULL,
+    ma_id BIGINT REFERENCES merchant_autopayout(id) ON DELETE RESTRICT NOT NULL,
+    amount DECIMAL NOT NULL,
+    payout_status INT NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS payouts_request_id_idx ON payouts(request_id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS payouts_ma_id_idx;
+
+ALTER TABLE payouts
+    DROP COLUMN IF EXISTS ticker,
+    DROP COLUMN IF EXISTS network,
+    DROP COLUMN IF EXISTS raw_response,
+    DROP COLUMN IF EXISTS error_text;
\ No newline at end of file
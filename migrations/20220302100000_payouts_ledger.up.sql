@@ -0,0 +1,7 @@
+ALTER TABLE payouts
+    ADD COLUMN IF NOT EXISTS ticker VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS network VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS raw_response TEXT,
+    ADD COLUMN IF NOT EXISTS error_text TEXT;
+
+CREATE INDEX IF NOT EXISTS payouts_ma_id_idx ON payouts(ma_id);
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS request_status_history(
+    id BIGSERIAL PRIMARY KEY,
+    request_id BIGINT REFERENCES request(id) ON DELETE CASCADE NOT NULL,
+    status_from INT NOT NULL,
+    status_to INT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS request_status_history_request_id_idx ON request_status_history(request_id);
\ No newline at end of file
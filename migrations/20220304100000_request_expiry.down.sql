@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS address_pool;
+ALTER TABLE request DROP COLUMN IF EXISTS ma_id;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS request_ttl;
\ No newline at end of file
@@ -0,0 +1,12 @@
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS request_ttl INT NOT NULL DEFAULT 0;
+ALTER TABLE request ADD COLUMN IF NOT EXISTS ma_id BIGINT REFERENCES merchant_autopayout(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS address_pool(
+    id BIGSERIAL PRIMARY KEY,
+    ma_id BIGINT REFERENCES merchant_autopayout(id) ON DELETE CASCADE NOT NULL,
+    currency VARCHAR(20) NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+
+    UNIQUE (ma_id, currency, address)
+);
\ No newline at end of file
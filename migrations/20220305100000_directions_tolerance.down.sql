@@ -0,0 +1,2 @@
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS tolerance;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS tolerance_type;
\ No newline at end of file
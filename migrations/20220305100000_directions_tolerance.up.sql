@@ -0,0 +1,2 @@
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS tolerance_type VARCHAR(20) NOT NULL DEFAULT 'absolute';
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS tolerance DECIMAL NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS rate_history(
+    id BIGSERIAL PRIMARY KEY,
+    direction_id BIGINT REFERENCES exchange_directions(id) ON DELETE CASCADE NOT NULL,
+    exchanger_id BIGINT REFERENCES exchangers(id) ON DELETE CASCADE NOT NULL,
+    source_in DECIMAL NOT NULL,
+    source_out DECIMAL NOT NULL,
+    course_correction INT NOT NULL,
+    rate_in DECIMAL NOT NULL,
+    rate_out DECIMAL NOT NULL,
+    reference BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS rate_history_direction_created_at_idx ON rate_history(direction_id, created_at);
+CREATE INDEX IF NOT EXISTS rate_history_created_at_idx ON rate_history(created_at);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS correction_max;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS correction_min;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS target_position;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS auto_pricing;
\ No newline at end of file
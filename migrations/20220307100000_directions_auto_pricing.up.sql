@@ -0,0 +1,4 @@
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS auto_pricing BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS target_position INT NOT NULL DEFAULT 0;
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS correction_min INT NOT NULL DEFAULT 0;
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS correction_max INT NOT NULL DEFAULT 0;
\ No newline at end of file
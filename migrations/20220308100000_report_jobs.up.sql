@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS report_jobs(
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    params JSONB NOT NULL DEFAULT '{}',
+    file VARCHAR(255) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS report_jobs_status_updated_at_idx ON report_jobs(status, updated_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE exchangers DROP COLUMN IF EXISTS mapping;
+ALTER TABLE exchangers DROP COLUMN IF EXISTS format;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE exchangers ADD COLUMN IF NOT EXISTS format VARCHAR(20) NOT NULL DEFAULT 'xml';
+ALTER TABLE exchangers ADD COLUMN IF NOT EXISTS mapping JSONB NOT NULL DEFAULT '{}';
\ No newline at end of file
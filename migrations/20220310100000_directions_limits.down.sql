@@ -0,0 +1,4 @@
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS reserve;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS reserve_type;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS max_amount;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS min_amount;
\ No newline at end of file
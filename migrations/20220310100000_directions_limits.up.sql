@@ -0,0 +1,4 @@
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS min_amount DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS max_amount DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS reserve_type VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS reserve DECIMAL NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE directions_ma DROP COLUMN IF EXISTS weight;
+ALTER TABLE directions_ma DROP COLUMN IF EXISTS priority;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS autopayout_policy;
+ALTER TABLE exchange_directions DROP COLUMN IF EXISTS merchant_policy;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS merchant_policy VARCHAR(20) NOT NULL DEFAULT 'priority';
+ALTER TABLE exchange_directions ADD COLUMN IF NOT EXISTS autopayout_policy VARCHAR(20) NOT NULL DEFAULT 'priority';
+ALTER TABLE directions_ma ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+ALTER TABLE directions_ma ADD COLUMN IF NOT EXISTS weight INT NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS history_cursors;
\ No newline at end of file
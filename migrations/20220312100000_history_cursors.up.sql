@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS history_cursors(
+    ma_id BIGINT PRIMARY KEY REFERENCES merchant_autopayout(id) ON DELETE CASCADE,
+    created_at BIGINT NOT NULL DEFAULT 0,
+    seen JSONB NOT NULL DEFAULT '[]',
+    pending JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS account_health;
\ No newline at end of file
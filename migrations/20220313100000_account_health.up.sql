@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS account_health(
+    ma_id BIGINT PRIMARY KEY REFERENCES merchant_autopayout(id) ON DELETE CASCADE,
+    alive BOOLEAN NOT NULL DEFAULT false,
+    latency BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_error_at TIMESTAMP,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    checks INT NOT NULL DEFAULT 0,
+    failures INT NOT NULL DEFAULT 0,
+    last_success_at TIMESTAMP,
+    checked_at TIMESTAMP DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE exchange_directions DROP CONSTRAINT IF EXISTS exchange_directions_exchange_to_fkey;
+ALTER TABLE exchange_directions DROP CONSTRAINT IF EXISTS exchange_directions_exchange_from_fkey;
+DROP TABLE IF EXISTS currencies;
\ No newline at end of file
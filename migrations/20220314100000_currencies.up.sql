@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS currencies(
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    ticker VARCHAR(20) NOT NULL,
+    network VARCHAR(20) NOT NULL DEFAULT '',
+    decimals INT NOT NULL DEFAULT 8,
+    memo_required BOOLEAN NOT NULL DEFAULT false,
+    min_deposit DECIMAL NOT NULL DEFAULT 0,
+    min_withdraw DECIMAL NOT NULL DEFAULT 0,
+    provider_codes JSONB NOT NULL DEFAULT '{}',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+-- Валюты, которые раньше определялись разбором кода. USDT без сети
+-- выплачивался в сети TRC20, поэтому сеть сохраняется
+INSERT INTO currencies(code, ticker, network, decimals) VALUES
+    ('BTC', 'BTC', '', 8),
+    ('ETH', 'ETH', '', 8),
+    ('TRX', 'TRX', '', 6),
+    ('USDT', 'USDT', 'TRC20', 6),
+    ('USDTTRC20', 'USDT', 'TRC20', 6),
+    ('USDTERC20', 'USDT', 'ERC20', 6),
+    ('USDTOMNI', 'USDT', 'OMNI', 8),
+    ('SBERRUB', 'RUB', '', 2)
+ON CONFLICT (code) DO NOTHING;
+
+-- Остальные валюты существующих направлений, фиатные
+-- платежные системы заканчиваются на код валюты
+INSERT INTO currencies(code, ticker, decimals)
+SELECT code, code, CASE WHEN code ~ '(RUB|USD|EUR|UAH|KZT)$' THEN 2 ELSE 8 END
+FROM (
+    SELECT exchange_from AS code FROM exchange_directions
+    UNION
+    SELECT exchange_to AS code FROM exchange_directions
+) AS codes
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE exchange_directions ADD CONSTRAINT exchange_directions_exchange_from_fkey FOREIGN KEY (exchange_from) REFERENCES currencies(code);
+ALTER TABLE exchange_directions ADD CONSTRAINT exchange_directions_exchange_to_fkey FOREIGN KEY (exchange_to) REFERENCES currencies(code);
\ No newline at end of file
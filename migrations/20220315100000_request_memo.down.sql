@@ -0,0 +1,2 @@
+ALTER TABLE request DROP COLUMN IF EXISTS client_memo;
+ALTER TABLE request DROP COLUMN IF EXISTS memo;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE request ADD COLUMN IF NOT EXISTS memo VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE request ADD COLUMN IF NOT EXISTS client_memo VARCHAR(255) NOT NULL DEFAULT '';
\ No newline at end of file
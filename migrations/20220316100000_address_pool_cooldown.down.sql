@@ -0,0 +1 @@
+ALTER TABLE address_pool DROP COLUMN IF EXISTS released_at;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE address_pool ADD COLUMN IF NOT EXISTS released_at TIMESTAMP NOT NULL DEFAULT now();
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE history_cursors DROP COLUMN IF EXISTS gap_offset;
+ALTER TABLE history_cursors DROP COLUMN IF EXISTS ceiling;
+ALTER TABLE history_cursors DROP COLUMN IF EXISTS head;
\ No newline at end of file
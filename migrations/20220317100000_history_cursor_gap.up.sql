@@ -0,0 +1,3 @@
+ALTER TABLE history_cursors ADD COLUMN IF NOT EXISTS gap_offset INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE history_cursors ADD COLUMN IF NOT EXISTS ceiling BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE history_cursors ADD COLUMN IF NOT EXISTS head BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file